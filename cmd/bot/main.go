@@ -6,6 +6,8 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	// Schedules of strategies are set in IANA time zones, which the scratch image doesn't ship.
+	_ "time/tzdata"
 
 	"github.com/ksysoev/deriv-bot/pkg/cmd"
 )
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/api/docs"
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
+	"github.com/ksysoev/deriv-bot/pkg/core/executor"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	readHeaderTimeout = 5 * time.Second
+	shutdownTimeout   = 10 * time.Second
+)
+
+type Config struct {
+	Listen string `mapstructure:"listen"`
+	// Token names the entry of tokens holding the bearer token of control requests, it's resolved by the caller.
+	Token string `mapstructure:"token"`
+}
+
+type Executor interface {
+	Strategies() []executor.StrategyStatus
+	Strategy(name string) (executor.StrategyStatus, error)
+	StartStrategy(name string) error
+	StopStrategy(name string) error
+	PauseStrategy(name string) error
+	ResumeStrategy(name string) error
+	Positions() []executor.OpenPosition
+	ClosePosition(ctx context.Context, contractID int) error
+	Contracts(ctx context.Context) ([]executor.Contract, error)
+	Balances(ctx context.Context) ([]executor.Balance, error)
+	Trades(since time.Time) []executor.ClosedTrade
+}
+
+type EventSubscriber interface {
+	Subscribe(types ...event.Type) (<-chan event.Event, func())
+}
+
+type Service struct {
+	exec   Executor
+	events EventSubscriber
+	token  string
+	cfg    Config
+}
+
+// New creates a new HTTP control API service.
+// cfg defines the address to listen on, token is the bearer token required by requests changing the bot state,
+// exec provides the strategy control operations, and events provides the stream of bot events for live dashboards.
+// Such requests are rejected when token is empty.
+// Returns a pointer to the initialized Service.
+func New(cfg Config, token string, exec Executor, events EventSubscriber) *Service {
+	return &Service{
+		cfg:    cfg,
+		token:  token,
+		exec:   exec,
+		events: events,
+	}
+}
+
+//	@title						Deriv Bot control API
+//	@version					1.0
+//	@description				API for managing trading strategies of a running bot.
+//	@BasePath					/
+//	@securityDefinitions.apikey	BearerAuth
+//	@in							header
+//	@name						Authorization
+//	@description				Bearer token configured with api.token, required by requests changing the bot state.
+
+// Run starts the HTTP server and blocks until ctx is cancelled, then shuts the server down gracefully.
+// Returns an error if the server fails to listen or stops unexpectedly.
+func (s *Service) Run(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.cfg.Listen,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: readHeaderTimeout,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		slog.Info("Control API started", slog.String("listen", s.cfg.Listen))
+
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("failed to run control API: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shutdown control API: %w", err)
+	}
+
+	return nil
+}
+
+// Handler returns the HTTP handler serving the control API routes.
+// Routes changing the bot state require the bearer token, read-only routes are served without it.
+func (s *Service) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /strategies", s.listStrategies)
+	mux.HandleFunc("GET /strategies/{name}", s.getStrategy)
+	mux.HandleFunc("POST /strategies/{name}/start", s.authorized(s.startStrategy))
+	mux.HandleFunc("POST /strategies/{name}/stop", s.authorized(s.stopStrategy))
+	mux.HandleFunc("POST /strategies/{name}/pause", s.authorized(s.pauseStrategy))
+	mux.HandleFunc("POST /strategies/{name}/resume", s.authorized(s.resumeStrategy))
+	mux.HandleFunc("GET /positions", s.listPositions)
+	mux.HandleFunc("POST /positions/{contract_id}/close", s.authorized(s.closePosition))
+	mux.HandleFunc("GET /contracts", s.listContracts)
+	mux.HandleFunc("GET /balance", s.listBalances)
+	mux.HandleFunc("GET /report", s.getReport)
+	mux.HandleFunc("GET /events", s.streamEvents)
+	mux.HandleFunc("GET /openapi.json", s.openAPISpec)
+	mux.Handle("GET /metrics", promhttp.Handler())
+
+	return mux
+}
+
+// authorized wraps next, rejecting requests without the bearer token of the service with 401 Unauthorized.
+func (s *Service) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		if !ok || s.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeJSON(w, http.StatusUnauthorized, errorResponse{Error: "unauthorized"})
+
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func (s *Service) openAPISpec(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if _, err := w.Write([]byte(docs.SwaggerInfo.ReadDoc())); err != nil {
+		slog.Debug("Failed to write OpenAPI spec", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/executor"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubExecutor struct {
+	started []string
+	closed  []int
+}
+
+func (e *stubExecutor) Strategies() []executor.StrategyStatus { return nil }
+
+func (e *stubExecutor) Strategy(name string) (executor.StrategyStatus, error) {
+	return executor.StrategyStatus{Name: name}, nil
+}
+
+func (e *stubExecutor) StartStrategy(name string) error {
+	e.started = append(e.started, name)
+	return nil
+}
+
+func (e *stubExecutor) StopStrategy(string) error   { return nil }
+func (e *stubExecutor) PauseStrategy(string) error  { return nil }
+func (e *stubExecutor) ResumeStrategy(string) error { return nil }
+
+func (e *stubExecutor) Positions() []executor.OpenPosition { return nil }
+
+func (e *stubExecutor) ClosePosition(_ context.Context, contractID int) error {
+	e.closed = append(e.closed, contractID)
+	return nil
+}
+
+func (e *stubExecutor) Contracts(context.Context) ([]executor.Contract, error) { return nil, nil }
+func (e *stubExecutor) Balances(context.Context) ([]executor.Balance, error)   { return nil, nil }
+func (e *stubExecutor) Trades(time.Time) []executor.ClosedTrade                { return nil }
+
+func TestService_Authorization(t *testing.T) {
+	tests := []struct {
+		name       string
+		token      string
+		method     string
+		path       string
+		header     string
+		wantStatus int
+		wantCalled bool
+	}{
+		{
+			name:       "start without token",
+			token:      "secret",
+			method:     http.MethodPost,
+			path:       "/strategies/r100/start",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "start with wrong token",
+			token:      "secret",
+			method:     http.MethodPost,
+			path:       "/strategies/r100/start",
+			header:     "Bearer other",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "start with token of another scheme",
+			token:      "secret",
+			method:     http.MethodPost,
+			path:       "/strategies/r100/start",
+			header:     "Basic secret",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "start with token",
+			token:      "secret",
+			method:     http.MethodPost,
+			path:       "/strategies/r100/start",
+			header:     "Bearer secret",
+			wantStatus: http.StatusOK,
+			wantCalled: true,
+		},
+		{
+			name:       "start when service has no token",
+			method:     http.MethodPost,
+			path:       "/strategies/r100/start",
+			header:     "Bearer ",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "close position without token",
+			token:      "secret",
+			method:     http.MethodPost,
+			path:       "/positions/42/close",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "close position with token",
+			token:      "secret",
+			method:     http.MethodPost,
+			path:       "/positions/42/close",
+			header:     "Bearer secret",
+			wantStatus: http.StatusNoContent,
+			wantCalled: true,
+		},
+		{
+			name:       "list strategies without token",
+			token:      "secret",
+			method:     http.MethodGet,
+			path:       "/strategies",
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := &stubExecutor{}
+			svc := New(Config{}, tt.token, exec, nil)
+
+			req := httptest.NewRequest(tt.method, tt.path, http.NoBody)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+
+			rec := httptest.NewRecorder()
+			svc.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantCalled, len(exec.started)+len(exec.closed) > 0)
+
+			if tt.wantStatus == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
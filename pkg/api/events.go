@@ -0,0 +1,79 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
+)
+
+const keepAliveInterval = 15 * time.Second
+
+// streamEvents streams bot events to the client as Server-Sent Events.
+// Each message has the event type as SSE event name and the JSON encoded event as data.
+//
+//	@Summary	Stream bot events
+//	@Tags		events
+//	@Produce	text/event-stream
+//	@Param		types	query	string	false	"Comma separated list of event types to receive, all events by default"
+//	@Success	200
+//	@Failure	500	{object}	errorResponse
+//	@Router		/events [get]
+func (s *Service) streamEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: "streaming is not supported"})
+		return
+	}
+
+	var types []event.Type
+
+	if param := r.URL.Query().Get("types"); param != "" {
+		for _, t := range strings.Split(param, ",") {
+			types = append(types, event.Type(strings.TrimSpace(t)))
+		}
+	}
+
+	events, unsubscribe := s.events.Subscribe(types...)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+
+			data, err := json.Marshal(e)
+			if err != nil {
+				slog.Error("Failed to encode event", slog.String("type", string(e.Type)), slog.Any("error", err))
+				continue
+			}
+
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data); err != nil {
+				return
+			}
+		}
+
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,336 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/executor"
+	"github.com/ksysoev/deriv-bot/pkg/core/report"
+)
+
+// listStrategies returns all registered strategies with their state.
+//
+//	@Summary	List strategies
+//	@Tags		strategies
+//	@Produce	json
+//	@Success	200	{array}	strategyResponse
+//	@Router		/strategies [get]
+func (s *Service) listStrategies(w http.ResponseWriter, _ *http.Request) {
+	statuses := s.exec.Strategies()
+	resp := make([]strategyResponse, 0, len(statuses))
+
+	for i := range statuses {
+		resp = append(resp, newStrategyResponse(&statuses[i]))
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// getStrategy returns a single strategy with its state.
+//
+//	@Summary	Get strategy
+//	@Tags		strategies
+//	@Produce	json
+//	@Param		name	path		string	true	"Strategy name"
+//	@Success	200		{object}	strategyResponse
+//	@Failure	404		{object}	errorResponse
+//	@Router		/strategies/{name} [get]
+func (s *Service) getStrategy(w http.ResponseWriter, r *http.Request) {
+	st, err := s.exec.Strategy(r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newStrategyResponse(&st))
+}
+
+// startStrategy starts an idle, stopped or errored strategy, it warms up before opening positions.
+//
+//	@Summary	Start strategy
+//	@Tags		strategies
+//	@Produce	json
+//	@Security	BearerAuth
+//	@Param		name	path		string	true	"Strategy name"
+//	@Success	200		{object}	strategyResponse
+//	@Failure	401		{object}	errorResponse
+//	@Failure	404		{object}	errorResponse
+//	@Failure	409		{object}	errorResponse
+//	@Failure	422		{object}	errorResponse
+//	@Router		/strategies/{name}/start [post]
+func (s *Service) startStrategy(w http.ResponseWriter, r *http.Request) {
+	s.controlStrategy(w, r, s.exec.StartStrategy)
+}
+
+// stopStrategy stops an active strategy, an open position is left untouched.
+//
+//	@Summary	Stop strategy
+//	@Tags		strategies
+//	@Produce	json
+//	@Security	BearerAuth
+//	@Param		name	path		string	true	"Strategy name"
+//	@Success	200		{object}	strategyResponse
+//	@Failure	401		{object}	errorResponse
+//	@Failure	404		{object}	errorResponse
+//	@Failure	409		{object}	errorResponse
+//	@Router		/strategies/{name}/stop [post]
+func (s *Service) stopStrategy(w http.ResponseWriter, r *http.Request) {
+	s.controlStrategy(w, r, s.exec.StopStrategy)
+}
+
+// pauseStrategy prevents an active strategy from opening new positions.
+//
+//	@Summary	Pause strategy
+//	@Tags		strategies
+//	@Produce	json
+//	@Security	BearerAuth
+//	@Param		name	path		string	true	"Strategy name"
+//	@Success	200		{object}	strategyResponse
+//	@Failure	401		{object}	errorResponse
+//	@Failure	404		{object}	errorResponse
+//	@Failure	409		{object}	errorResponse
+//	@Router		/strategies/{name}/pause [post]
+func (s *Service) pauseStrategy(w http.ResponseWriter, r *http.Request) {
+	s.controlStrategy(w, r, s.exec.PauseStrategy)
+}
+
+// resumeStrategy allows a paused strategy to open new positions again.
+//
+//	@Summary	Resume strategy
+//	@Tags		strategies
+//	@Produce	json
+//	@Security	BearerAuth
+//	@Param		name	path		string	true	"Strategy name"
+//	@Success	200		{object}	strategyResponse
+//	@Failure	401		{object}	errorResponse
+//	@Failure	404		{object}	errorResponse
+//	@Failure	409		{object}	errorResponse
+//	@Router		/strategies/{name}/resume [post]
+func (s *Service) resumeStrategy(w http.ResponseWriter, r *http.Request) {
+	s.controlStrategy(w, r, s.exec.ResumeStrategy)
+}
+
+// listPositions returns positions held by the strategies.
+//
+//	@Summary	List positions
+//	@Tags		positions
+//	@Produce	json
+//	@Success	200	{array}	positionResponse
+//	@Router		/positions [get]
+func (s *Service) listPositions(w http.ResponseWriter, _ *http.Request) {
+	positions := s.exec.Positions()
+	resp := make([]positionResponse, 0, len(positions))
+
+	for i := range positions {
+		resp = append(resp, newPositionResponse(&positions[i]))
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// closePosition closes a position held by a strategy.
+//
+//	@Summary	Close position
+//	@Tags		positions
+//	@Produce	json
+//	@Security	BearerAuth
+//	@Param		contract_id	path	int	true	"Contract ID"
+//	@Success	204
+//	@Failure	400	{object}	errorResponse
+//	@Failure	401	{object}	errorResponse
+//	@Failure	404	{object}	errorResponse
+//	@Failure	500	{object}	errorResponse
+//	@Router		/positions/{contract_id}/close [post]
+func (s *Service) closePosition(w http.ResponseWriter, r *http.Request) {
+	contractID, err := strconv.Atoi(r.PathValue("contract_id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid contract ID"})
+		return
+	}
+
+	if err := s.exec.ClosePosition(r.Context(), contractID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listContracts returns all open contracts on the trading accounts of strategies.
+//
+//	@Summary	List open contracts
+//	@Tags		account
+//	@Produce	json
+//	@Success	200	{array}		contractResponse
+//	@Failure	500	{object}	errorResponse
+//	@Router		/contracts [get]
+func (s *Service) listContracts(w http.ResponseWriter, r *http.Request) {
+	contracts, err := s.exec.Contracts(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := make([]contractResponse, 0, len(contracts))
+
+	for _, c := range contracts {
+		resp = append(resp, contractResponse{
+			PurchasedAt:  c.PurchasedAt,
+			LoginID:      c.LoginID,
+			Symbol:       c.Symbol,
+			ContractType: c.ContractType,
+			Currency:     c.Currency,
+			BuyPrice:     c.BuyPrice,
+			ID:           c.ID,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// listBalances returns the balances of the trading accounts of strategies, one per account.
+//
+//	@Summary	List account balances
+//	@Tags		account
+//	@Produce	json
+//	@Success	200	{array}		balanceResponse
+//	@Failure	500	{object}	errorResponse
+//	@Router		/balance [get]
+func (s *Service) listBalances(w http.ResponseWriter, r *http.Request) {
+	balances, err := s.exec.Balances(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := make([]balanceResponse, 0, len(balances))
+
+	for _, b := range balances {
+		resp = append(resp, balanceResponse{
+			LoginID:  b.LoginID,
+			Currency: b.Currency,
+			Amount:   b.Amount,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// getReport returns the performance report of trades closed since the bot started, or since the given time.
+//
+//	@Summary	Get performance report
+//	@Tags		account
+//	@Produce	json,text/csv,text/html
+//	@Param		since		query		string	false	"RFC 3339 time of the first closed trade to include"
+//	@Param		strategy	query		string	false	"Report only trades of the strategy"
+//	@Param		format		query		string	false	"Report format"	Enums(json, csv, html)	default(json)
+//	@Success	200			{object}	report.Report
+//	@Failure	400			{object}	errorResponse
+//	@Router		/report [get]
+func (s *Service) getReport(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var since time.Time
+
+	if v := q.Get("since"); v != "" {
+		var err error
+
+		if since, err = time.Parse(time.RFC3339, v); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid since, expected RFC 3339 time"})
+			return
+		}
+	}
+
+	format := q.Get("format")
+	if format == "" {
+		format = report.FormatJSON
+	}
+
+	contentType, ok := reportContentTypes[format]
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("unknown report format %q", format)})
+		return
+	}
+
+	strategy := q.Get("strategy")
+	trades := make([]report.Trade, 0)
+
+	for _, t := range s.exec.Trades(since) {
+		if strategy != "" && t.Strategy != strategy {
+			continue
+		}
+
+		trades = append(trades, report.Trade{
+			OpenedAt: t.OpenedAt,
+			ClosedAt: t.ClosedAt,
+			Symbol:   t.Symbol,
+			Stake:    t.Amount,
+			Profit:   t.Profit,
+		})
+	}
+
+	w.Header().Set("Content-Type", contentType)
+
+	if err := report.Write(w, format, report.New(trades, 0), trades); err != nil {
+		slog.Debug("Failed to write report", slog.Any("error", err))
+	}
+}
+
+var reportContentTypes = map[string]string{
+	report.FormatJSON: "application/json",
+	report.FormatCSV:  "text/csv",
+	report.FormatHTML: "text/html; charset=utf-8",
+}
+
+func (s *Service) controlStrategy(w http.ResponseWriter, r *http.Request, op func(name string) error) {
+	name := r.PathValue("name")
+
+	if err := op(name); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	st, err := s.exec.Strategy(name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newStrategyResponse(&st))
+}
+
+// writeError maps executor errors to HTTP status codes and writes them as JSON.
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, executor.ErrStrategyNotFound), errors.Is(err, executor.ErrNoOpenPosition):
+		status = http.StatusNotFound
+	case errors.Is(err, executor.ErrInvalidState):
+		status = http.StatusConflict
+	case errors.Is(err, executor.ErrContractLimits):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, executor.ErrNotRunning):
+		status = http.StatusServiceUnavailable
+	}
+
+	if status == http.StatusInternalServerError {
+		slog.Error("Control API request failed", slog.Any("error", err))
+	}
+
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		slog.Debug("Failed to write response", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/executor"
+)
+
+type errorResponse struct {
+	Error string `json:"error" example:"strategy not found: r100-long"`
+}
+
+type positionResponse struct {
+	OpenedAt   time.Time `json:"opened_at"`
+	Strategy   string    `json:"strategy" example:"r100-long"`
+	Symbol     string    `json:"symbol" example:"R_100"`
+	Type       string    `json:"type" example:"buy"`
+	Amount     float64   `json:"amount" example:"10"`
+	Price      float64   `json:"price" example:"1234.56"`
+	Leverage   float64   `json:"leverage" example:"10"`
+	StopPrice  float64   `json:"stop_price,omitempty" example:"1230.5"`
+	ContractID int       `json:"contract_id" example:"123456789"`
+}
+
+type orderResponse struct {
+	UpdatedAt  time.Time `json:"updated_at"`
+	State      string    `json:"state" example:"filled"`
+	Error      string    `json:"error,omitempty"`
+	Price      float64   `json:"price" example:"1234.56"`
+	ID         int       `json:"id" example:"1"`
+	ContractID int       `json:"contract_id,omitempty" example:"123456789"`
+}
+
+type strategyResponse struct {
+	StateSince    time.Time         `json:"state_since"`
+	CooldownUntil *time.Time        `json:"cooldown_until,omitempty"`
+	Position      *positionResponse `json:"position,omitempty"`
+	Order         *orderResponse    `json:"order,omitempty"`
+	Name          string            `json:"name" example:"r100-long"`
+	Symbol        string            `json:"symbol" example:"R_100"`
+	Type          string            `json:"type" example:"buy"`
+	State         string            `json:"state" example:"waiting_to_open" enums:"idle,warming_up,waiting_to_open,in_position,cooling_down,paused,stopped,errored"`
+	Error         string            `json:"error,omitempty"`
+	Amount        float64           `json:"amount" example:"10"`
+	Leverage      float64           `json:"leverage" example:"10"`
+	TradesToday   int               `json:"trades_today" example:"3"`
+}
+
+type contractResponse struct {
+	PurchasedAt  time.Time `json:"purchased_at"`
+	LoginID      string    `json:"login_id" example:"VRTC123456"`
+	Symbol       string    `json:"symbol" example:"R_100"`
+	ContractType string    `json:"contract_type" example:"MULTUP"`
+	Currency     string    `json:"currency" example:"USD"`
+	BuyPrice     float64   `json:"buy_price" example:"10"`
+	ID           int       `json:"id" example:"123456789"`
+}
+
+type balanceResponse struct {
+	LoginID  string  `json:"login_id" example:"VRTC123456"`
+	Currency string  `json:"currency" example:"USD"`
+	Amount   float64 `json:"amount" example:"10000"`
+}
+
+func newPositionResponse(pos *executor.OpenPosition) positionResponse {
+	return positionResponse{
+		OpenedAt:   pos.OpenedAt,
+		Strategy:   pos.Strategy,
+		Symbol:     pos.Symbol,
+		Type:       pos.Type.String(),
+		Amount:     pos.Amount,
+		Price:      pos.Price,
+		Leverage:   pos.Leverage,
+		StopPrice:  pos.StopPrice,
+		ContractID: pos.ContractID,
+	}
+}
+
+func newStrategyResponse(st *executor.StrategyStatus) strategyResponse {
+	resp := strategyResponse{
+		Name:        st.Name,
+		Symbol:      st.Symbol,
+		Type:        st.Type.String(),
+		State:       string(st.State),
+		StateSince:  st.StateSince,
+		Error:       st.Error,
+		Amount:      st.Amount,
+		Leverage:    st.Leverage,
+		TradesToday: st.TradesToday,
+	}
+
+	if st.State == executor.StrategyStateCoolingDown {
+		until := st.CooldownUntil
+		resp.CooldownUntil = &until
+	}
+
+	if st.Position != nil {
+		pos := newPositionResponse(st.Position)
+		resp.Position = &pos
+	}
+
+	if o := st.Order; o != nil {
+		resp.Order = &orderResponse{
+			UpdatedAt:  o.UpdatedAt,
+			State:      string(o.State),
+			Error:      o.Error,
+			Price:      o.Price,
+			ID:         o.ID,
+			ContractID: o.ContractID,
+		}
+	}
+
+	return resp
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ksysoev/deriv-bot/pkg/prov/secret"
+)
+
+// resolveAPIToken resolves the bearer token of the control API, the token references an entry of cfg.Tokens.
+// Returns an empty token if the control API is not configured, and an error if the token is missing
+// or cannot be resolved.
+func resolveAPIToken(cfg *appConfig, resolver *secret.Resolver) (secret.Value, error) {
+	if cfg.API.Listen == "" {
+		return "", nil
+	}
+
+	if cfg.API.Token == "" {
+		return "", fmt.Errorf("control API requires a token")
+	}
+
+	token, err := resolveToken(cfg, resolver, cfg.API.Token)
+	if err != nil {
+		return "", fmt.Errorf("control API: %w", err)
+	}
+
+	return token, nil
+}
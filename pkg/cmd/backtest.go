@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/backtest"
+	"github.com/ksysoev/deriv-bot/pkg/core/executor"
+	"github.com/ksysoev/deriv-bot/pkg/core/report"
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+	"github.com/ksysoev/deriv-bot/pkg/repo/tickfile"
+)
+
+type backtestOptions struct {
+	Strategy string
+	Data     []string
+	Params   []string
+}
+
+// reportOptions selects the format of performance reports and where they are written, stdout by default.
+type reportOptions struct {
+	Format  string
+	Output  string
+	Balance float64
+}
+
+type optimizeOptions struct {
+	backtestOptions
+	Search      string
+	Objective   string
+	Samples     int
+	Workers     int
+	WalkForward int
+	Top         int
+	Seed        uint64
+}
+
+// runBacktest replays the recorded ticks through the configured strategy and writes the performance report of
+// its simulated trades. Params are name=value overrides of the strategy rule parameters.
+// Returns an error if the config, the strategy or the data can't be loaded, the strategy uses policies the backtest
+// doesn't simulate, or the report can't be written.
+func runBacktest(args *cmdArgs, opts backtestOptions, ropts reportOptions, w io.Writer) error {
+	sc, ticks, err := loadBacktest(args, opts)
+	if err != nil {
+		return err
+	}
+
+	params := make(map[string]float64, len(opts.Params))
+
+	for _, p := range opts.Params {
+		r, err := backtest.ParseParamRange(p)
+		if err != nil {
+			return err
+		}
+
+		if r.Min != r.Max {
+			return fmt.Errorf("parameter %s expects a single value, ranges are searched by optimize", r.Name)
+		}
+
+		params[r.Name] = r.Min
+	}
+
+	strategy, err := buildBacktestStrategy(sc, params)
+	if err != nil {
+		return err
+	}
+
+	if err := backtest.Check(strategy); err != nil {
+		return fmt.Errorf("failed to backtest strategy %s: %w", sc.Name, err)
+	}
+
+	res := backtest.Run(strategy, ticks)
+
+	trades := make([]report.Trade, 0, len(res.Trades))
+
+	for _, t := range res.Trades {
+		trades = append(trades, report.Trade{
+			OpenedAt: t.OpenedAt,
+			ClosedAt: t.ClosedAt,
+			Symbol:   sc.Symbol,
+			Stake:    t.Stake,
+			Profit:   t.Profit,
+		})
+	}
+
+	title := fmt.Sprintf("%s (%s, %s), %d ticks, %s - %s", sc.Name, sc.Symbol, sc.Type, len(ticks),
+		ticks[0].Time.UTC().Format(time.RFC3339), ticks[len(ticks)-1].Time.UTC().Format(time.RFC3339))
+
+	return writeReport(title, trades, ropts, w)
+}
+
+// runOptimize sweeps the parameter ranges of the configured strategy over the recorded ticks
+// and prints the best combinations and walk-forward splits to w.
+// Returns an error if the config, the strategy or the data can't be loaded, or the search is invalid.
+func runOptimize(ctx context.Context, args *cmdArgs, opts optimizeOptions, w io.Writer) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	sc, ticks, err := loadBacktest(args, opts.backtestOptions)
+	if err != nil {
+		return err
+	}
+
+	if len(opts.Params) == 0 {
+		return fmt.Errorf("at least one parameter range is required")
+	}
+
+	ranges := make([]backtest.ParamRange, 0, len(opts.Params))
+
+	for _, p := range opts.Params {
+		r, err := backtest.ParseParamRange(p)
+		if err != nil {
+			return err
+		}
+
+		ranges = append(ranges, r)
+	}
+
+	report, err := backtest.Optimize(ctx, backtest.OptimizeConfig{
+		Ranges:      ranges,
+		Search:      backtest.Search(opts.Search),
+		Objective:   backtest.Objective(opts.Objective),
+		Samples:     opts.Samples,
+		Workers:     opts.Workers,
+		WalkForward: opts.WalkForward,
+		Seed:        opts.Seed,
+	}, func(params map[string]float64) (executor.Strategy, error) {
+		return buildBacktestStrategy(sc, params)
+	}, ticks)
+	if err != nil {
+		return fmt.Errorf("failed to optimize strategy %s: %w", sc.Name, err)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Evaluated %d combinations over %d ticks, skipped %d invalid, ranked by %s\n\n",
+		len(report.Candidates), len(ticks), report.Skipped, opts.Objective)
+	fmt.Fprintln(tw, "RANK\tPARAMS\tSCORE\tTRADES\tWIN RATE\tNET PNL\tPROFIT FACTOR\tSHARPE\tMAX DD")
+
+	for i, c := range report.Candidates[:min(opts.Top, len(report.Candidates))] {
+		fmt.Fprintf(tw, "%d\t%s\t%.4g\t%d\t%.1f%%\t%.2f\t%.2f\t%.2f\t%.2f\n", i+1, formatParams(c.Params), c.Score,
+			len(c.Result.Trades), c.Result.WinRate()*100, c.Result.NetPnL, c.Result.ProfitFactor(), c.Result.Sharpe(), c.Result.MaxDrawdown)
+	}
+
+	if len(report.Splits) > 0 {
+		fmt.Fprintln(tw, "\nWALK-FORWARD\tTRAIN FROM\tTEST FROM\tTEST TO\tPARAMS\tTRAIN SCORE\tTEST SCORE\tTEST TRADES\tTEST NET PNL")
+
+		for i, s := range report.Splits {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%.4g\t%.4g\t%d\t%.2f\n", i+1,
+				s.TrainFrom.UTC().Format(time.RFC3339), s.TestFrom.UTC().Format(time.RFC3339), s.TestTo.UTC().Format(time.RFC3339),
+				formatParams(s.Best.Params), s.Best.Score, s.TestScore, len(s.Test.Trades), s.Test.NetPnL)
+		}
+	}
+
+	return tw.Flush()
+}
+
+// validate checks the numeric options of the search.
+// Returns an error if a count is negative, or samples aren't positive for random search.
+func (o *optimizeOptions) validate() error {
+	switch {
+	case o.Top < 0:
+		return fmt.Errorf("top must not be negative, got %d", o.Top)
+	case o.Samples < 0:
+		return fmt.Errorf("samples must not be negative, got %d", o.Samples)
+	case o.Samples == 0 && backtest.Search(o.Search) == backtest.SearchRandom:
+		return fmt.Errorf("samples must be positive for random search")
+	case o.Workers < 0:
+		return fmt.Errorf("workers must not be negative, got %d", o.Workers)
+	case o.WalkForward < 0:
+		return fmt.Errorf("walk-forward must not be negative, got %d", o.WalkForward)
+	}
+
+	return nil
+}
+
+// loadBacktest loads the config of the named strategy and the ticks of its symbol from the data files.
+// Data entries are file paths or glob patterns, ticks recorded for other symbols are skipped and ticks of all files
+// are ordered by time.
+// Returns an error if the strategy is not configured or no ticks of its symbol are found.
+func loadBacktest(args *cmdArgs, opts backtestOptions) (strategyConfig, []signal.Tick, error) {
+	cfg, err := loadConfig(args)
+	if err != nil {
+		return strategyConfig{}, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	idx := slices.IndexFunc(cfg.Strategies, func(sc strategyConfig) bool { return sc.Name == opts.Strategy })
+	if idx < 0 {
+		return strategyConfig{}, nil, fmt.Errorf("strategy %q is not configured", opts.Strategy)
+	}
+
+	sc := cfg.Strategies[idx]
+
+	var ticks []signal.Tick
+
+	for _, pattern := range opts.Data {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return strategyConfig{}, nil, fmt.Errorf("invalid data pattern %q: %w", pattern, err)
+		}
+
+		for _, path := range paths {
+			records, err := tickfile.ReadFile(path)
+			if err != nil {
+				return strategyConfig{}, nil, err
+			}
+
+			ticks = append(ticks, tickfile.Ticks(records, sc.Symbol)...)
+		}
+	}
+
+	if len(ticks) == 0 {
+		return strategyConfig{}, nil, fmt.Errorf("no ticks of %s found in %s", sc.Symbol, strings.Join(opts.Data, ", "))
+	}
+
+	sort.SliceStable(ticks, func(i, j int) bool { return ticks[i].Time.Before(ticks[j].Time) })
+
+	return sc, ticks, nil
+}
+
+// buildBacktestStrategy builds sc with params overriding its rule parameters. Tokens aren't resolved, backtests don't trade.
+func buildBacktestStrategy(sc strategyConfig, params map[string]float64) (executor.Strategy, error) {
+	merged := maps.Clone(sc.Params)
+	if merged == nil {
+		merged = make(map[string]float64, len(params))
+	}
+
+	maps.Copy(merged, params)
+	sc.Params = merged
+
+	return buildStrategy(sc, "")
+}
+
+func formatParams(params map[string]float64) string {
+	parts := make([]string, 0, len(params))
+
+	for _, name := range slices.Sorted(maps.Keys(params)) {
+		parts = append(parts, fmt.Sprintf("%s=%g", name, params[name]))
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/backtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptimizeOptions_Validate(t *testing.T) {
+	valid := optimizeOptions{Search: string(backtest.SearchRandom), Samples: 100, Top: 10}
+
+	tests := []struct {
+		modify  func(o *optimizeOptions)
+		name    string
+		wantErr string
+	}{
+		{name: "valid", modify: func(*optimizeOptions) {}},
+		{name: "grid without samples", modify: func(o *optimizeOptions) { o.Search, o.Samples = string(backtest.SearchGrid), 0 }},
+		{name: "negative top", modify: func(o *optimizeOptions) { o.Top = -1 }, wantErr: "top must not be negative"},
+		{name: "negative samples", modify: func(o *optimizeOptions) { o.Samples = -5 }, wantErr: "samples must not be negative"},
+		{name: "random without samples", modify: func(o *optimizeOptions) { o.Samples = 0 }, wantErr: "samples must be positive"},
+		{name: "negative workers", modify: func(o *optimizeOptions) { o.Workers = -2 }, wantErr: "workers must not be negative"},
+		{name: "negative walk-forward", modify: func(o *optimizeOptions) { o.WalkForward = -1 }, wantErr: "walk-forward must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := valid
+			tt.modify(&opts)
+
+			err := opts.validate()
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLoadBacktest_FiltersSymbol(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "config.yml")
+	require.NoError(t, os.WriteFile(config, []byte(`
+strategies:
+  - name: "r100"
+    symbol: "R_100"
+    type: "buy"
+    amount: 10
+    leverage: 100
+`), 0o600))
+
+	files := map[string]string{
+		"R_100.csv": "time,quote,ask,bid,symbol\n2000,102,102,102,R_100\n",
+		"R_50.csv":  "time,quote,ask,bid,symbol\n1500,50,50,50,R_50\n",
+		"mixed.csv": "time,quote,ask,bid,symbol\n1000,101,101,101,R_100\n1200,51,51,51,R_50\n",
+		"bare.csv":  "3000,103\n",
+	}
+
+	for name, data := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600))
+	}
+
+	args := &cmdArgs{ConfigPath: config}
+
+	sc, ticks, err := loadBacktest(args, backtestOptions{Strategy: "r100", Data: []string{filepath.Join(dir, "*.csv")}})
+	require.NoError(t, err)
+	assert.Equal(t, "R_100", sc.Symbol)
+
+	quotes := make([]float64, len(ticks))
+	for i, tick := range ticks {
+		quotes[i] = tick.Quote
+	}
+
+	assert.Equal(t, []float64{101, 102, 103}, quotes)
+
+	_, _, err = loadBacktest(args, backtestOptions{Strategy: "r100", Data: []string{filepath.Join(dir, "R_50.csv")}})
+	assert.ErrorContains(t, err, "no ticks of R_100 found")
+}
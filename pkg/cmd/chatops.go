@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/chatops"
+	"github.com/ksysoev/deriv-bot/pkg/prov/secret"
+	"github.com/ksysoev/deriv-bot/pkg/prov/telegram"
+)
+
+type chatCommandsConfig struct {
+	Token        string  `mapstructure:"token"`
+	URL          string  `mapstructure:"url"`
+	AllowedChats []int64 `mapstructure:"allowed_chats"`
+}
+
+// buildChatBot creates the Telegram bot for chat commands, the token references an entry of cfg.Tokens.
+// Returns nil if chat commands are not configured, and an error if the token cannot be resolved
+// or no chats are allowed.
+func buildChatBot(cfg *appConfig, resolver *secret.Resolver) (chatops.Bot, error) {
+	cc := cfg.ChatCommands
+	if cc.Token == "" {
+		return nil, nil
+	}
+
+	if len(cc.AllowedChats) == 0 {
+		return nil, fmt.Errorf("chat commands require at least one allowed chat")
+	}
+
+	token, err := resolveToken(cfg, resolver, cc.Token)
+	if err != nil {
+		return nil, fmt.Errorf("chat commands: %w", err)
+	}
+
+	return telegram.NewBot(telegram.New(cc.URL, token.Reveal())), nil
+}
@@ -5,16 +5,47 @@ import (
 	"log/slog"
 	"strings"
 
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/ksysoev/deriv-bot/pkg/api"
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
 	"github.com/ksysoev/deriv-bot/pkg/prov/deriv"
+	"github.com/ksysoev/deriv-bot/pkg/prov/paper"
+	"github.com/ksysoev/deriv-bot/pkg/prov/secret"
+	"github.com/ksysoev/deriv-bot/pkg/prov/synthetic"
+	"github.com/ksysoev/deriv-bot/pkg/repo/tickfile"
 	"github.com/spf13/viper"
 )
 
 type appConfig struct {
-	Deriv deriv.Config `mapstructure:"deriv"`
+	Tokens        map[string]string       `mapstructure:"tokens"`
+	Secrets       secret.Config           `mapstructure:"secrets"`
+	Deriv         deriv.Config            `mapstructure:"deriv"`
+	Market        marketConfig            `mapstructure:"market"`
+	Recorder      tickfile.RecorderConfig `mapstructure:"recorder"`
+	API           api.Config              `mapstructure:"api"`
+	Strategies    []strategyConfig        `mapstructure:"strategies"`
+	Notifications []notificationConfig    `mapstructure:"notifications"`
+	ChatCommands  chatCommandsConfig      `mapstructure:"chat_commands"`
+}
+
+const (
+	marketDeriv     = "deriv"
+	marketSynthetic = "synthetic"
+)
+
+// marketConfig selects where market data comes from and where orders go.
+// The synthetic provider generates ticks locally and fills orders on a paper account, so no network is needed.
+// Watchdog sets the data quality checks of ticks of any provider.
+type marketConfig struct {
+	Provider  string                `mapstructure:"provider"`
+	Synthetic synthetic.Config      `mapstructure:"synthetic"`
+	Paper     paper.Config          `mapstructure:"paper"`
+	Watchdog  signal.WatchdogConfig `mapstructure:"watchdog"`
 }
 
 // loadConfig loads the application configuration from the specified file path and environment variables.
 // It uses the provided args structure to determine the configuration path.
+// Unknown keys are rejected, so typos in the config file are reported instead of being silently ignored.
 // The function returns a pointer to the appConfig structure and an error if something goes wrong.
 func loadConfig(args *cmdArgs) (*appConfig, error) {
 	v := viper.NewWithOptions(viper.ExperimentalBindStruct())
@@ -32,7 +63,7 @@ func loadConfig(args *cmdArgs) (*appConfig, error) {
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv()
 
-	if err := v.Unmarshal(&cfg); err != nil {
+	if err := v.Unmarshal(&cfg, func(dc *mapstructure.DecoderConfig) { dc.ErrorUnused = true }); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
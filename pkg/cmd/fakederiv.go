@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/prov/deriv/derivtest"
+)
+
+// runFakeDeriv serves the fake Deriv API on listen until ctx is cancelled, so the bot can be demoed offline
+// by pointing deriv.endpoint to it. Every token is accepted and authorizes a virtual account.
+// Returns an error if the server fails to listen.
+func runFakeDeriv(ctx context.Context, args *cmdArgs, listen string, tickInterval time.Duration) error {
+	if err := initLogger(args); err != nil {
+		return fmt.Errorf("failed to init logger: %w", err)
+	}
+
+	fake := derivtest.New(derivtest.WithTickInterval(tickInterval))
+
+	srv := &http.Server{
+		Addr:              listen,
+		Handler:           fake,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		slog.Info("Fake Deriv API started", slog.String("endpoint", "ws://"+listen))
+
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("failed to run fake Deriv API: %w", err)
+	case <-ctx.Done():
+	}
+
+	fake.Disconnect()
+
+	return srv.Close()
+}
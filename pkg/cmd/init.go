@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 )
 
@@ -9,7 +11,6 @@ type BuildInfo struct {
 }
 
 type cmdArgs struct {
-	Token      string `mapstructure:"token"`
 	LogLevel   string `mapstructure:"log_level"`
 	Version    string
 	ConfigPath string `mapstructure:"config_path"`
@@ -32,6 +33,11 @@ func InitCommand(build BuildInfo) cobra.Command {
 	cmd.PersistentFlags().StringVar(&args.ConfigPath, "config", "", "config path")
 
 	cmd.AddCommand(initRunCommand(args))
+	cmd.AddCommand(initSecretsCommand(args))
+	cmd.AddCommand(initConfigCommand(args))
+	cmd.AddCommand(initBacktestCommand(args))
+	cmd.AddCommand(initOptimizeCommand(args))
+	cmd.AddCommand(initReportCommand())
 
 	return cmd
 }
@@ -52,10 +58,190 @@ func initRunCommand(args *cmdArgs) *cobra.Command {
 		},
 	}
 
-	// Temporary flag for token, to be removed later
-	cmdRunAll.Flags().StringVar(&args.Token, "token", "", "Deriv API token")
+	var (
+		fakeListen       string
+		fakeTickInterval time.Duration
+	)
+
+	cmdFakeDeriv := &cobra.Command{
+		Use:   "fake-deriv",
+		Short: "Run a fake Deriv API server",
+		Long:  "Run a local fake Deriv API server with synthetic ticks and a virtual account, for demos without network access.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runFakeDeriv(cmd.Context(), args, fakeListen, fakeTickInterval)
+		},
+	}
 
-	runCmd.AddCommand(cmdRunAll)
+	cmdFakeDeriv.Flags().StringVar(&fakeListen, "listen", "127.0.0.1:8765", "address to listen on")
+	cmdFakeDeriv.Flags().DurationVar(&fakeTickInterval, "tick-interval", time.Second, "interval between ticks")
+
+	runCmd.AddCommand(cmdRunAll, cmdFakeDeriv)
 
 	return runCmd
 }
+
+func initSecretsCommand(args *cmdArgs) *cobra.Command {
+	secretsCmd := &cobra.Command{
+		Use:   "secrets",
+		Short: "Manage the encrypted keystore",
+		Long:  "Manage API tokens stored in the encrypted local keystore. The passphrase is read from the environment.",
+	}
+
+	cmdSet := &cobra.Command{
+		Use:   "set <name>",
+		Short: "Store a secret",
+		Long:  "Store a secret in the keystore, the value is read from stdin.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, cmdArgs []string) error {
+			return setSecret(args, cmdArgs[0], cmd.InOrStdin())
+		},
+	}
+
+	cmdList := &cobra.Command{
+		Use:   "list",
+		Short: "List stored secret names",
+		Long:  "List names of secrets stored in the keystore, values are never printed.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return listSecrets(args, cmd.OutOrStdout())
+		},
+	}
+
+	cmdDelete := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a secret",
+		Long:  "Delete a secret from the keystore.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, cmdArgs []string) error {
+			return deleteSecret(args, cmdArgs[0])
+		},
+	}
+
+	secretsCmd.AddCommand(cmdSet, cmdList, cmdDelete)
+
+	return secretsCmd
+}
+
+func initConfigCommand(args *cmdArgs) *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the configuration",
+		Long:  "Validate the configuration and export its JSON Schema.",
+	}
+
+	cmdValidate := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the configuration",
+		Long:  "Check required fields, value ranges, unknown keys and token references of the configuration.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return validateConfigFile(args, cmd.OutOrStdout())
+		},
+	}
+
+	cmdSchema := &cobra.Command{
+		Use:   "schema",
+		Short: "Print the configuration JSON Schema",
+		Long:  "Print the JSON Schema of the configuration file, for validation and autocomplete in editors.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return writeConfigSchema(cmd.OutOrStdout())
+		},
+	}
+
+	configCmd.AddCommand(cmdValidate, cmdSchema)
+
+	return configCmd
+}
+
+func initBacktestCommand(args *cmdArgs) *cobra.Command {
+	var (
+		opts  backtestOptions
+		ropts reportOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "backtest",
+		Short: "Backtest a strategy on recorded ticks",
+		Long: "Replay recorded tick files through the rules of a configured strategy and report the performance of its " +
+			"simulated trades. Strategies with exits, a schedule, a cooldown or a daily trade limit are rejected, " +
+			"the replay doesn't simulate them.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runBacktest(args, opts, ropts, cmd.OutOrStdout())
+		},
+	}
+
+	addBacktestFlags(cmd, &opts)
+	addReportFlags(cmd, &ropts)
+	cmd.Flags().StringArrayVar(&opts.Params, "param", nil, "rule parameter override, e.g. fast=12")
+
+	return cmd
+}
+
+func initReportCommand() *cobra.Command {
+	var (
+		ropts  reportOptions
+		trades string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Report the performance of closed trades",
+		Long: "Compute performance statistics, equity and drawdown curves of closed trades read from a CSV file " +
+			"with opened_at, closed_at, symbol, stake and profit columns, e.g. exported by backtest --format csv.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runReport(trades, ropts, cmd.OutOrStdout())
+		},
+	}
+
+	addReportFlags(cmd, &ropts)
+	cmd.Flags().StringVar(&trades, "trades", "", "CSV file of closed trades")
+
+	_ = cmd.MarkFlagRequired("trades")
+
+	return cmd
+}
+
+func addReportFlags(cmd *cobra.Command, opts *reportOptions) {
+	cmd.Flags().StringVar(&opts.Format, "format", formatText, "report format (text, json, csv, html)")
+	cmd.Flags().StringVar(&opts.Output, "output", "", "report file, defaults to stdout")
+	cmd.Flags().Float64Var(&opts.Balance, "balance", 0, "initial balance the equity curve starts at")
+}
+
+func initOptimizeCommand(args *cmdArgs) *cobra.Command {
+	var opts optimizeOptions
+
+	cmd := &cobra.Command{
+		Use:   "optimize",
+		Short: "Search strategy parameters on recorded ticks",
+		Long: "Backtest combinations of rule parameters of a configured strategy on recorded tick files in parallel " +
+			"and rank them by an objective, optionally validating the best ones with walk-forward splits.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runOptimize(cmd.Context(), args, opts, cmd.OutOrStdout())
+		},
+	}
+
+	addBacktestFlags(cmd, &opts.backtestOptions)
+	cmd.Flags().StringArrayVar(&opts.Params, "param", nil, "parameter range as name=min:max[:step], e.g. fast=5:20")
+	cmd.Flags().StringVar(&opts.Search, "search", "grid", "search method (grid, random)")
+	cmd.Flags().IntVar(&opts.Samples, "samples", 100, "number of combinations tried by random search")
+	cmd.Flags().StringVar(&opts.Objective, "objective", "net_pnl", "ranking objective (net_pnl, sharpe, profit_factor, max_drawdown)")
+	cmd.Flags().IntVar(&opts.Workers, "workers", 0, "parallel backtests, defaults to the number of CPUs")
+	cmd.Flags().IntVar(&opts.WalkForward, "walk-forward", 0, "number of walk-forward splits, 0 disables them")
+	cmd.Flags().IntVar(&opts.Top, "top", 10, "number of best combinations to print")
+	cmd.Flags().Uint64Var(&opts.Seed, "seed", 0, "random search seed, 0 picks a random one")
+
+	return cmd
+}
+
+func addBacktestFlags(cmd *cobra.Command, opts *backtestOptions) {
+	cmd.Flags().StringVar(&opts.Strategy, "strategy", "", "name of the configured strategy")
+	cmd.Flags().StringArrayVar(&opts.Data, "data", nil,
+		"tick file or glob pattern, e.g. ./runtime/ticks/R_100/*.csv.gz, ticks of other symbols are skipped")
+
+	_ = cmd.MarkFlagRequired("strategy")
+	_ = cmd.MarkFlagRequired("data")
+}
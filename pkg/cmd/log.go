@@ -2,10 +2,15 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"os"
 )
 
+type redactor interface {
+	Redact(s string) string
+}
+
 // ContextHandler is a custom slog.Handler that enriches log records with application-specific attributes.
 // It embeds a slog.Handler and adds attributes like application name and version, as well as request-specific context data.
 type ContextHandler struct {
@@ -61,3 +66,74 @@ func initLogger(arg *cmdArgs) error {
 
 	return nil
 }
+
+// RedactHandler is a slog.Handler that scrubs known secrets from log records before they are written.
+// It redacts the message and all string attributes, including nested groups and values formatted from arbitrary types.
+type RedactHandler struct {
+	slog.Handler
+	redactor redactor
+}
+
+// Handle redacts the message and attributes of the record and delegates it to the embedded handler.
+// Returns error if the embedded handler fails.
+func (h RedactHandler) Handle(ctx context.Context, r slog.Record) error { //nolint:gocritic // slog.Handler interface requires passing record by value
+	redacted := slog.NewRecord(r.Time, r.Level, h.redactor.Redact(r.Message), r.PC)
+
+	r.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(h.redactAttr(attr))
+		return true
+	})
+
+	return h.Handler.Handle(ctx, redacted)
+}
+
+// WithAttrs returns a new RedactHandler whose embedded handler has the redacted attributes attached.
+func (h RedactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		redacted = append(redacted, h.redactAttr(attr))
+	}
+
+	return RedactHandler{Handler: h.Handler.WithAttrs(redacted), redactor: h.redactor}
+}
+
+// WithGroup returns a new RedactHandler whose embedded handler starts the named group.
+func (h RedactHandler) WithGroup(name string) slog.Handler {
+	return RedactHandler{Handler: h.Handler.WithGroup(name), redactor: h.redactor}
+}
+
+func (h RedactHandler) redactAttr(attr slog.Attr) slog.Attr {
+	val := attr.Value.Resolve()
+
+	switch val.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, h.redactor.Redact(val.String()))
+	case slog.KindGroup:
+		group := val.Group()
+		redacted := make([]any, 0, len(group))
+
+		for _, a := range group {
+			redacted = append(redacted, h.redactAttr(a))
+		}
+
+		return slog.Group(attr.Key, redacted...)
+	case slog.KindAny:
+		formatted := fmt.Sprintf("%+v", val.Any())
+		if scrubbed := h.redactor.Redact(formatted); scrubbed != formatted {
+			return slog.String(attr.Key, scrubbed)
+		}
+
+		return slog.Attr{Key: attr.Key, Value: val}
+	default:
+		return slog.Attr{Key: attr.Key, Value: val}
+	}
+}
+
+// enableRedaction wraps the default logger handler with RedactHandler using the provided redactor.
+// It also covers output of the standard log package, which is routed through the default slog handler.
+func enableRedaction(r redactor) {
+	slog.SetDefault(slog.New(RedactHandler{
+		Handler:  slog.Default().Handler(),
+		redactor: r,
+	}))
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
+	"github.com/ksysoev/deriv-bot/pkg/core/notifier"
+	"github.com/ksysoev/deriv-bot/pkg/prov/notify"
+	"github.com/ksysoev/deriv-bot/pkg/prov/secret"
+)
+
+type notificationConfig struct {
+	Name          string   `mapstructure:"name"`
+	Type          string   `mapstructure:"type"`
+	URL           string   `mapstructure:"url"`
+	Token         string   `mapstructure:"token"`
+	ChatID        string   `mapstructure:"chat_id"`
+	Events        []string `mapstructure:"events"`
+	RatePerMinute int      `mapstructure:"rate_per_minute"`
+	Burst         int      `mapstructure:"burst"`
+}
+
+// buildSinks converts notification configurations into notifier sinks.
+// The token of a sink references cfg.Tokens: it is the bot token for telegram, the incoming webhook URL for slack
+// and the optional HMAC signing secret for webhook sinks.
+// Returns an error if a sink type is unknown or its token cannot be resolved.
+func buildSinks(cfg *appConfig, resolver *secret.Resolver) ([]notifier.SinkConfig, error) {
+	sinks := make([]notifier.SinkConfig, 0, len(cfg.Notifications))
+
+	for _, nc := range cfg.Notifications {
+		var token secret.Value
+
+		if nc.Token != "" {
+			var err error
+
+			if token, err = resolveToken(cfg, resolver, nc.Token); err != nil {
+				return nil, fmt.Errorf("notification %s: %w", nc.Name, err)
+			}
+		}
+
+		var sink notifier.Sink
+
+		switch nc.Type {
+		case "telegram":
+			sink = notify.NewTelegram(nc.URL, token.Reveal(), nc.ChatID)
+		case "slack":
+			sink = notify.NewSlack(token.Reveal())
+		case "webhook":
+			sink = notify.NewWebhook(nc.URL, token.Reveal())
+		default:
+			return nil, fmt.Errorf("notification %s has unknown type %q", nc.Name, nc.Type)
+		}
+
+		events := make([]event.Type, 0, len(nc.Events))
+		for _, e := range nc.Events {
+			events = append(events, event.Type(e))
+		}
+
+		sinks = append(sinks, notifier.SinkConfig{
+			Sink:          sink,
+			Name:          nc.Name,
+			Events:        events,
+			RatePerMinute: nc.RatePerMinute,
+			Burst:         nc.Burst,
+		})
+	}
+
+	return sinks, nil
+}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/ksysoev/deriv-bot/pkg/core/executor"
+	"github.com/ksysoev/deriv-bot/pkg/prov/secret"
+	"github.com/spf13/viper"
+)
+
+type strategyRegistry interface {
+	AddStrategy(strategy executor.Strategy) error
+	StartStrategy(name string) error
+	UpdateStrategy(strategy executor.Strategy) error
+	RemoveStrategy(name string) error
+}
+
+// symbolCatalogue checks symbols are offered by the market.
+type symbolCatalogue interface {
+	Load(ctx context.Context, symbols []string) error
+}
+
+// configReloader applies strategy changes from the config file to the running executor.
+// When catalogue is set, strategy symbols are checked against it before changes are applied.
+type configReloader struct {
+	exec      strategyRegistry
+	catalogue symbolCatalogue
+	resolver  *secret.Resolver
+	args      *cmdArgs
+	cfg       *appConfig
+}
+
+// newConfigReloader creates a reloader of the strategies of exec, catalogue may be nil when symbols aren't checked.
+func newConfigReloader(
+	args *cmdArgs,
+	cfg *appConfig,
+	resolver *secret.Resolver,
+	exec strategyRegistry,
+	catalogue symbolCatalogue,
+) *configReloader {
+	return &configReloader{
+		exec:      exec,
+		catalogue: catalogue,
+		resolver:  resolver,
+		args:      args,
+		cfg:       cfg,
+	}
+}
+
+// Run reloads the configuration on SIGHUP and, when a config file is used, on changes of the file,
+// until ctx is cancelled. An invalid configuration is logged and the running strategies are kept as they are.
+// Returns nil when ctx is cancelled.
+func (r *configReloader) Run(ctx context.Context) error {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	defer signal.Stop(hup)
+
+	changes := make(chan struct{}, 1)
+
+	if r.args.ConfigPath != "" {
+		watchConfig(r.args.ConfigPath, changes)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-hup:
+			slog.Info("Reloading config on SIGHUP")
+		case <-changes:
+			slog.Info("Reloading config on file change")
+		}
+
+		if err := r.reload(ctx); err != nil {
+			slog.Error("Failed to reload config, keeping the current one", slog.Any("error", err))
+		}
+	}
+}
+
+// watchConfig notifies changes when the config file at path is written.
+// Notifications are coalesced, so a burst of writes results in a single reload.
+func watchConfig(path string, changes chan<- struct{}) {
+	v := viper.New()
+	v.SetConfigFile(path)
+
+	v.OnConfigChange(func(fsnotify.Event) {
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
+	})
+
+	v.WatchConfig()
+}
+
+// reload loads and validates the config and diffs its strategies against the applied ones:
+// new strategies are added and started, removed ones are stopped and unregistered, and changed ones are updated.
+// All strategies are built before any change is applied, so an invalid config changes nothing. Changes the
+// executor fails to apply are logged and tried again on the next reload.
+// Returns an error if the config can't be loaded, is invalid, a strategy can't be built or trades an unknown symbol.
+func (r *configReloader) reload(ctx context.Context) error {
+	cfg, err := loadConfig(r.args)
+	if err != nil {
+		return err
+	}
+
+	if err := validateConfig(cfg); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	strategies, err := buildStrategies(cfg, r.resolver)
+	if err != nil {
+		return fmt.Errorf("failed to build strategies: %w", err)
+	}
+
+	if r.catalogue != nil {
+		if err := r.catalogue.Load(ctx, strategySymbols(strategies)); err != nil {
+			return fmt.Errorf("failed to check strategy symbols: %w", err)
+		}
+	}
+
+	current := make(map[string]strategyConfig, len(r.cfg.Strategies))
+	for _, sc := range r.cfg.Strategies {
+		current[sc.Name] = sc
+	}
+
+	next := make(map[string]struct{}, len(cfg.Strategies))
+
+	// The baseline of the next reload holds the strategies as they are applied, a failed change keeps the previous
+	// config of its strategy, so it's tried again on the next reload.
+	applied := make([]strategyConfig, 0, len(cfg.Strategies))
+
+	for i, sc := range cfg.Strategies {
+		next[sc.Name] = struct{}{}
+
+		prev, ok := current[sc.Name]
+
+		switch {
+		case !ok:
+			if err := r.exec.AddStrategy(strategies[i]); err != nil {
+				slog.Error("Failed to add strategy", slog.String("strategy", sc.Name), slog.Any("error", err))
+				continue
+			}
+
+			// A registered strategy failing to start can still be started through the control interfaces.
+			applied = append(applied, sc)
+
+			if err := r.exec.StartStrategy(sc.Name); err != nil {
+				slog.Error("Failed to start strategy", slog.String("strategy", sc.Name), slog.Any("error", err))
+				continue
+			}
+
+			slog.Info("Strategy added", slog.String("strategy", sc.Name))
+		default:
+			changed := changedFields(prev, sc, r.cfg.Tokens, cfg.Tokens)
+			if len(changed) == 0 {
+				applied = append(applied, sc)
+				continue
+			}
+
+			if err := r.exec.UpdateStrategy(strategies[i]); err != nil {
+				slog.Error("Failed to update strategy", slog.String("strategy", sc.Name), slog.Any("error", err))
+
+				applied = append(applied, prev)
+
+				continue
+			}
+
+			applied = append(applied, sc)
+
+			slog.Info("Strategy changed, applied when flat", slog.String("strategy", sc.Name), slog.Any("fields", changed))
+		}
+	}
+
+	for _, sc := range r.cfg.Strategies {
+		if _, ok := next[sc.Name]; ok {
+			continue
+		}
+
+		if err := r.exec.RemoveStrategy(sc.Name); err != nil {
+			slog.Error("Failed to remove strategy", slog.String("strategy", sc.Name), slog.Any("error", err))
+
+			applied = append(applied, sc)
+
+			continue
+		}
+
+		slog.Info("Strategy removed", slog.String("strategy", sc.Name))
+	}
+
+	warnRestartRequired(r.cfg, cfg)
+
+	cfg.Strategies = applied
+	r.cfg = cfg
+
+	return nil
+}
+
+// changedFields returns the config keys that differ between two versions of a strategy.
+// The token is considered changed when either its name or the secret reference behind it changed.
+func changedFields(prev, next strategyConfig, prevTokens, nextTokens map[string]string) []string {
+	var changed []string
+
+	if prev.Token != next.Token || prevTokens[prev.Token] != nextTokens[next.Token] {
+		changed = append(changed, "token")
+	}
+
+	fields := []struct {
+		name    string
+		changed bool
+	}{
+		{"symbol", prev.Symbol != next.Symbol},
+		{"type", prev.Type != next.Type},
+		{"rule", prev.Rule != next.Rule},
+		{"params", !reflect.DeepEqual(prev.Params, next.Params)},
+		{"amount", prev.Amount != next.Amount},
+		{"leverage", prev.Leverage != next.Leverage},
+		{"clamp_limits", prev.ClampLimits != next.ClampLimits},
+		{"shutdown", prev.Shutdown != next.Shutdown},
+		{"stale_data", prev.StaleData != next.StaleData},
+		{"exit", prev.Exit != next.Exit},
+		{"schedule", !reflect.DeepEqual(prev.Schedule, next.Schedule)},
+		{"cooldown", prev.Cooldown != next.Cooldown},
+		{"max_trades_per_day", prev.MaxTradesPerDay != next.MaxTradesPerDay},
+	}
+
+	for _, f := range fields {
+		if f.changed {
+			changed = append(changed, f.name)
+		}
+	}
+
+	return changed
+}
+
+// warnRestartRequired logs config sections that changed but are applied only on restart.
+func warnRestartRequired(prev, next *appConfig) {
+	sections := []struct {
+		name    string
+		changed bool
+	}{
+		{"deriv", prev.Deriv != next.Deriv},
+		{"market", !reflect.DeepEqual(prev.Market, next.Market)},
+		{"recorder", prev.Recorder != next.Recorder},
+		{"secrets", prev.Secrets != next.Secrets},
+		{"api", prev.API != next.API},
+		{"notifications", !reflect.DeepEqual(prev.Notifications, next.Notifications)},
+		{"chat_commands", !reflect.DeepEqual(prev.ChatCommands, next.ChatCommands)},
+	}
+
+	for _, s := range sections {
+		if s.changed {
+			slog.Warn("Config section changed, restart to apply", slog.String("section", s.name))
+		}
+	}
+}
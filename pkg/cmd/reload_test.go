@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/executor"
+	"github.com/ksysoev/deriv-bot/pkg/prov/secret"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRegistry records applied changes, changes of strategies in fail are rejected with their error.
+type fakeRegistry struct {
+	fail    map[string]error
+	added   []string
+	started []string
+	updated []executor.Strategy
+	removed []string
+}
+
+func (f *fakeRegistry) AddStrategy(strategy executor.Strategy) error {
+	if err := f.fail[strategy.Name]; err != nil {
+		return err
+	}
+
+	f.added = append(f.added, strategy.Name)
+
+	return nil
+}
+
+func (f *fakeRegistry) StartStrategy(name string) error {
+	f.started = append(f.started, name)
+	return nil
+}
+
+func (f *fakeRegistry) UpdateStrategy(strategy executor.Strategy) error {
+	if err := f.fail[strategy.Name]; err != nil {
+		return err
+	}
+
+	f.updated = append(f.updated, strategy)
+
+	return nil
+}
+
+func (f *fakeRegistry) RemoveStrategy(name string) error {
+	if err := f.fail[name]; err != nil {
+		return err
+	}
+
+	f.removed = append(f.removed, name)
+
+	return nil
+}
+
+const reloadBaseConfig = `
+deriv:
+  endpoint: "wss://ws.derivws.com/websockets/v3"
+  app_id: 1
+tokens:
+  demo: "env:TEST_RELOAD_TOKEN"
+strategies:
+  - name: "kept"
+    token: "demo"
+    symbol: "R_100"
+    type: "buy"
+    amount: 10
+    leverage: 10
+  - name: "changed"
+    token: "demo"
+    symbol: "R_50"
+    type: "buy"
+    amount: 10
+    leverage: 10
+  - name: "removed"
+    token: "demo"
+    symbol: "R_25"
+    type: "sell"
+    amount: 10
+    leverage: 10
+`
+
+const reloadNextConfig = `
+deriv:
+  endpoint: "wss://ws.derivws.com/websockets/v3"
+  app_id: 1
+tokens:
+  demo: "env:TEST_RELOAD_TOKEN"
+strategies:
+  - name: "kept"
+    token: "demo"
+    symbol: "R_100"
+    type: "buy"
+    amount: 10
+    leverage: 10
+  - name: "changed"
+    token: "demo"
+    symbol: "R_50"
+    type: "buy"
+    amount: 20
+    leverage: 10
+  - name: "added"
+    token: "demo"
+    symbol: "R_10"
+    type: "buy"
+    amount: 5
+    leverage: 10
+`
+
+func TestConfigReloader_Reload(t *testing.T) {
+	t.Setenv("TEST_RELOAD_TOKEN", "token")
+
+	path := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(path, []byte(reloadBaseConfig), 0o600))
+
+	args := &cmdArgs{ConfigPath: path}
+
+	cfg, err := loadConfig(args)
+	require.NoError(t, err)
+
+	exec := &fakeRegistry{}
+	r := newConfigReloader(args, cfg, secret.New(secret.Config{}), exec, nil)
+
+	require.NoError(t, os.WriteFile(path, []byte(reloadNextConfig), 0o600))
+	require.NoError(t, r.reload(context.Background()))
+
+	assert.Equal(t, []string{"added"}, exec.added)
+	assert.Equal(t, []string{"added"}, exec.started)
+	assert.Equal(t, []string{"removed"}, exec.removed)
+	require.Len(t, exec.updated, 1)
+	assert.Equal(t, "changed", exec.updated[0].Name)
+	assert.InDelta(t, 20, exec.updated[0].Amount, 0)
+
+	// Reloading the same config changes nothing.
+	*exec = fakeRegistry{}
+
+	require.NoError(t, r.reload(context.Background()))
+	assert.Equal(t, fakeRegistry{}, *exec)
+
+	// An invalid config is rejected as a whole.
+	require.NoError(t, os.WriteFile(path, []byte(strings.Replace(reloadBaseConfig, "amount: 10", "amount: -1", 1)), 0o600))
+	require.Error(t, r.reload(context.Background()))
+	assert.Equal(t, fakeRegistry{}, *exec)
+}
+
+func TestConfigReloader_ReloadFailedChanges(t *testing.T) {
+	t.Setenv("TEST_RELOAD_TOKEN", "token")
+
+	path := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(path, []byte(reloadBaseConfig), 0o600))
+
+	args := &cmdArgs{ConfigPath: path}
+
+	cfg, err := loadConfig(args)
+	require.NoError(t, err)
+
+	errFailed := errors.New("executor failure")
+	exec := &fakeRegistry{fail: map[string]error{"added": errFailed, "changed": errFailed, "removed": errFailed}}
+	r := newConfigReloader(args, cfg, secret.New(secret.Config{}), exec, nil)
+
+	require.NoError(t, os.WriteFile(path, []byte(reloadNextConfig), 0o600))
+	require.NoError(t, r.reload(context.Background()))
+
+	assert.Empty(t, exec.added)
+	assert.Empty(t, exec.updated)
+	assert.Empty(t, exec.removed)
+
+	// Failed changes are tried again on the next reload.
+	*exec = fakeRegistry{}
+
+	require.NoError(t, r.reload(context.Background()))
+
+	assert.Equal(t, []string{"added"}, exec.added)
+	assert.Equal(t, []string{"added"}, exec.started)
+	assert.Equal(t, []string{"removed"}, exec.removed)
+	require.Len(t, exec.updated, 1)
+	assert.Equal(t, "changed", exec.updated[0].Name)
+
+	*exec = fakeRegistry{}
+
+	require.NoError(t, r.reload(context.Background()))
+	assert.Equal(t, fakeRegistry{}, *exec)
+}
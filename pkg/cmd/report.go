@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/report"
+)
+
+const formatText = "text"
+
+// runReport reads closed trades from the CSV file at path and writes their performance report.
+// Returns an error if the trades can't be read or the report can't be written.
+func runReport(path string, opts reportOptions, w io.Writer) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open trades file: %w", err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	trades, err := report.ReadCSV(f)
+	if err != nil {
+		return fmt.Errorf("failed to read trades from %s: %w", path, err)
+	}
+
+	return writeReport(path, trades, opts, w)
+}
+
+// writeReport computes the report of trades and writes it in the selected format to the output file, or to w
+// when no output is set. The text format is a summary for the terminal titled with title.
+// Returns an error if the format is unknown or the report can't be written.
+func writeReport(title string, trades []report.Trade, opts reportOptions, w io.Writer) (err error) {
+	if opts.Format != formatText && !slices.Contains(report.Formats, opts.Format) {
+		return fmt.Errorf("unknown report format %q", opts.Format)
+	}
+
+	if opts.Output != "" {
+		f, err := os.Create(opts.Output)
+		if err != nil {
+			return fmt.Errorf("failed to create report file: %w", err)
+		}
+
+		defer func() {
+			if cerr := f.Close(); err == nil && cerr != nil {
+				err = fmt.Errorf("failed to close report file: %w", cerr)
+			}
+		}()
+
+		w = f
+	}
+
+	r := report.New(trades, opts.Balance)
+
+	if opts.Format != formatText {
+		return report.Write(w, opts.Format, r, trades)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Report\t%s\n", title)
+	fmt.Fprintf(tw, "Trades\t%d\n", r.Trades)
+	fmt.Fprintf(tw, "Win rate\t%.1f%%\n", r.WinRate*100)
+	fmt.Fprintf(tw, "Net PnL\t%.2f\n", r.NetPnL)
+	fmt.Fprintf(tw, "Profit factor\t%s\n", formatOptional(r.ProfitFactor))
+	fmt.Fprintf(tw, "Expectancy\t%.2f\n", r.Expectancy)
+	fmt.Fprintf(tw, "Average win / loss\t%.2f / %.2f\n", r.AverageWin, r.AverageLoss)
+	fmt.Fprintf(tw, "Longest losing streak\t%d\n", r.LongestLosingStreak)
+	fmt.Fprintf(tw, "Sharpe\t%.2f\n", r.Sharpe)
+	fmt.Fprintf(tw, "Sortino\t%.2f\n", r.Sortino)
+	fmt.Fprintf(tw, "Calmar\t%s\n", formatOptional(r.Calmar))
+	fmt.Fprintf(tw, "Max drawdown\t%.2f\n", r.MaxDrawdown)
+
+	return tw.Flush()
+}
+
+func formatOptional(v *float64) string {
+	if v == nil {
+		return "n/a"
+	}
+
+	return fmt.Sprintf("%.2f", *v)
+}
@@ -3,11 +3,24 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"slices"
+	"time"
 
+	"github.com/ksysoev/deriv-bot/pkg/api"
+	"github.com/ksysoev/deriv-bot/pkg/core/chatops"
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
 	"github.com/ksysoev/deriv-bot/pkg/core/executor"
+	"github.com/ksysoev/deriv-bot/pkg/core/market"
+	"github.com/ksysoev/deriv-bot/pkg/core/notifier"
 	"github.com/ksysoev/deriv-bot/pkg/core/signal"
 	"github.com/ksysoev/deriv-bot/pkg/prov/deriv"
+	"github.com/ksysoev/deriv-bot/pkg/prov/paper"
+	"github.com/ksysoev/deriv-bot/pkg/prov/secret"
+	"github.com/ksysoev/deriv-bot/pkg/prov/synthetic"
 	"github.com/ksysoev/deriv-bot/pkg/repo/subsmng"
+	"github.com/ksysoev/deriv-bot/pkg/repo/tickfile"
+	"golang.org/x/sync/errgroup"
 )
 
 func runAllServices(ctx context.Context, args *cmdArgs) error {
@@ -20,37 +33,173 @@ func runAllServices(ctx context.Context, args *cmdArgs) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	derivApi, err := deriv.New(cfg.Deriv)
+	if err := validateConfig(cfg); err != nil {
+		return fmt.Errorf("invalid config:\n%w", err)
+	}
+
+	resolver := secret.New(cfg.Secrets)
+	enableRedaction(resolver)
+
+	strategies, err := buildStrategies(cfg, resolver)
+	if err != nil {
+		return fmt.Errorf("failed to build strategies: %w", err)
+	}
+
+	sinks, err := buildSinks(cfg, resolver)
+	if err != nil {
+		return fmt.Errorf("failed to build notification sinks: %w", err)
+	}
+
+	chatBot, err := buildChatBot(cfg, resolver)
+	if err != nil {
+		return fmt.Errorf("failed to build chat bot: %w", err)
+	}
+
+	apiToken, err := resolveAPIToken(cfg, resolver)
+	if err != nil {
+		return fmt.Errorf("failed to resolve control API token: %w", err)
+	}
+
+	marketData, trading, closeProviders, err := buildProviders(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to create Deriv API client: %w", err)
+		return err
+	}
+
+	defer closeProviders()
+
+	var catalogue *market.Catalogue
+
+	if prov, ok := trading.(market.Provider); ok {
+		if catalogue, err = loadCatalogue(ctx, prov, strategies); err != nil {
+			return err
+		}
+	}
+
+	events := event.NewBus()
+
+	marketSignals := signal.New(marketData, subsmng.New(), events)
+	marketSignals.SetWatchdog(cfg.Market.Watchdog)
+	marketSignals.SetQueue(signal.QueueConfig(cfg.Deriv.Ticks))
+
+	var recorder *tickfile.Recorder
+
+	if cfg.Recorder.Dir != "" {
+		recorder = tickfile.NewRecorder(cfg.Recorder)
+		marketSignals.SetRecorder(recorder)
+	}
+
+	exec := executor.New(marketSignals, trading, events)
+
+	if catalogue != nil {
+		exec.SetMarketHours(catalogue)
+		exec.SetContractLimits(catalogue)
+	}
+
+	for _, strategy := range strategies {
+		if err := exec.AddStrategy(strategy); err != nil {
+			return fmt.Errorf("failed to add strategy: %w", err)
+		}
+	}
+
+	eg, ctx := errgroup.WithContext(ctx)
+
+	eg.Go(func() error { return exec.Run(ctx) })
+
+	// A nil catalogue must not be wrapped into a non-nil interface.
+	var symbols symbolCatalogue
+	if catalogue != nil {
+		symbols = catalogue
+	}
+
+	reloader := newConfigReloader(args, cfg, resolver, exec, symbols)
+
+	eg.Go(func() error { return reloader.Run(ctx) })
+
+	if recorder != nil {
+		eg.Go(func() error { return recorder.Run(ctx) })
 	}
 
-	defer derivApi.Close()
+	if catalogue != nil {
+		eg.Go(func() error { return catalogue.Run(ctx) })
+	}
 
-	marketSignals := signal.New(derivApi, subsmng.New())
+	if len(sinks) > 0 {
+		eg.Go(func() error { return notifier.New(events, sinks).Run(ctx) })
+	}
 
-	exec := executor.New(marketSignals, derivApi)
+	if chatBot != nil {
+		eg.Go(func() error { return chatops.New(exec, chatBot, cfg.ChatCommands.AllowedChats).Run(ctx) })
+	}
 
-	initPrice := float64(0)
-	strategy := executor.Strategy{
-		Token:    args.Token,
-		Symbol:   "R_100",
-		Amount:   10,
-		Type:     executor.StrategyTypeBuy,
-		Leverage: 10,
-		CheckToOpen: func(tick signal.Tick) bool {
-			initPrice = tick.Quote
-			return true
-		},
-		CheckToClose: func(tick signal.Tick) bool {
-			return tick.Quote > initPrice*1.01
-		},
+	if cfg.API.Listen != "" {
+		eg.Go(func() error { return api.New(cfg.API, apiToken.Reveal(), exec, events).Run(ctx) })
 	}
 
-	err = exec.ExecuteStrategy(ctx, strategy)
+	return eg.Wait()
+}
+
+// buildProviders creates the market data and trading providers selected by the market section.
+// Returns the providers, a function releasing them and an error if they can't be created.
+func buildProviders(cfg *appConfig) (signal.MarketProvider, executor.TradingProvider, func(), error) {
+	if cfg.Market.Provider == marketSynthetic {
+		synth, err := synthetic.New(cfg.Market.Synthetic)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to create synthetic market: %w", err)
+		}
+
+		slog.Warn("Using synthetic market data and paper trading, no real orders are placed")
+
+		trader := paper.New(cfg.Market.Paper, synth)
+		synth.OnQuote(trader.Update)
+
+		return synth, trader, func() {}, nil
+	}
+
+	derivApi, err := deriv.New(cfg.Deriv)
 	if err != nil {
-		return fmt.Errorf("failed to subscribe to ticks: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to create Deriv API client: %w", err)
+	}
+
+	return derivApi, derivApi, derivApi.Close, nil
+}
+
+// loadCatalogue loads the symbols offered by prov and checks the symbols of strategies are active.
+// Returns the catalogue and an error if a symbol is unknown or the catalogue can't be loaded.
+func loadCatalogue(ctx context.Context, prov market.Provider, strategies []executor.Strategy) (*market.Catalogue, error) {
+	symbols := strategySymbols(strategies)
+	catalogue := market.New(prov)
+
+	if err := catalogue.Load(ctx, symbols); err != nil {
+		return nil, fmt.Errorf("failed to load market catalogue: %w", err)
+	}
+
+	for _, name := range symbols {
+		sym, _ := catalogue.Symbol(name)
+
+		slog.Info("Loaded symbol",
+			slog.String("symbol", sym.Name),
+			slog.String("market", sym.Market),
+			slog.String("submarket", sym.Submarket),
+			slog.Float64("pip", sym.Pip),
+			slog.Any("multipliers", sym.Limits.Multipliers),
+			slog.Float64("min_stake", sym.Limits.MinStake),
+			slog.Float64("max_stake", sym.Limits.MaxStake),
+			slog.Bool("open", catalogue.IsOpen(sym.Name, time.Now())),
+		)
+	}
+
+	return catalogue, nil
+}
+
+// strategySymbols returns the distinct symbols traded by strategies.
+func strategySymbols(strategies []executor.Strategy) []string {
+	symbols := make([]string, 0, len(strategies))
+
+	for _, s := range strategies {
+		if !slices.Contains(symbols, s.Symbol) {
+			symbols = append(symbols, s.Symbol)
+		}
 	}
 
-	return nil
+	return symbols
 }
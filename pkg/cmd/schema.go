@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
+	"github.com/ksysoev/deriv-bot/pkg/core/rules"
+)
+
+// schemaRules holds constraints added to the generated schema, keyed by config path.
+// Array items are addressed with [] and map values with .*, e.g. strategies[].type or tokens.*.
+var schemaRules = map[string]map[string]any{
+	"market.provider":                               {"enum": marketProviders},
+	"market.synthetic.default.model":                {"enum": syntheticModels},
+	"market.synthetic.symbols.*.model":              {"enum": syntheticModels},
+	"market.synthetic.interval":                     {"description": "Go duration, e.g. 500ms or 1s"},
+	"recorder.flush_interval":                       {"description": "Go duration, e.g. 5s"},
+	"market.paper.balance":                          {"minimum": 0},
+	"deriv.endpoint":                                {"pattern": "^wss?://"},
+	"deriv.app_id":                                  {"minimum": 1},
+	"deriv.ticks.buffer":                            {"minimum": 0, "description": "Ticks queued per subscription while the bot is busy, defaults to 100"},
+	"deriv.ticks.conflate":                          {"description": "Keep only the latest tick per symbol instead of queueing"},
+	"deriv.rate_limits.trading":                     {"description": "Budget of trading and account requests, zero values use the defaults"},
+	"deriv.rate_limits.market_data":                 {"description": "Budget of tick subscriptions and market catalogue requests, zero values use the defaults"},
+	"deriv.rate_limits.trading.rate_per_minute":     {"minimum": 0},
+	"deriv.rate_limits.trading.burst":               {"minimum": 0},
+	"deriv.rate_limits.market_data.rate_per_minute": {"minimum": 0},
+	"deriv.rate_limits.market_data.burst":           {"minimum": 0},
+	"tokens.*":                                      {"pattern": "^(env|file|keystore):.+"},
+	"strategies":                                    {"minItems": 1},
+	"strategies[]":                                  {"required": []string{"name", "token", "symbol", "type", "amount", "leverage"}},
+	"strategies[].type":                             {"enum": strategyTypes},
+	"strategies[].rule":                             {"enum": rules.Names},
+	"strategies[].amount":                           {"exclusiveMinimum": 0},
+	"strategies[].leverage":                         {"enum": allowedLeverages},
+	"strategies[].shutdown.mode":                    {"enum": shutdownModes},
+	"strategies[].shutdown.fallback":                {"enum": shutdownModes},
+	"strategies[].stale_data.action":                {"enum": staleActions},
+	"strategies[].stale_data.after":                 {"description": "Go duration, e.g. 30s, no ticks for this long triggers the action"},
+	"strategies[].exit.trailing_stop.mode":          {"enum": trailingModes},
+	"strategies[].exit.trailing_stop.distance":      {"minimum": 0, "description": "Distance of the stop from the best quote: price units, percent or ATR multiples by mode"},
+	"strategies[].exit.trailing_stop.atr_period":    {"minimum": 0, "description": "Number of tick changes averaged for the atr mode, 14 by default"},
+	"strategies[].exit.break_even.profit_pct":       {"minimum": 0, "description": "Favorable move in percent after which the stop moves to the entry price"},
+	"strategies[].exit.break_even.offset_pct":       {"minimum": 0, "description": "Distance of the break-even stop above the entry price in percent, locking in a small profit"},
+	"strategies[].exit.retrace.profit_pct":          {"minimum": 0, "description": "Favorable move in percent after which retraces are watched"},
+	"strategies[].exit.retrace.retrace_pct":         {"minimum": 0, "maximum": 100, "description": "Share of the best profit in percent given back before the position is closed"},
+	"strategies[].schedule.timezone":                {"description": "IANA time zone of days, hours and blackouts, e.g. Europe/London, UTC by default"},
+	"strategies[].schedule.days[]":                  {"enum": weekdays},
+	"strategies[].schedule.hours[]":                 {"pattern": "^([01][0-9]|2[0-3]):[0-5][0-9]-(([01][0-9]|2[0-3]):[0-5][0-9]|24:00)$", "description": "HH:MM-HH:MM, a window ending before it starts spans midnight"},
+	"strategies[].schedule.blackouts[]":             {"required": []string{"from", "to"}},
+	"strategies[].schedule.blackouts[].from":        {"description": "YYYY-MM-DD HH:MM in the schedule timezone"},
+	"strategies[].schedule.blackouts[].to":          {"description": "YYYY-MM-DD HH:MM in the schedule timezone"},
+	"strategies[].schedule.max_holding":             {"description": "Go duration, e.g. 4h, positions held for this long are closed"},
+	"strategies[].cooldown":                         {"description": "Go duration, e.g. 5m, no new position is opened for this long after a close"},
+	"strategies[].max_trades_per_day":               {"minimum": 0, "description": "Positions opened per UTC day, 0 for no limit"},
+	"market.watchdog.stale_after":                   {"description": "Go duration, e.g. 1m, a symbol without ticks for this long is reported as stale"},
+	"market.watchdog.max_jump":                      {"minimum": 0, "description": "Largest accepted change between consecutive quotes, as a fraction of the previous quote"},
+	"strategies[].shutdown.timeout":                 {"description": "Go duration, e.g. 30s or 5m"},
+	"notifications[]":                               {"required": []string{"name", "type"}},
+	"notifications[].type":                          {"enum": notificationTypes},
+	"notifications[].events[]":                      {"enum": event.Types},
+	"notifications[].rate_per_minute":               {"minimum": 0},
+	"notifications[].burst":                         {"minimum": 0},
+	"chat_commands.allowed_chats":                   {"minItems": 1},
+	"api.listen":                                    {"description": "Address of the control API, e.g. 127.0.0.1:8080, empty disables it"},
+	"api.token":                                     {"description": "Entry of tokens holding the bearer token required by requests changing the bot state"},
+}
+
+// configSchema builds the JSON Schema of the config file from the mapstructure tags of appConfig.
+// Unknown keys are rejected, matching the strict decoding of loadConfig.
+func configSchema() map[string]any {
+	s := schemaOf(reflect.TypeFor[appConfig](), "")
+	s["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	s["title"] = "Deriv bot configuration"
+
+	return s
+}
+
+func schemaOf(t reflect.Type, path string) map[string]any {
+	var s map[string]any
+
+	switch {
+	case t == reflect.TypeFor[time.Duration]():
+		s = map[string]any{"type": "string", "pattern": `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`}
+	case t.Kind() == reflect.Struct:
+		props := make(map[string]any, t.NumField())
+
+		for i := range t.NumField() {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("mapstructure"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+
+			props[name] = schemaOf(t.Field(i).Type, strings.TrimPrefix(path+"."+name, "."))
+		}
+
+		s = map[string]any{"type": "object", "properties": props, "additionalProperties": false}
+	case t.Kind() == reflect.Slice:
+		s = map[string]any{"type": "array", "items": schemaOf(t.Elem(), path+"[]")}
+	case t.Kind() == reflect.Map:
+		s = map[string]any{"type": "object", "additionalProperties": schemaOf(t.Elem(), path+".*")}
+	case t.Kind() == reflect.String:
+		s = map[string]any{"type": "string"}
+	case t.Kind() == reflect.Bool:
+		s = map[string]any{"type": "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		s = map[string]any{"type": "integer"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		s = map[string]any{"type": "number"}
+	default:
+		s = map[string]any{}
+	}
+
+	maps.Copy(s, schemaRules[path])
+
+	return s
+}
+
+// writeConfigSchema writes the indented JSON Schema of the config file to w.
+func writeConfigSchema(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(configSchema()); err != nil {
+		return fmt.Errorf("failed to encode config schema: %w", err)
+	}
+
+	return nil
+}
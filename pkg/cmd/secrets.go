@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ksysoev/deriv-bot/pkg/prov/secret"
+)
+
+func setSecret(args *cmdArgs, name string, in io.Reader) error {
+	ks, err := openKeystore(args)
+	if err != nil {
+		return err
+	}
+
+	val, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("failed to read secret: %w", err)
+	}
+
+	val = strings.TrimSpace(val)
+	if val == "" {
+		return fmt.Errorf("secret value is empty")
+	}
+
+	ks.Set(name, val)
+
+	return ks.Save()
+}
+
+func listSecrets(args *cmdArgs, out io.Writer) error {
+	ks, err := openKeystore(args)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range ks.Names() {
+		if _, err := fmt.Fprintln(out, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func deleteSecret(args *cmdArgs, name string) error {
+	ks, err := openKeystore(args)
+	if err != nil {
+		return err
+	}
+
+	if !ks.Delete(name) {
+		return fmt.Errorf("secret %q not found", name)
+	}
+
+	return ks.Save()
+}
+
+func openKeystore(args *cmdArgs) (*secret.Keystore, error) {
+	if err := initLogger(args); err != nil {
+		return nil, fmt.Errorf("failed to init logger: %w", err)
+	}
+
+	cfg, err := loadConfig(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	ks, err := secret.New(cfg.Secrets).Keystore()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open keystore: %w", err)
+	}
+
+	return ks, nil
+}
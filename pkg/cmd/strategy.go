@@ -0,0 +1,386 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/executor"
+	"github.com/ksysoev/deriv-bot/pkg/core/rules"
+	"github.com/ksysoev/deriv-bot/pkg/prov/secret"
+)
+
+// blackoutLayout is the layout of the bounds of schedule blackouts.
+const blackoutLayout = "2006-01-02 15:04"
+
+// weekdays are the names of schedule days, indexed by time.Weekday.
+var weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// defaultATRPeriod is the number of tick changes averaged by an ATR trailing stop without atr_period.
+const defaultATRPeriod = 14
+
+// strategyConfig defines a strategy. Rule selects when positions are opened and closed,
+// it defaults to take_profit, and Params overrides the default parameters of the rule.
+// ClampLimits lets the strategy start with the nearest accepted amount and leverage when the configured ones
+// are outside the contract limits of the symbol, otherwise it refuses to start.
+// Cooldown holds back new positions after a close, MaxTradesPerDay limits positions opened per UTC day.
+type strategyConfig struct {
+	Params          map[string]float64 `mapstructure:"params"`
+	Name            string             `mapstructure:"name"`
+	Token           string             `mapstructure:"token"`
+	Symbol          string             `mapstructure:"symbol"`
+	Type            string             `mapstructure:"type"`
+	Rule            string             `mapstructure:"rule"`
+	Amount          float64            `mapstructure:"amount"`
+	Leverage        float64            `mapstructure:"leverage"`
+	ClampLimits     bool               `mapstructure:"clamp_limits"`
+	Shutdown        shutdownConfig     `mapstructure:"shutdown"`
+	StaleData       staleDataConfig    `mapstructure:"stale_data"`
+	Exit            exitConfig         `mapstructure:"exit"`
+	Schedule        scheduleConfig     `mapstructure:"schedule"`
+	Cooldown        time.Duration      `mapstructure:"cooldown"`
+	MaxTradesPerDay int                `mapstructure:"max_trades_per_day"`
+}
+
+// staleDataConfig defines how a strategy reacts when no tick of its symbol arrived for After.
+// The check is disabled unless After is set.
+type staleDataConfig struct {
+	Action string        `mapstructure:"action"`
+	After  time.Duration `mapstructure:"after"`
+}
+
+// exitConfig defines exits evaluated on every tick against the open position, independently of the close rule.
+// Each exit is disabled unless its thresholds are set.
+type exitConfig struct {
+	TrailingStop trailingStopConfig `mapstructure:"trailing_stop"`
+	BreakEven    breakEvenConfig    `mapstructure:"break_even"`
+	Retrace      retraceConfig      `mapstructure:"retrace"`
+}
+
+// trailingStopConfig keeps the stop at Distance from the best quote, in price units, percent of the quote
+// or multiples of the tick ATR depending on Mode. ATRPeriod defaults to 14 ticks.
+type trailingStopConfig struct {
+	Mode      string  `mapstructure:"mode"`
+	Distance  float64 `mapstructure:"distance"`
+	ATRPeriod int     `mapstructure:"atr_period"`
+}
+
+type breakEvenConfig struct {
+	ProfitPct float64 `mapstructure:"profit_pct"`
+	OffsetPct float64 `mapstructure:"offset_pct"`
+}
+
+type retraceConfig struct {
+	ProfitPct  float64 `mapstructure:"profit_pct"`
+	RetracePct float64 `mapstructure:"retrace_pct"`
+}
+
+// scheduleConfig limits when a strategy opens positions. Days are three letter lower case names, Hours are
+// HH:MM-HH:MM windows, both in Timezone, which defaults to UTC. Blackouts are periods without new positions,
+// their bounds are "YYYY-MM-DD HH:MM" in Timezone. A position held for MaxHolding is closed.
+type scheduleConfig struct {
+	Timezone   string           `mapstructure:"timezone"`
+	Days       []string         `mapstructure:"days"`
+	Hours      []string         `mapstructure:"hours"`
+	Blackouts  []blackoutConfig `mapstructure:"blackouts"`
+	MaxHolding time.Duration    `mapstructure:"max_holding"`
+}
+
+type blackoutConfig struct {
+	From string `mapstructure:"from"`
+	To   string `mapstructure:"to"`
+}
+
+// shutdownConfig defines what happens with an open position of a strategy when the bot stops.
+// Mode defaults to leave, which keeps the behaviour of earlier versions.
+type shutdownConfig struct {
+	Mode       string        `mapstructure:"mode"`
+	Fallback   string        `mapstructure:"fallback"`
+	Timeout    time.Duration `mapstructure:"timeout"`
+	TakeProfit float64       `mapstructure:"take_profit"`
+	StopLoss   float64       `mapstructure:"stop_loss"`
+}
+
+// buildStrategies converts strategy configurations into executor strategies.
+// Each strategy references its API token by name, the reference is looked up in cfg.Tokens and resolved with resolver.
+// Returns the list of strategies and an error if a token is unknown or cannot be resolved, or a strategy type is invalid.
+func buildStrategies(cfg *appConfig, resolver *secret.Resolver) ([]executor.Strategy, error) {
+	strategies := make([]executor.Strategy, 0, len(cfg.Strategies))
+
+	for _, sc := range cfg.Strategies {
+		token, err := resolveToken(cfg, resolver, sc.Token)
+		if err != nil {
+			return nil, fmt.Errorf("strategy %s: %w", sc.Name, err)
+		}
+
+		strategy, err := buildStrategy(sc, token)
+		if err != nil {
+			return nil, err
+		}
+
+		strategies = append(strategies, strategy)
+	}
+
+	return strategies, nil
+}
+
+func buildStrategy(sc strategyConfig, token secret.Value) (executor.Strategy, error) {
+	var strategyType executor.StrategyType
+
+	switch sc.Type {
+	case "buy":
+		strategyType = executor.StrategyTypeBuy
+	case "sell":
+		strategyType = executor.StrategyTypeSell
+	default:
+		return executor.Strategy{}, fmt.Errorf("strategy %s has unknown type %q", sc.Name, sc.Type)
+	}
+
+	shutdown, err := buildShutdownPolicy(sc.Shutdown)
+	if err != nil {
+		return executor.Strategy{}, fmt.Errorf("strategy %s: %w", sc.Name, err)
+	}
+
+	staleData, err := buildStaleDataPolicy(sc.StaleData)
+	if err != nil {
+		return executor.Strategy{}, fmt.Errorf("strategy %s: %w", sc.Name, err)
+	}
+
+	exit, err := buildExitPolicy(sc.Exit)
+	if err != nil {
+		return executor.Strategy{}, fmt.Errorf("strategy %s: %w", sc.Name, err)
+	}
+
+	schedule, err := buildSchedule(sc.Schedule)
+	if err != nil {
+		return executor.Strategy{}, fmt.Errorf("strategy %s: %w", sc.Name, err)
+	}
+
+	rule, err := rules.New(sc.Rule, sc.Params, strategyType == executor.StrategyTypeSell)
+	if err != nil {
+		return executor.Strategy{}, fmt.Errorf("strategy %s: %w", sc.Name, err)
+	}
+
+	return executor.Strategy{
+		Name:            sc.Name,
+		Token:           token.Reveal(),
+		Symbol:          sc.Symbol,
+		Amount:          sc.Amount,
+		Type:            strategyType,
+		Leverage:        sc.Leverage,
+		ClampLimits:     sc.ClampLimits,
+		Shutdown:        shutdown,
+		StaleData:       staleData,
+		Exit:            exit,
+		Schedule:        schedule,
+		Cooldown:        sc.Cooldown,
+		MaxTradesPerDay: sc.MaxTradesPerDay,
+		WarmUp:          rule.WarmUp,
+		CheckToOpen:     rule.CheckToOpen,
+		CheckToClose:    rule.CheckToClose,
+	}, nil
+}
+
+func buildShutdownPolicy(sc shutdownConfig) (executor.ShutdownPolicy, error) {
+	policy := executor.ShutdownPolicy{
+		Mode:       executor.ShutdownMode(sc.Mode),
+		Fallback:   executor.ShutdownMode(sc.Fallback),
+		Timeout:    sc.Timeout,
+		TakeProfit: sc.TakeProfit,
+		StopLoss:   sc.StopLoss,
+	}
+
+	if policy.Mode == "" {
+		policy.Mode = executor.ShutdownLeave
+	}
+
+	if policy.Fallback == "" {
+		policy.Fallback = executor.ShutdownClose
+	}
+
+	for _, mode := range []executor.ShutdownMode{policy.Mode, policy.Fallback} {
+		switch mode {
+		case executor.ShutdownLeave, executor.ShutdownClose, executor.ShutdownProtect, executor.ShutdownWait:
+		default:
+			return executor.ShutdownPolicy{}, fmt.Errorf("unknown shutdown mode %q", mode)
+		}
+	}
+
+	switch {
+	case policy.Fallback == executor.ShutdownWait:
+		return executor.ShutdownPolicy{}, fmt.Errorf("shutdown fallback can't be %q", executor.ShutdownWait)
+	case policy.Mode == executor.ShutdownWait && policy.Timeout <= 0:
+		return executor.ShutdownPolicy{}, fmt.Errorf("shutdown timeout is required for %q mode", executor.ShutdownWait)
+	case usesProtect(policy) && policy.TakeProfit <= 0 && policy.StopLoss <= 0:
+		return executor.ShutdownPolicy{}, fmt.Errorf("take_profit or stop_loss is required for %q mode", executor.ShutdownProtect)
+	}
+
+	return policy, nil
+}
+
+func buildStaleDataPolicy(sc staleDataConfig) (executor.StaleDataPolicy, error) {
+	policy := executor.StaleDataPolicy{Action: executor.StaleAction(sc.Action), After: sc.After}
+
+	switch {
+	case policy.After < 0:
+		return executor.StaleDataPolicy{}, fmt.Errorf("stale data threshold must not be negative")
+	case policy.After == 0 && policy.Action != "":
+		return executor.StaleDataPolicy{}, fmt.Errorf("stale data threshold is required for an action")
+	case policy.After == 0:
+		return policy, nil
+	}
+
+	switch policy.Action {
+	case executor.StaleResubscribe, executor.StalePause, executor.StaleClose:
+	case "":
+		return executor.StaleDataPolicy{}, fmt.Errorf("stale data action is required")
+	default:
+		return executor.StaleDataPolicy{}, fmt.Errorf("unknown stale data action %q", policy.Action)
+	}
+
+	return policy, nil
+}
+
+func buildExitPolicy(sc exitConfig) (executor.ExitPolicy, error) {
+	policy := executor.ExitPolicy{
+		TrailingStop: executor.TrailingStop{
+			Mode:      executor.TrailingMode(sc.TrailingStop.Mode),
+			Distance:  sc.TrailingStop.Distance,
+			ATRPeriod: sc.TrailingStop.ATRPeriod,
+		},
+		BreakEven: executor.BreakEven{ProfitPct: sc.BreakEven.ProfitPct, OffsetPct: sc.BreakEven.OffsetPct},
+		Retrace:   executor.Retrace{ProfitPct: sc.Retrace.ProfitPct, RetracePct: sc.Retrace.RetracePct},
+	}
+
+	ts := &policy.TrailingStop
+
+	switch {
+	case ts.Distance < 0:
+		return executor.ExitPolicy{}, fmt.Errorf("trailing stop distance must not be negative")
+	case ts.ATRPeriod < 0:
+		return executor.ExitPolicy{}, fmt.Errorf("trailing stop atr_period must not be negative")
+	case ts.Distance == 0 && ts.Mode != "":
+		return executor.ExitPolicy{}, fmt.Errorf("trailing stop distance is required for a mode")
+	case ts.Distance > 0 && ts.Mode == "":
+		return executor.ExitPolicy{}, fmt.Errorf("trailing stop mode is required")
+	}
+
+	switch ts.Mode {
+	case executor.TrailingPrice, executor.TrailingPercent, "":
+	case executor.TrailingATR:
+		if ts.ATRPeriod == 0 {
+			ts.ATRPeriod = defaultATRPeriod
+		}
+	default:
+		return executor.ExitPolicy{}, fmt.Errorf("unknown trailing stop mode %q", ts.Mode)
+	}
+
+	be, rt := policy.BreakEven, policy.Retrace
+
+	switch {
+	case be.ProfitPct < 0 || be.OffsetPct < 0:
+		return executor.ExitPolicy{}, fmt.Errorf("break even thresholds must not be negative")
+	case be.OffsetPct >= be.ProfitPct && be.ProfitPct > 0:
+		return executor.ExitPolicy{}, fmt.Errorf("break even offset_pct must be below profit_pct")
+	case rt.ProfitPct < 0 || rt.RetracePct < 0 || rt.RetracePct > 100:
+		return executor.ExitPolicy{}, fmt.Errorf("retrace retrace_pct must be between 0 and 100, profit_pct must not be negative")
+	case (rt.ProfitPct > 0) != (rt.RetracePct > 0):
+		return executor.ExitPolicy{}, fmt.Errorf("retrace requires both profit_pct and retrace_pct")
+	}
+
+	return policy, nil
+}
+
+func buildSchedule(sc scheduleConfig) (executor.Schedule, error) {
+	loc := time.UTC
+
+	if sc.Timezone != "" {
+		var err error
+
+		if loc, err = time.LoadLocation(sc.Timezone); err != nil {
+			return executor.Schedule{}, fmt.Errorf("unknown schedule timezone %q", sc.Timezone)
+		}
+	}
+
+	schedule := executor.Schedule{Location: loc, MaxHolding: sc.MaxHolding}
+
+	if sc.MaxHolding < 0 {
+		return executor.Schedule{}, fmt.Errorf("schedule max_holding must not be negative")
+	}
+
+	for _, name := range sc.Days {
+		day := slices.Index(weekdays, name)
+		if day < 0 {
+			return executor.Schedule{}, fmt.Errorf("unknown schedule day %q, expected one of %v", name, weekdays)
+		}
+
+		schedule.Days = append(schedule.Days, time.Weekday(day))
+	}
+
+	for _, hours := range sc.Hours {
+		from, to, ok := strings.Cut(hours, "-")
+		if !ok {
+			return executor.Schedule{}, fmt.Errorf("invalid schedule hours %q, expected HH:MM-HH:MM", hours)
+		}
+
+		fromOffset, errFrom := parseClock(from)
+		toOffset, errTo := parseClock(to)
+
+		if errFrom != nil || errTo != nil || fromOffset == toOffset {
+			return executor.Schedule{}, fmt.Errorf("invalid schedule hours %q, expected HH:MM-HH:MM", hours)
+		}
+
+		schedule.Hours = append(schedule.Hours, executor.DayWindow{From: fromOffset, To: toOffset})
+	}
+
+	for _, b := range sc.Blackouts {
+		from, errFrom := time.ParseInLocation(blackoutLayout, b.From, loc)
+		to, errTo := time.ParseInLocation(blackoutLayout, b.To, loc)
+
+		switch {
+		case errFrom != nil || errTo != nil:
+			return executor.Schedule{}, fmt.Errorf("invalid schedule blackout %s - %s, expected %q", b.From, b.To, blackoutLayout)
+		case !to.After(from):
+			return executor.Schedule{}, fmt.Errorf("schedule blackout %s - %s ends before it starts", b.From, b.To)
+		}
+
+		schedule.Blackouts = append(schedule.Blackouts, executor.Window{From: from, To: to})
+	}
+
+	return schedule, nil
+}
+
+// parseClock parses a HH:MM time of day, 24:00 marks the end of the day.
+func parseClock(s string) (time.Duration, error) {
+	if s == "24:00" {
+		return 24 * time.Hour, nil
+	}
+
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func usesProtect(p executor.ShutdownPolicy) bool {
+	return p.Mode == executor.ShutdownProtect || (p.Mode == executor.ShutdownWait && p.Fallback == executor.ShutdownProtect)
+}
+
+// resolveToken looks up the secret reference registered under name in cfg.Tokens and resolves it.
+// Returns an error if the name is unknown or the secret cannot be resolved.
+func resolveToken(cfg *appConfig, resolver *secret.Resolver, name string) (secret.Value, error) {
+	ref, ok := cfg.Tokens[name]
+	if !ok {
+		return "", fmt.Errorf("unknown token %q", name)
+	}
+
+	token, err := resolver.Resolve(ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve token %q: %w", name, err)
+	}
+
+	return token, nil
+}
@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"slices"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
+	"github.com/ksysoev/deriv-bot/pkg/core/executor"
+	"github.com/ksysoev/deriv-bot/pkg/core/rules"
+	"github.com/ksysoev/deriv-bot/pkg/prov/deriv"
+	"github.com/ksysoev/deriv-bot/pkg/prov/secret"
+	"github.com/ksysoev/deriv-bot/pkg/prov/synthetic"
+)
+
+var (
+	strategyTypes     = []string{"buy", "sell"}
+	notificationTypes = []string{"telegram", "slack", "webhook"}
+	marketProviders   = []string{marketDeriv, marketSynthetic}
+	syntheticModels   = []string{synthetic.ModelRandomWalk, synthetic.ModelGBM, synthetic.ModelJump, synthetic.ModelReplay}
+	shutdownModes     = []string{
+		string(executor.ShutdownLeave),
+		string(executor.ShutdownClose),
+		string(executor.ShutdownProtect),
+		string(executor.ShutdownWait),
+	}
+	staleActions = []string{
+		string(executor.StaleResubscribe),
+		string(executor.StalePause),
+		string(executor.StaleClose),
+	}
+	trailingModes = []string{
+		string(executor.TrailingPrice),
+		string(executor.TrailingPercent),
+		string(executor.TrailingATR),
+	}
+
+	// allowedLeverages are the multipliers offered by Deriv for multiplier contracts across markets.
+	// A symbol supports only a subset of them, which is checked by the API when an order is placed.
+	allowedLeverages = []float64{1, 2, 3, 5, 10, 20, 25, 30, 40, 50, 60, 80, 100, 150, 200, 250, 300, 400, 500, 750, 1000, 1500, 2000}
+)
+
+// validateConfig checks required fields, value ranges and references between config sections.
+// Returns all problems found joined in a single error, each prefixed with the config path it relates to.
+func validateConfig(cfg *appConfig) error {
+	v := &validator{}
+
+	if cfg.Market.Provider != "" {
+		v.oneOf("market.provider", cfg.Market.Provider, marketProviders)
+	}
+
+	if cfg.Market.Watchdog.StaleAfter < 0 {
+		v.fail("market.watchdog.stale_after", "must not be negative")
+	}
+
+	if cfg.Market.Watchdog.MaxJump < 0 {
+		v.fail("market.watchdog.max_jump", "must not be negative")
+	}
+
+	if cfg.Market.Provider == marketSynthetic {
+		if _, err := synthetic.New(cfg.Market.Synthetic); err != nil {
+			v.fail("market.synthetic", err.Error())
+		}
+
+		if cfg.Market.Paper.Balance < 0 {
+			v.fail("market.paper.balance", "must not be negative")
+		}
+	} else {
+		if cfg.Deriv.Endpoint == "" {
+			v.fail("deriv.endpoint", "is required")
+		} else if u, err := url.Parse(cfg.Deriv.Endpoint); err != nil || (u.Scheme != "ws" && u.Scheme != "wss") {
+			v.fail("deriv.endpoint", "must be a ws:// or wss:// URL")
+		}
+
+		if cfg.Deriv.AppID <= 0 {
+			v.fail("deriv.app_id", "must be a positive number")
+		}
+
+		limits := []struct {
+			path  string
+			limit deriv.RateLimit
+		}{
+			{"deriv.rate_limits.trading", cfg.Deriv.RateLimits.Trading},
+			{"deriv.rate_limits.market_data", cfg.Deriv.RateLimits.MarketData},
+		}
+
+		if cfg.Deriv.Ticks.Buffer < 0 {
+			v.fail("deriv.ticks.buffer", "must not be negative")
+		}
+
+		for _, l := range limits {
+			if l.limit.RatePerMinute < 0 {
+				v.fail(l.path+".rate_per_minute", "must not be negative")
+			}
+
+			if l.limit.Burst < 0 {
+				v.fail(l.path+".burst", "must not be negative")
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(cfg.Tokens)) {
+		if err := secret.ValidateRef(cfg.Tokens[name]); err != nil {
+			v.fail("tokens."+name, err.Error())
+		}
+	}
+
+	if len(cfg.Strategies) == 0 {
+		v.fail("strategies", "at least one strategy is required")
+	}
+
+	names := make(map[string]struct{}, len(cfg.Strategies))
+
+	for i, sc := range cfg.Strategies {
+		path := fmt.Sprintf("strategies[%d]", i)
+
+		if sc.Name == "" {
+			v.fail(path+".name", "is required")
+		} else if _, ok := names[sc.Name]; ok {
+			v.fail(path+".name", fmt.Sprintf("duplicate strategy name %q", sc.Name))
+		}
+
+		names[sc.Name] = struct{}{}
+
+		v.tokenRef(cfg, path+".token", sc.Token, true)
+
+		if sc.Symbol == "" {
+			v.fail(path+".symbol", "is required")
+		}
+
+		v.oneOf(path+".type", sc.Type, strategyTypes)
+
+		if _, err := rules.New(sc.Rule, sc.Params, sc.Type == "sell"); err != nil {
+			v.fail(path+".rule", err.Error())
+		}
+
+		if sc.Amount <= 0 {
+			v.fail(path+".amount", "must be positive")
+		}
+
+		if !slices.Contains(allowedLeverages, sc.Leverage) {
+			v.fail(path+".leverage", fmt.Sprintf("must be one of %v", allowedLeverages))
+		}
+
+		if _, err := buildShutdownPolicy(sc.Shutdown); err != nil {
+			v.fail(path+".shutdown", err.Error())
+		}
+
+		if _, err := buildStaleDataPolicy(sc.StaleData); err != nil {
+			v.fail(path+".stale_data", err.Error())
+		}
+
+		if _, err := buildExitPolicy(sc.Exit); err != nil {
+			v.fail(path+".exit", err.Error())
+		}
+
+		if _, err := buildSchedule(sc.Schedule); err != nil {
+			v.fail(path+".schedule", err.Error())
+		}
+
+		if sc.Cooldown < 0 {
+			v.fail(path+".cooldown", "must not be negative")
+		}
+
+		if sc.MaxTradesPerDay < 0 {
+			v.fail(path+".max_trades_per_day", "must not be negative")
+		}
+	}
+
+	for i, nc := range cfg.Notifications {
+		path := fmt.Sprintf("notifications[%d]", i)
+
+		if nc.Name == "" {
+			v.fail(path+".name", "is required")
+		}
+
+		v.oneOf(path+".type", nc.Type, notificationTypes)
+
+		switch nc.Type {
+		case "telegram":
+			v.tokenRef(cfg, path+".token", nc.Token, true)
+
+			if nc.ChatID == "" {
+				v.fail(path+".chat_id", "is required for telegram")
+			}
+		case "slack":
+			v.tokenRef(cfg, path+".token", nc.Token, true)
+		case "webhook":
+			v.tokenRef(cfg, path+".token", nc.Token, false)
+
+			if nc.URL == "" {
+				v.fail(path+".url", "is required for webhook")
+			}
+		}
+
+		for j, e := range nc.Events {
+			if !slices.Contains(event.Types, event.Type(e)) {
+				v.fail(fmt.Sprintf("%s.events[%d]", path, j), fmt.Sprintf("unknown event type %q", e))
+			}
+		}
+
+		if nc.RatePerMinute < 0 {
+			v.fail(path+".rate_per_minute", "must not be negative")
+		}
+
+		if nc.Burst < 0 {
+			v.fail(path+".burst", "must not be negative")
+		}
+	}
+
+	if cfg.API.Listen != "" {
+		v.tokenRef(cfg, "api.token", cfg.API.Token, true)
+	}
+
+	if cfg.ChatCommands.Token != "" {
+		v.tokenRef(cfg, "chat_commands.token", cfg.ChatCommands.Token, true)
+
+		if len(cfg.ChatCommands.AllowedChats) == 0 {
+			v.fail("chat_commands.allowed_chats", "at least one chat is required")
+		}
+	}
+
+	return errors.Join(v.errs...)
+}
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) fail(path, msg string) {
+	v.errs = append(v.errs, fmt.Errorf("%s: %s", path, msg))
+}
+
+func (v *validator) oneOf(path, val string, allowed []string) {
+	if !slices.Contains(allowed, val) {
+		v.fail(path, fmt.Sprintf("must be one of %v, got %q", allowed, val))
+	}
+}
+
+// tokenRef checks that name references an entry of the tokens section.
+func (v *validator) tokenRef(cfg *appConfig, path, name string, required bool) {
+	if name == "" {
+		if required {
+			v.fail(path, "is required")
+		}
+
+		return
+	}
+
+	if _, ok := cfg.Tokens[name]; !ok {
+		v.fail(path, fmt.Sprintf("unknown token %q", name))
+	}
+}
+
+// validateConfigFile loads and validates the config, printing the result to w.
+// Returns an error if the config can't be loaded or is invalid.
+func validateConfigFile(args *cmdArgs, w io.Writer) error {
+	cfg, err := loadConfig(args)
+	if err != nil {
+		return err
+	}
+
+	if err := validateConfig(cfg); err != nil {
+		return fmt.Errorf("invalid config:\n%w", err)
+	}
+
+	_, err = fmt.Fprintln(w, "Config is valid")
+
+	return err
+}
@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/api"
+	"github.com/ksysoev/deriv-bot/pkg/prov/deriv"
+	"github.com/ksysoev/deriv-bot/pkg/prov/synthetic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validConfig() *appConfig {
+	return &appConfig{
+		Deriv:  deriv.Config{Endpoint: "wss://ws.derivws.com/websockets/v3", AppID: 1},
+		API:    api.Config{Listen: "127.0.0.1:8080", Token: "api"},
+		Tokens: map[string]string{"demo": "env:DERIV_TOKEN", "api": "env:BOT_API_TOKEN"},
+		Strategies: []strategyConfig{
+			{Name: "r100", Token: "demo", Symbol: "R_100", Type: "buy", Amount: 10, Leverage: 100},
+		},
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		modify  func(cfg *appConfig)
+		name    string
+		wantErr []string
+	}{
+		{
+			name:   "valid",
+			modify: func(*appConfig) {},
+		},
+		{
+			name: "missing deriv settings",
+			modify: func(cfg *appConfig) {
+				cfg.Deriv = deriv.Config{}
+			},
+			wantErr: []string{"deriv.endpoint: is required", "deriv.app_id: must be a positive number"},
+		},
+		{
+			name: "invalid stale data policy",
+			modify: func(cfg *appConfig) {
+				cfg.Strategies[0].StaleData = staleDataConfig{Action: "panic", After: time.Minute}
+				cfg.Market.Watchdog.MaxJump = -0.1
+			},
+			wantErr: []string{
+				"market.watchdog.max_jump: must not be negative",
+				`strategies[0].stale_data: unknown stale data action "panic"`,
+			},
+		},
+		{
+			name: "negative rate limits",
+			modify: func(cfg *appConfig) {
+				cfg.Deriv.RateLimits.Trading.RatePerMinute = -1
+				cfg.Deriv.RateLimits.MarketData.Burst = -1
+				cfg.Deriv.Ticks.Buffer = -1
+			},
+			wantErr: []string{
+				"deriv.ticks.buffer: must not be negative",
+				"deriv.rate_limits.trading.rate_per_minute: must not be negative",
+				"deriv.rate_limits.market_data.burst: must not be negative",
+			},
+		},
+		{
+			name: "synthetic market without deriv settings",
+			modify: func(cfg *appConfig) {
+				cfg.Deriv = deriv.Config{}
+				cfg.Market.Provider = marketSynthetic
+			},
+		},
+		{
+			name: "invalid market",
+			modify: func(cfg *appConfig) {
+				cfg.Market.Provider = "mock"
+			},
+			wantErr: []string{`market.provider: must be one of [deriv synthetic], got "mock"`},
+		},
+		{
+			name: "invalid synthetic model",
+			modify: func(cfg *appConfig) {
+				cfg.Market.Provider = marketSynthetic
+				cfg.Market.Synthetic.Symbols = map[string]synthetic.ModelConfig{"R_100": {Model: "random_walk", Start: 100}}
+			},
+			wantErr: []string{"market.synthetic: invalid synthetic model for R_100: step must be positive for random_walk model"},
+		},
+		{
+			name: "invalid endpoint",
+			modify: func(cfg *appConfig) {
+				cfg.Deriv.Endpoint = "https://example.com"
+			},
+			wantErr: []string{"deriv.endpoint: must be a ws:// or wss:// URL"},
+		},
+		{
+			name: "invalid strategy values",
+			modify: func(cfg *appConfig) {
+				cfg.Strategies[0].Amount = 0
+				cfg.Strategies[0].Leverage = 7
+				cfg.Strategies[0].Type = "long"
+			},
+			wantErr: []string{
+				"strategies[0].amount: must be positive",
+				"strategies[0].leverage: must be one of",
+				`strategies[0].type: must be one of [buy sell], got "long"`,
+			},
+		},
+		{
+			name: "broken references",
+			modify: func(cfg *appConfig) {
+				cfg.Tokens["raw"] = "abc123"
+				cfg.Strategies = append(cfg.Strategies, cfg.Strategies[0])
+				cfg.Strategies[1].Token = "missing"
+			},
+			wantErr: []string{
+				"tokens.raw: invalid secret reference",
+				`strategies[1].name: duplicate strategy name "r100"`,
+				`strategies[1].token: unknown token "missing"`,
+			},
+		},
+		{
+			name: "invalid notification",
+			modify: func(cfg *appConfig) {
+				cfg.Notifications = []notificationConfig{{Name: "tg", Type: "telegram", Events: []string{"trade"}}}
+			},
+			wantErr: []string{
+				"notifications[0].token: is required",
+				"notifications[0].chat_id: is required for telegram",
+				`notifications[0].events[0]: unknown event type "trade"`,
+			},
+		},
+		{
+			name: "control API without token",
+			modify: func(cfg *appConfig) {
+				cfg.API.Token = ""
+			},
+			wantErr: []string{"api.token: is required"},
+		},
+		{
+			name: "control API disabled",
+			modify: func(cfg *appConfig) {
+				cfg.API = api.Config{}
+			},
+		},
+		{
+			name: "invalid rule parameters",
+			modify: func(cfg *appConfig) {
+				cfg.Strategies[0].Rule = "ema_cross"
+				cfg.Strategies[0].Params = map[string]float64{"fast": 30, "slow": 20}
+			},
+			wantErr: []string{"strategies[0].rule: slow must be an integer greater than fast"},
+		},
+		{
+			name: "negative cooldown and trade limit",
+			modify: func(cfg *appConfig) {
+				cfg.Strategies[0].Cooldown = -time.Minute
+				cfg.Strategies[0].MaxTradesPerDay = -1
+			},
+			wantErr: []string{
+				"strategies[0].cooldown: must not be negative",
+				"strategies[0].max_trades_per_day: must not be negative",
+			},
+		},
+		{
+			name: "invalid exit policy",
+			modify: func(cfg *appConfig) {
+				cfg.Strategies[0].Exit.TrailingStop = trailingStopConfig{Mode: "ticks", Distance: 2}
+			},
+			wantErr: []string{`strategies[0].exit: unknown trailing stop mode "ticks"`},
+		},
+		{
+			name: "invalid schedule",
+			modify: func(cfg *appConfig) {
+				cfg.Strategies[0].Schedule = scheduleConfig{Timezone: "Europe/London", Days: []string{"mon"}, Hours: []string{"9-17"}}
+				cfg.Strategies = append(cfg.Strategies, cfg.Strategies[0])
+				cfg.Strategies[1].Name = "r100-blackout"
+				cfg.Strategies[1].Schedule = scheduleConfig{
+					Blackouts: []blackoutConfig{{From: "2026-12-25 00:00", To: "2026-12-24 00:00"}},
+				}
+			},
+			wantErr: []string{
+				`strategies[0].schedule: invalid schedule hours "9-17", expected HH:MM-HH:MM`,
+				"strategies[1].schedule: schedule blackout 2026-12-25 00:00 - 2026-12-24 00:00 ends before it starts",
+			},
+		},
+		{
+			name: "invalid shutdown policy",
+			modify: func(cfg *appConfig) {
+				cfg.Strategies[0].Shutdown = shutdownConfig{Mode: "wait"}
+			},
+			wantErr: []string{`strategies[0].shutdown: shutdown timeout is required for "wait" mode`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+
+			err := validateConfig(cfg)
+
+			if len(tt.wantErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+
+			for _, want := range tt.wantErr {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_UnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(path, []byte("deriv:\n  appid: 1\n"), 0o600))
+
+	_, err := loadConfig(&cmdArgs{ConfigPath: path})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "appid")
+}
+
+func TestConfigSchema_UpToDate(t *testing.T) {
+	want, err := os.ReadFile("../../runtime/config.schema.json")
+	require.NoError(t, err)
+
+	var got bytes.Buffer
+
+	require.NoError(t, writeConfigSchema(&got))
+	assert.Equal(t, string(want), got.String(), "regenerate with `bot config schema > runtime/config.schema.json`")
+}
@@ -0,0 +1,220 @@
+// Package backtest replays historical ticks through strategies, simulating multiplier contracts,
+// and searches strategy parameters that perform best on them.
+package backtest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/executor"
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+)
+
+type Objective string
+
+const (
+	ObjectiveNetPnL       Objective = "net_pnl"
+	ObjectiveSharpe       Objective = "sharpe"
+	ObjectiveProfitFactor Objective = "profit_factor"
+	ObjectiveMaxDrawdown  Objective = "max_drawdown"
+)
+
+// Objectives lists the supported objectives.
+var Objectives = []Objective{ObjectiveNetPnL, ObjectiveSharpe, ObjectiveProfitFactor, ObjectiveMaxDrawdown}
+
+// Trade is a simulated round trip of a strategy.
+type Trade struct {
+	OpenedAt   time.Time
+	ClosedAt   time.Time
+	EntryPrice float64
+	ExitPrice  float64
+	Stake      float64
+	Profit     float64
+	StoppedOut bool
+}
+
+// Result summarizes a backtest run. MaxDrawdown is the largest drop of realized profit from its peak.
+type Result struct {
+	Trades      []Trade
+	NetPnL      float64
+	GrossProfit float64
+	GrossLoss   float64
+	MaxDrawdown float64
+	Wins        int
+}
+
+// ErrUnsupported is returned for strategies using policies the replay doesn't simulate.
+var ErrUnsupported = errors.New("not supported by backtest")
+
+// Check reports policies of strategy Run doesn't simulate: exits, the schedule with its max holding time,
+// the cooldown and the daily trade limit. The executor applies them on every tick, so results of such
+// strategies would differ from live trading.
+// Returns an error wrapping ErrUnsupported naming the policies, or nil if the strategy can be backtested.
+func Check(strategy executor.Strategy) error {
+	var unsupported []string
+
+	if strategy.Exit != (executor.ExitPolicy{}) {
+		unsupported = append(unsupported, "exit")
+	}
+
+	sch := strategy.Schedule
+	if len(sch.Days) > 0 || len(sch.Hours) > 0 || len(sch.Blackouts) > 0 || sch.MaxHolding > 0 {
+		unsupported = append(unsupported, "schedule")
+	}
+
+	if strategy.Cooldown > 0 {
+		unsupported = append(unsupported, "cooldown")
+	}
+
+	if strategy.MaxTradesPerDay > 0 {
+		unsupported = append(unsupported, "max_trades_per_day")
+	}
+
+	if len(unsupported) > 0 {
+		return fmt.Errorf("%w: %s", ErrUnsupported, strings.Join(unsupported, ", "))
+	}
+
+	return nil
+}
+
+// Run replays ticks through the open and close rules of strategy: a position is opened on the tick CheckToOpen
+// accepts and closed on the tick CheckToClose accepts, filled at the tick quote. A position is stopped out when
+// its loss reaches the stake, and a position still open after the last tick is closed at the last quote.
+// Exits, schedules, cooldowns and daily trade limits aren't simulated, strategies using them are rejected
+// by Check. The strategy must be freshly built, rules keep state between ticks.
+// Returns the result of the simulated trades.
+func Run(strategy executor.Strategy, ticks []signal.Tick) Result {
+	var (
+		res   Result
+		open  *Trade
+		peak  float64
+		short = strategy.Type == executor.StrategyTypeSell
+	)
+
+	closeAt := func(tick signal.Tick, stoppedOut bool) {
+		open.ClosedAt = tick.Time
+		open.ExitPrice = tick.Quote
+		open.Profit = profit(open, tick.Quote, strategy.Leverage, short)
+		open.StoppedOut = stoppedOut
+
+		res.add(*open)
+		open = nil
+
+		peak = math.Max(peak, res.NetPnL)
+		res.MaxDrawdown = math.Max(res.MaxDrawdown, peak-res.NetPnL)
+	}
+
+	for _, tick := range ticks {
+		if open == nil {
+			if strategy.CheckToOpen(tick) {
+				open = &Trade{OpenedAt: tick.Time, EntryPrice: tick.Quote, Stake: strategy.Amount}
+			}
+
+			continue
+		}
+
+		switch {
+		case profit(open, tick.Quote, strategy.Leverage, short) <= -open.Stake:
+			closeAt(tick, true)
+		case strategy.CheckToClose(tick):
+			closeAt(tick, false)
+		}
+	}
+
+	if open != nil {
+		closeAt(ticks[len(ticks)-1], false)
+	}
+
+	return res
+}
+
+func (r *Result) add(t Trade) {
+	r.Trades = append(r.Trades, t)
+	r.NetPnL += t.Profit
+
+	if t.Profit > 0 {
+		r.Wins++
+		r.GrossProfit += t.Profit
+	} else {
+		r.GrossLoss -= t.Profit
+	}
+}
+
+// WinRate returns the share of profitable trades, or 0 without trades.
+func (r Result) WinRate() float64 {
+	if len(r.Trades) == 0 {
+		return 0
+	}
+
+	return float64(r.Wins) / float64(len(r.Trades))
+}
+
+// ProfitFactor returns gross profit divided by gross loss. It is +Inf for profitable runs without losses
+// and 0 without profitable trades.
+func (r Result) ProfitFactor() float64 {
+	switch {
+	case r.GrossProfit == 0:
+		return 0
+	case r.GrossLoss == 0:
+		return math.Inf(1)
+	default:
+		return r.GrossProfit / r.GrossLoss
+	}
+}
+
+// Sharpe returns the mean return per trade relative to the stake divided by the standard deviation of returns.
+// The ratio is not annualized, it is 0 with less than two trades or without variance.
+func (r Result) Sharpe() float64 {
+	n := float64(len(r.Trades))
+	if n < 2 {
+		return 0
+	}
+
+	var sum, sumSq float64
+
+	for _, t := range r.Trades {
+		ret := t.Profit / t.Stake
+		sum += ret
+		sumSq += ret * ret
+	}
+
+	mean := sum / n
+
+	variance := (sumSq - n*mean*mean) / (n - 1)
+	if variance <= 0 {
+		return 0
+	}
+
+	return mean / math.Sqrt(variance)
+}
+
+// Score returns the value of objective for the result, higher is better for all objectives,
+// so the max drawdown is negated.
+// Returns an error if the objective is unknown.
+func (r Result) Score(objective Objective) (float64, error) {
+	switch objective {
+	case ObjectiveNetPnL:
+		return r.NetPnL, nil
+	case ObjectiveSharpe:
+		return r.Sharpe(), nil
+	case ObjectiveProfitFactor:
+		return r.ProfitFactor(), nil
+	case ObjectiveMaxDrawdown:
+		return -r.MaxDrawdown, nil
+	default:
+		return 0, fmt.Errorf("unknown objective %q", objective)
+	}
+}
+
+// profit calculates the profit of a multiplier contract at quote, the loss is capped by the stake.
+func profit(t *Trade, quote, leverage float64, short bool) float64 {
+	change := (quote - t.EntryPrice) / t.EntryPrice
+	if short {
+		change = -change
+	}
+
+	return math.Max(t.Stake*leverage*change, -t.Stake)
+}
@@ -0,0 +1,257 @@
+package backtest
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/executor"
+	"github.com/ksysoev/deriv-bot/pkg/core/rules"
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ticksOf(quotes ...float64) []signal.Tick {
+	start := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	ticks := make([]signal.Tick, len(quotes))
+
+	for i, q := range quotes {
+		ticks[i] = signal.Tick{Time: start.Add(time.Duration(i) * time.Second), Quote: q}
+	}
+
+	return ticks
+}
+
+func takeProfitStrategy(t *testing.T, pct float64, strategyType executor.StrategyType) executor.Strategy {
+	t.Helper()
+
+	rule, err := rules.New(rules.TakeProfit, map[string]float64{"take_profit_pct": pct}, false)
+	require.NoError(t, err)
+
+	return executor.Strategy{
+		Type:         strategyType,
+		Amount:       10,
+		Leverage:     100,
+		CheckToOpen:  rule.CheckToOpen,
+		CheckToClose: rule.CheckToClose,
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name         string
+		strategyType executor.StrategyType
+		quotes       []float64
+		wantProfits  []float64
+		wantStopped  []bool
+		wantDrawdown float64
+	}{
+		{
+			name:         "take profit then close at end",
+			strategyType: executor.StrategyTypeBuy,
+			quotes:       []float64{100, 100.5, 101.5, 100, 99.5},
+			wantProfits:  []float64{15, -5},
+			wantStopped:  []bool{false, false},
+			wantDrawdown: 5,
+		},
+		{
+			name:         "stop out caps the loss",
+			strategyType: executor.StrategyTypeBuy,
+			quotes:       []float64{100, 95, 101},
+			wantProfits:  []float64{-10, 0},
+			wantStopped:  []bool{true, false},
+			wantDrawdown: 10,
+		},
+		{
+			name:         "short profits from a falling market",
+			strategyType: executor.StrategyTypeSell,
+			quotes:       []float64{100, 99.5},
+			wantProfits:  []float64{5},
+			wantStopped:  []bool{false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Run(takeProfitStrategy(t, 1, tt.strategyType), ticksOf(tt.quotes...))
+
+			require.Len(t, res.Trades, len(tt.wantProfits))
+
+			for i, trade := range res.Trades {
+				assert.InDelta(t, tt.wantProfits[i], trade.Profit, 1e-9)
+				assert.Equal(t, tt.wantStopped[i], trade.StoppedOut)
+			}
+
+			assert.InDelta(t, tt.wantDrawdown, res.MaxDrawdown, 1e-9)
+		})
+	}
+}
+
+func TestResult_Score(t *testing.T) {
+	res := Result{}
+	res.add(Trade{Stake: 10, Profit: 20})
+	res.add(Trade{Stake: 10, Profit: -10})
+	res.add(Trade{Stake: 10, Profit: 5})
+
+	assert.InDelta(t, 2.0/3, res.WinRate(), 1e-9)
+	assert.InDelta(t, 2.5, res.ProfitFactor(), 1e-9)
+
+	sharpe, err := res.Score(ObjectiveSharpe)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.5/math.Sqrt(2.25), sharpe, 1e-9)
+
+	_, err = res.Score("alpha")
+	assert.ErrorContains(t, err, `unknown objective "alpha"`)
+}
+
+func TestOptimize(t *testing.T) {
+	// The market rises 1.2% and falls back repeatedly, so only targets below the swing close in profit.
+	var quotes []float64
+	for range 20 {
+		quotes = append(quotes, 100, 100.6, 101.2, 100.6)
+	}
+
+	build := func(params map[string]float64) (executor.Strategy, error) {
+		rule, err := rules.New(rules.TakeProfit, params, false)
+		if err != nil {
+			return executor.Strategy{}, err
+		}
+
+		return executor.Strategy{
+			Type:         executor.StrategyTypeBuy,
+			Amount:       10,
+			Leverage:     10,
+			CheckToOpen:  rule.CheckToOpen,
+			CheckToClose: rule.CheckToClose,
+		}, nil
+	}
+
+	tests := []struct {
+		name           string
+		cfg            OptimizeConfig
+		wantCandidates int
+		wantSplits     int
+	}{
+		{
+			name: "grid",
+			cfg: OptimizeConfig{
+				Ranges:    []ParamRange{{Name: "take_profit_pct", Min: 0, Max: 2, Step: 0.5}},
+				Objective: ObjectiveNetPnL,
+			},
+			wantCandidates: 4,
+		},
+		{
+			name: "random with walk-forward",
+			cfg: OptimizeConfig{
+				Ranges:      []ParamRange{{Name: "take_profit_pct", Min: 0.5, Max: 2, Step: 0.5}},
+				Search:      SearchRandom,
+				Samples:     10,
+				Objective:   ObjectiveNetPnL,
+				WalkForward: 3,
+				Seed:        1,
+			},
+			wantCandidates: 4,
+			wantSplits:     3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := Optimize(context.Background(), tt.cfg, build, ticksOf(quotes...))
+			require.NoError(t, err)
+
+			require.Len(t, report.Candidates, tt.wantCandidates)
+			assert.Len(t, report.Splits, tt.wantSplits)
+			assert.Equal(t, map[string]float64{"take_profit_pct": 1}, report.Candidates[0].Params)
+
+			for i := 1; i < len(report.Candidates); i++ {
+				assert.GreaterOrEqual(t, report.Candidates[i-1].Score, report.Candidates[i].Score)
+			}
+
+			for _, s := range report.Splits {
+				assert.True(t, s.TestFrom.After(s.TrainFrom))
+			}
+		})
+	}
+}
+
+func TestParseParamRange(t *testing.T) {
+	r, err := ParseParamRange("slow=30:100:5")
+	require.NoError(t, err)
+	assert.Equal(t, ParamRange{Name: "slow", Min: 30, Max: 100, Step: 5}, r)
+	assert.Len(t, r.values(), 15)
+
+	r, err = ParseParamRange("fast=9")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{9}, r.values())
+
+	_, err = ParseParamRange("fast=20:5")
+	assert.Error(t, err)
+
+	_, err = ParseParamRange("fast")
+	assert.Error(t, err)
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		modify  func(s *executor.Strategy)
+		name    string
+		wantErr string
+	}{
+		{name: "rules only", modify: func(*executor.Strategy) {}},
+		{
+			name: "exit policy",
+			modify: func(s *executor.Strategy) {
+				s.Exit.TrailingStop = executor.TrailingStop{Mode: executor.TrailingPercent, Distance: 1}
+			},
+			wantErr: "exit",
+		},
+		{
+			name:    "max holding",
+			modify:  func(s *executor.Strategy) { s.Schedule.MaxHolding = time.Hour },
+			wantErr: "schedule",
+		},
+		{
+			name: "cooldown and trade limit",
+			modify: func(s *executor.Strategy) {
+				s.Cooldown = time.Minute
+				s.MaxTradesPerDay = 5
+			},
+			wantErr: "cooldown, max_trades_per_day",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy := takeProfitStrategy(t, 1, executor.StrategyTypeBuy)
+			tt.modify(&strategy)
+
+			err := Check(strategy)
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrUnsupported)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestOptimize_Unsupported(t *testing.T) {
+	_, err := Optimize(context.Background(), OptimizeConfig{
+		Ranges:    []ParamRange{{Name: "take_profit_pct", Min: 1, Max: 2, Step: 1}},
+		Search:    SearchGrid,
+		Objective: ObjectiveNetPnL,
+	}, func(params map[string]float64) (executor.Strategy, error) {
+		strategy := takeProfitStrategy(t, params["take_profit_pct"], executor.StrategyTypeBuy)
+		strategy.Cooldown = time.Minute
+
+		return strategy, nil
+	}, ticksOf(100, 101, 102))
+
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
@@ -0,0 +1,345 @@
+package backtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/executor"
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+	"golang.org/x/sync/errgroup"
+)
+
+type Search string
+
+const (
+	SearchGrid   Search = "grid"
+	SearchRandom Search = "random"
+)
+
+var ErrNoCandidates = errors.New("no valid parameter combinations")
+
+// maxGridSize limits the number of parameter combinations evaluated by a grid search.
+const maxGridSize = 100_000
+
+// ParamRange defines the values of a parameter from Min to Max inclusive, in increments of Step.
+type ParamRange struct {
+	Name string
+	Min  float64
+	Max  float64
+	Step float64
+}
+
+// ParseParamRange parses a range in the name=min:max:step form, the step defaults to 1.
+// A single value, e.g. name=10, fixes the parameter.
+// Returns an error if the range is malformed.
+func ParseParamRange(s string) (ParamRange, error) {
+	name, spec, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return ParamRange{}, fmt.Errorf("invalid parameter range %q, expected name=min:max[:step]", s)
+	}
+
+	parts := strings.Split(spec, ":")
+	if len(parts) > 3 {
+		return ParamRange{}, fmt.Errorf("invalid parameter range %q, expected name=min:max[:step]", s)
+	}
+
+	vals := []float64{0, 0, 1}
+
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return ParamRange{}, fmt.Errorf("invalid parameter range %q: %w", s, err)
+		}
+
+		vals[i] = v
+	}
+
+	if len(parts) == 1 {
+		vals[1] = vals[0]
+	}
+
+	r := ParamRange{Name: name, Min: vals[0], Max: vals[1], Step: vals[2]}
+
+	if r.Max < r.Min || r.Step <= 0 {
+		return ParamRange{}, fmt.Errorf("invalid parameter range %q, max must not be less than min and step must be positive", s)
+	}
+
+	return r, nil
+}
+
+// values returns the values of the range. The count is computed upfront, so float steps don't accumulate errors.
+func (p ParamRange) values() []float64 {
+	n := int(math.Floor((p.Max-p.Min)/p.Step+1e-9)) + 1
+	vals := make([]float64, n)
+
+	for i := range vals {
+		vals[i] = p.Min + float64(i)*p.Step
+	}
+
+	return vals
+}
+
+// OptimizeConfig configures a parameter search. Samples is the number of combinations tried by random search,
+// Workers defaults to the number of CPUs and Seed makes random search reproducible.
+// WalkForward splits the ticks into WalkForward+1 consecutive windows, each window is used to pick the best
+// parameters which are then tested on the next window.
+type OptimizeConfig struct {
+	Ranges      []ParamRange
+	Search      Search
+	Objective   Objective
+	Samples     int
+	Workers     int
+	WalkForward int
+	Seed        uint64
+}
+
+// BuildFunc builds a fresh strategy with params.
+type BuildFunc func(params map[string]float64) (executor.Strategy, error)
+
+// Candidate is a parameter combination with its backtest result.
+type Candidate struct {
+	Params map[string]float64
+	Result Result
+	Score  float64
+}
+
+// Split is a walk-forward step: parameters picked on the train window and their out-of-sample result.
+type Split struct {
+	TrainFrom time.Time
+	TestFrom  time.Time
+	TestTo    time.Time
+	Best      Candidate
+	Test      Result
+	TestScore float64
+}
+
+// Report holds candidates ranked by score over the whole dataset and the walk-forward splits, if any.
+// Skipped counts parameter combinations rejected by the strategy builder.
+type Report struct {
+	Candidates []Candidate
+	Splits     []Split
+	Skipped    int
+}
+
+// Optimize backtests parameter combinations of cfg.Ranges over ticks in parallel and ranks them by the objective.
+// Returns the report and an error if the configuration is invalid, no combination can be built,
+// the strategy uses policies Check rejects or ctx is cancelled.
+func Optimize(ctx context.Context, cfg OptimizeConfig, build BuildFunc, ticks []signal.Tick) (*Report, error) {
+	if _, err := (Result{}).Score(cfg.Objective); err != nil {
+		return nil, err
+	}
+
+	if len(ticks) == 0 {
+		return nil, fmt.Errorf("no ticks to backtest")
+	}
+
+	if cfg.Workers <= 0 {
+		cfg.Workers = runtime.NumCPU()
+	}
+
+	combos, err := combinations(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	valid := combos[:0]
+
+	for _, params := range combos {
+		strategy, err := build(params)
+		if err != nil {
+			continue
+		}
+
+		if err := Check(strategy); err != nil {
+			return nil, err
+		}
+
+		valid = append(valid, params)
+	}
+
+	report := &Report{Skipped: len(combos) - len(valid)}
+
+	if len(valid) == 0 {
+		return nil, ErrNoCandidates
+	}
+
+	if report.Candidates, err = evaluate(ctx, cfg, build, valid, ticks); err != nil {
+		return nil, err
+	}
+
+	if cfg.WalkForward <= 0 {
+		return report, nil
+	}
+
+	windows := split(ticks, cfg.WalkForward+1)
+	if len(windows) < 2 {
+		return nil, fmt.Errorf("not enough ticks for %d walk-forward splits", cfg.WalkForward)
+	}
+
+	for i := range len(windows) - 1 {
+		ranked, err := evaluate(ctx, cfg, build, valid, windows[i])
+		if err != nil {
+			return nil, err
+		}
+
+		best := ranked[0]
+
+		strategy, err := build(best.Params)
+		if err != nil {
+			return nil, err
+		}
+
+		test := Run(strategy, windows[i+1])
+		score, _ := test.Score(cfg.Objective)
+
+		report.Splits = append(report.Splits, Split{
+			TrainFrom: windows[i][0].Time,
+			TestFrom:  windows[i+1][0].Time,
+			TestTo:    windows[i+1][len(windows[i+1])-1].Time,
+			Best:      best,
+			Test:      test,
+			TestScore: score,
+		})
+	}
+
+	return report, nil
+}
+
+// evaluate backtests every combination over ticks using cfg.Workers goroutines.
+// Returns candidates sorted by score, best first, and an error if ctx is cancelled.
+func evaluate(ctx context.Context, cfg OptimizeConfig, build BuildFunc, combos []map[string]float64, ticks []signal.Tick) ([]Candidate, error) {
+	candidates := make([]Candidate, len(combos))
+
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(cfg.Workers)
+
+	for i, params := range combos {
+		eg.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			strategy, err := build(params)
+			if err != nil {
+				return fmt.Errorf("failed to build strategy with %v: %w", params, err)
+			}
+
+			res := Run(strategy, ticks)
+			score, _ := res.Score(cfg.Objective)
+			candidates[i] = Candidate{Params: params, Result: res, Score: score}
+
+			return nil
+		})
+	}
+
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+
+	return candidates, nil
+}
+
+// combinations enumerates the parameter combinations to evaluate.
+// Returns an error if the search is unknown or the grid is too large.
+func combinations(cfg OptimizeConfig) ([]map[string]float64, error) {
+	values := make([][]float64, len(cfg.Ranges))
+	size := 1
+
+	for i, r := range cfg.Ranges {
+		values[i] = r.values()
+		size *= len(values[i])
+
+		if size > maxGridSize {
+			if cfg.Search != SearchRandom {
+				return nil, fmt.Errorf("grid has more than %d combinations, use random search or narrow the ranges", maxGridSize)
+			}
+
+			size = maxGridSize + 1
+		}
+	}
+
+	switch cfg.Search {
+	case SearchGrid, "":
+		combos := []map[string]float64{{}}
+
+		for i, r := range cfg.Ranges {
+			next := make([]map[string]float64, 0, len(combos)*len(values[i]))
+
+			for _, c := range combos {
+				for _, v := range values[i] {
+					params := maps.Clone(c)
+					params[r.Name] = v
+					next = append(next, params)
+				}
+			}
+
+			combos = next
+		}
+
+		return combos, nil
+	case SearchRandom:
+		if cfg.Samples <= 0 {
+			return nil, fmt.Errorf("samples must be positive for random search")
+		}
+
+		rng := rand.New(rand.NewPCG(cfg.Seed, cfg.Seed)) //nolint:gosec // sampling parameters, not security sensitive
+		if cfg.Seed == 0 {
+			rng = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())) //nolint:gosec // sampling parameters, not security sensitive
+		}
+
+		seen := make(map[string]struct{}, cfg.Samples)
+		combos := make([]map[string]float64, 0, cfg.Samples)
+
+		for attempts := 0; len(combos) < cfg.Samples && len(combos) < size && attempts < cfg.Samples*10; attempts++ {
+			params := make(map[string]float64, len(cfg.Ranges))
+
+			for i, r := range cfg.Ranges {
+				params[r.Name] = values[i][rng.IntN(len(values[i]))]
+			}
+
+			key := fmt.Sprint(params)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+
+			seen[key] = struct{}{}
+			combos = append(combos, params)
+		}
+
+		return combos, nil
+	default:
+		return nil, fmt.Errorf("unknown search %q", cfg.Search)
+	}
+}
+
+// split divides ticks into n consecutive windows of equal size, dropping empty ones.
+func split(ticks []signal.Tick, n int) [][]signal.Tick {
+	size := len(ticks) / n
+	if size == 0 {
+		return nil
+	}
+
+	windows := make([][]signal.Tick, n)
+
+	for i := range windows {
+		end := (i + 1) * size
+		if i == n-1 {
+			end = len(ticks)
+		}
+
+		windows[i] = ticks[i*size : end]
+	}
+
+	return windows
+}
@@ -0,0 +1,316 @@
+package chatops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/executor"
+)
+
+const (
+	retryDelay   = 5 * time.Second
+	closeTimeout = 30 * time.Second
+)
+
+type Executor interface {
+	Strategies() []executor.StrategyStatus
+	PauseStrategy(name string) error
+	ResumeStrategy(name string) error
+	Positions() []executor.OpenPosition
+	ClosePosition(ctx context.Context, contractID int) error
+	Trades(since time.Time) []executor.ClosedTrade
+}
+
+// Command is a text command received from a chat.
+type Command struct {
+	Text   string
+	ChatID int64
+}
+
+type Bot interface {
+	// Commands long-polls for new commands, returning an empty slice when nothing arrived.
+	Commands(ctx context.Context) ([]Command, error)
+	Reply(ctx context.Context, chatID int64, text string) error
+}
+
+type Service struct {
+	exec    Executor
+	bot     Bot
+	allowed map[int64]struct{}
+	now     func() time.Time
+}
+
+// New creates a chat command service controlling exec through bot.
+// Only commands from chats listed in allowedChats are executed, others are ignored and logged.
+// Returns a pointer to the initialized Service.
+func New(exec Executor, bot Bot, allowedChats []int64) *Service {
+	allowed := make(map[int64]struct{}, len(allowedChats))
+	for _, id := range allowedChats {
+		allowed[id] = struct{}{}
+	}
+
+	return &Service{
+		exec:    exec,
+		bot:     bot,
+		allowed: allowed,
+		now:     time.Now,
+	}
+}
+
+// Run polls the bot for commands and replies to them until ctx is cancelled.
+// Polling errors are logged and retried after a delay. Returns nil when ctx is cancelled.
+func (s *Service) Run(ctx context.Context) error {
+	for ctx.Err() == nil {
+		cmds, err := s.bot.Commands(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+
+			slog.Error("Failed to receive chat commands", slog.Any("error", err))
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(retryDelay):
+			}
+
+			continue
+		}
+
+		for _, cmd := range cmds {
+			if _, ok := s.allowed[cmd.ChatID]; !ok {
+				slog.Warn("Chat command from unauthorized chat ignored", slog.Int64("chat_id", cmd.ChatID))
+				continue
+			}
+
+			reply := s.Handle(ctx, cmd.Text)
+
+			if err := s.bot.Reply(ctx, cmd.ChatID, reply); err != nil {
+				slog.Error("Failed to reply to chat command", slog.Int64("chat_id", cmd.ChatID), slog.Any("error", err))
+			}
+		}
+	}
+
+	return nil
+}
+
+// Handle executes a single command and returns the reply text.
+// Supported commands are /status, /positions, /pause <strategy>, /resume [strategy], /closeall and /pnl [today|all].
+func (s *Service) Handle(ctx context.Context, text string) string {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return helpText
+	}
+
+	// Commands in group chats may be addressed to the bot, e.g. /status@my_bot.
+	name, _, _ := strings.Cut(fields[0], "@")
+	args := fields[1:]
+
+	switch name {
+	case "/status":
+		return s.status()
+	case "/positions":
+		return s.positions()
+	case "/pause":
+		return s.pause(args)
+	case "/resume":
+		return s.resume(args)
+	case "/closeall":
+		return s.closeAll(ctx)
+	case "/pnl":
+		return s.pnl(args)
+	default:
+		return helpText
+	}
+}
+
+const helpText = `Available commands:
+/status - strategies and their state
+/positions - open positions
+/pause <strategy> - stop opening new positions
+/resume [strategy] - resume a paused strategy, or all of them
+/closeall - close all open positions
+/pnl [today|all] - realized profit and loss`
+
+func (s *Service) status() string {
+	statuses := s.exec.Strategies()
+	if len(statuses) == 0 {
+		return "No strategies configured"
+	}
+
+	var sb strings.Builder
+
+	for _, st := range statuses {
+		fmt.Fprintf(&sb, "%s (%s %s): %s", st.Name, st.Type, st.Symbol, st.State)
+
+		if st.Position != nil {
+			fmt.Fprintf(&sb, ", in position %d", st.Position.ContractID)
+		}
+
+		if st.Error != "" {
+			fmt.Fprintf(&sb, ", error: %s", st.Error)
+		}
+
+		sb.WriteString("\n")
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
+func (s *Service) positions() string {
+	positions := s.exec.Positions()
+	if len(positions) == 0 {
+		return "No open positions"
+	}
+
+	var sb strings.Builder
+
+	for _, p := range positions {
+		fmt.Fprintf(&sb, "%d %s: %s %s, stake %.2f x%.0f at %.5f since %s\n",
+			p.ContractID, p.Strategy, p.Type, p.Symbol, p.Amount, p.Leverage, p.Price, p.OpenedAt.Format(time.RFC3339))
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
+func (s *Service) pause(args []string) string {
+	if len(args) != 1 {
+		return "Usage: /pause <strategy>"
+	}
+
+	if err := s.exec.PauseStrategy(args[0]); err != nil {
+		return "Failed to pause: " + err.Error()
+	}
+
+	return fmt.Sprintf("Strategy %s paused", args[0])
+}
+
+func (s *Service) resume(args []string) string {
+	switch len(args) {
+	case 0:
+		var resumed []string
+
+		for _, st := range s.exec.Strategies() {
+			if st.State != executor.StrategyStatePaused {
+				continue
+			}
+
+			if err := s.exec.ResumeStrategy(st.Name); err == nil {
+				resumed = append(resumed, st.Name)
+			}
+		}
+
+		if len(resumed) == 0 {
+			return "No paused strategies"
+		}
+
+		return "Resumed: " + strings.Join(resumed, ", ")
+	case 1:
+		if err := s.exec.ResumeStrategy(args[0]); err != nil {
+			return "Failed to resume: " + err.Error()
+		}
+
+		return fmt.Sprintf("Strategy %s resumed", args[0])
+	default:
+		return "Usage: /resume [strategy]"
+	}
+}
+
+func (s *Service) closeAll(ctx context.Context) string {
+	positions := s.exec.Positions()
+	if len(positions) == 0 {
+		return "No open positions"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, closeTimeout)
+	defer cancel()
+
+	var sb strings.Builder
+
+	for _, p := range positions {
+		err := s.exec.ClosePosition(ctx, p.ContractID)
+
+		switch {
+		case err == nil:
+			fmt.Fprintf(&sb, "%d %s: closed\n", p.ContractID, p.Strategy)
+		case errors.Is(err, executor.ErrNoOpenPosition):
+			fmt.Fprintf(&sb, "%d %s: already closed\n", p.ContractID, p.Strategy)
+		default:
+			fmt.Fprintf(&sb, "%d %s: failed: %s\n", p.ContractID, p.Strategy, err)
+		}
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
+func (s *Service) pnl(args []string) string {
+	period := "today"
+	if len(args) > 0 {
+		period = args[0]
+	}
+
+	var since time.Time
+
+	switch period {
+	case "today":
+		now := s.now()
+		since = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	case "all":
+	default:
+		return "Usage: /pnl [today|all]"
+	}
+
+	trades := s.exec.Trades(since)
+	if len(trades) == 0 {
+		return fmt.Sprintf("No closed trades (%s)", period)
+	}
+
+	type summary struct {
+		profit float64
+		count  int
+		wins   int
+	}
+
+	byStrategy := make(map[string]*summary)
+	total := summary{}
+
+	for _, t := range trades {
+		sum, ok := byStrategy[t.Strategy]
+		if !ok {
+			sum = &summary{}
+			byStrategy[t.Strategy] = sum
+		}
+
+		for _, s := range []*summary{sum, &total} {
+			s.profit += t.Profit
+			s.count++
+
+			if t.Profit > 0 {
+				s.wins++
+			}
+		}
+	}
+
+	names := make([]string, 0, len(byStrategy))
+	for name := range byStrategy {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "PnL (%s): %.2f, %d trades, %d wins\n", period, total.profit, total.count, total.wins)
+
+	for _, name := range names {
+		sum := byStrategy[name]
+		fmt.Fprintf(&sb, "%s: %.2f, %d trades, %d wins\n", name, sum.profit, sum.count, sum.wins)
+	}
+
+	return strings.TrimSpace(sb.String())
+}
@@ -0,0 +1,255 @@
+package chatops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/executor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBot delivers queued commands on the first poll and records replies.
+type fakeBot struct {
+	commands []Command
+	replies  map[int64][]string
+	mu       sync.Mutex
+}
+
+func (b *fakeBot) Commands(ctx context.Context) ([]Command, error) {
+	b.mu.Lock()
+	cmds := b.commands
+	b.commands = nil
+	b.mu.Unlock()
+
+	if len(cmds) > 0 {
+		return cmds, nil
+	}
+
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
+
+func (b *fakeBot) Reply(_ context.Context, chatID int64, text string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.replies == nil {
+		b.replies = make(map[int64][]string)
+	}
+
+	b.replies[chatID] = append(b.replies[chatID], text)
+
+	return nil
+}
+
+func (b *fakeBot) repliesTo(chatID int64) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.replies[chatID]
+}
+
+type stubExecutor struct {
+	closeErrs  map[int]error
+	resumeErrs map[string]error
+	statuses   []executor.StrategyStatus
+	positions  []executor.OpenPosition
+	trades     []executor.ClosedTrade
+	resumed    []string
+	since      time.Time
+}
+
+func (e *stubExecutor) Strategies() []executor.StrategyStatus { return e.statuses }
+
+func (e *stubExecutor) PauseStrategy(name string) error {
+	return fmt.Errorf("%w: %s", executor.ErrStrategyNotFound, name)
+}
+
+func (e *stubExecutor) ResumeStrategy(name string) error {
+	if err := e.resumeErrs[name]; err != nil {
+		return err
+	}
+
+	e.resumed = append(e.resumed, name)
+
+	return nil
+}
+
+func (e *stubExecutor) Positions() []executor.OpenPosition { return e.positions }
+
+func (e *stubExecutor) ClosePosition(_ context.Context, contractID int) error {
+	return e.closeErrs[contractID]
+}
+
+func (e *stubExecutor) Trades(since time.Time) []executor.ClosedTrade {
+	e.since = since
+
+	return e.trades
+}
+
+func TestService_Handle(t *testing.T) {
+	now := time.Date(2026, 10, 19, 15, 30, 0, 0, time.UTC)
+	openedAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		exec      *stubExecutor
+		name      string
+		command   string
+		want      string
+		wantSince time.Time
+	}{
+		{
+			name:    "positions",
+			command: "/positions",
+			exec: &stubExecutor{positions: []executor.OpenPosition{{
+				OpenedAt: openedAt, Strategy: "r100", Symbol: "R_100", Type: executor.StrategyTypeBuy,
+				Amount: 10, Leverage: 100, Price: 1234.5, ContractID: 42,
+			}}},
+			want: "42 r100: buy R_100, stake 10.00 x100 at 1234.50000 since 2026-10-19T12:00:00Z",
+		},
+		{
+			name:    "no positions",
+			command: "/positions",
+			exec:    &stubExecutor{},
+			want:    "No open positions",
+		},
+		{
+			name:    "resume strategy",
+			command: "/resume r100",
+			exec:    &stubExecutor{},
+			want:    "Strategy r100 resumed",
+		},
+		{
+			name:    "resume unknown strategy",
+			command: "/resume r10",
+			exec:    &stubExecutor{resumeErrs: map[string]error{"r10": executor.ErrStrategyNotFound}},
+			want:    "Failed to resume: strategy not found",
+		},
+		{
+			name:    "resume all paused strategies",
+			command: "/resume",
+			exec: &stubExecutor{
+				statuses: []executor.StrategyStatus{
+					{Name: "r100", State: executor.StrategyStatePaused},
+					{Name: "r50", State: executor.StrategyStateWaitingToOpen},
+					{Name: "r25", State: executor.StrategyStatePaused},
+				},
+				resumeErrs: map[string]error{"r25": executor.ErrInvalidState},
+			},
+			want: "Resumed: r100",
+		},
+		{
+			name:    "resume without paused strategies",
+			command: "/resume",
+			exec:    &stubExecutor{statuses: []executor.StrategyStatus{{Name: "r100", State: executor.StrategyStateInPosition}}},
+			want:    "No paused strategies",
+		},
+		{
+			name:    "resume with too many arguments",
+			command: "/resume r100 r50",
+			exec:    &stubExecutor{},
+			want:    "Usage: /resume [strategy]",
+		},
+		{
+			name:    "close all positions",
+			command: "/closeall",
+			exec: &stubExecutor{
+				positions: []executor.OpenPosition{
+					{Strategy: "r100", ContractID: 1},
+					{Strategy: "r50", ContractID: 2},
+					{Strategy: "r25", ContractID: 3},
+				},
+				closeErrs: map[int]error{
+					2: fmt.Errorf("%w: strategy r50", executor.ErrNoOpenPosition),
+					3: errors.New("connection closed"),
+				},
+			},
+			want: "1 r100: closed\n2 r50: already closed\n3 r25: failed: connection closed",
+		},
+		{
+			name:    "close without positions",
+			command: "/closeall",
+			exec:    &stubExecutor{},
+			want:    "No open positions",
+		},
+		{
+			name:    "pnl today",
+			command: "/pnl",
+			exec: &stubExecutor{trades: []executor.ClosedTrade{
+				{Strategy: "r50", Profit: -4},
+				{Strategy: "r100", Profit: 15},
+				{Strategy: "r100", Profit: 5},
+			}},
+			want:      "PnL (today): 16.00, 3 trades, 2 wins\nr100: 20.00, 2 trades, 2 wins\nr50: -4.00, 1 trades, 0 wins",
+			wantSince: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "pnl all without trades",
+			command: "/pnl all",
+			exec:    &stubExecutor{},
+			want:    "No closed trades (all)",
+		},
+		{
+			name:    "pnl of unknown period",
+			command: "/pnl week",
+			exec:    &stubExecutor{},
+			want:    "Usage: /pnl [today|all]",
+		},
+		{
+			name:    "pause unknown strategy",
+			command: "/pause r10",
+			exec:    &stubExecutor{},
+			want:    "Failed to pause: strategy not found: r10",
+		},
+		{
+			name:    "command addressed to the bot",
+			command: "/positions@deriv_bot",
+			exec:    &stubExecutor{},
+			want:    "No open positions",
+		},
+		{
+			name:    "unknown command",
+			command: "/close 42",
+			exec:    &stubExecutor{},
+			want:    helpText,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := New(tt.exec, &fakeBot{}, nil)
+			svc.now = func() time.Time { return now }
+
+			assert.Equal(t, tt.want, svc.Handle(context.Background(), tt.command))
+			assert.Equal(t, tt.wantSince, tt.exec.since)
+		})
+	}
+}
+
+func TestService_Run(t *testing.T) {
+	exec := &stubExecutor{}
+	bot := &fakeBot{commands: []Command{
+		{ChatID: 666, Text: "/closeall"},
+		{ChatID: 1, Text: "/resume r100"},
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- New(exec, bot, []int64{1}).Run(ctx) }()
+
+	require.Eventually(t, func() bool { return len(bot.repliesTo(1)) == 1 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+
+	assert.Equal(t, []string{"Strategy r100 resumed"}, bot.repliesTo(1))
+	assert.Empty(t, bot.repliesTo(666), "commands of unauthorized chats are ignored")
+	assert.Equal(t, []string{"r100"}, exec.resumed)
+}
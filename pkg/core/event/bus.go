@@ -0,0 +1,76 @@
+package event
+
+import (
+	"sync"
+)
+
+const defaultBufferSize = 256
+
+type subscriber struct {
+	ch     chan Event
+	filter map[Type]struct{}
+}
+
+// Bus is an in-process publish/subscribe hub for events.
+// Publishing never blocks, events are dropped for subscribers that don't keep up.
+type Bus struct {
+	subs map[*subscriber]struct{}
+	mu   sync.RWMutex
+}
+
+// NewBus creates a new Bus without subscribers.
+func NewBus() *Bus {
+	return &Bus{
+		subs: make(map[*subscriber]struct{}),
+	}
+}
+
+// Publish delivers the event to every subscriber interested in its type.
+// Subscribers with a full buffer miss the event, which is accounted in the dropped events metric.
+func (b *Bus) Publish(e Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subs {
+		if len(sub.filter) > 0 {
+			if _, ok := sub.filter[e.Type]; !ok {
+				continue
+			}
+		}
+
+		select {
+		case sub.ch <- e:
+		default:
+			droppedEvents.WithLabelValues(string(e.Type)).Inc()
+		}
+	}
+}
+
+// Subscribe registers a new subscriber for the given event types, or for all events if none are given.
+// Returns a channel delivering events and a function to unsubscribe, which closes the channel.
+func (b *Bus) Subscribe(types ...Type) (<-chan Event, func()) {
+	sub := &subscriber{
+		ch:     make(chan Event, defaultBufferSize),
+		filter: make(map[Type]struct{}, len(types)),
+	}
+
+	for _, t := range types {
+		sub.filter[t] = struct{}{}
+	}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+
+	return sub.ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, sub)
+			b.mu.Unlock()
+
+			close(sub.ch)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBus_PublishSubscribe(t *testing.T) {
+	bus := NewBus()
+
+	all, unsubscribeAll := bus.Subscribe()
+	defer unsubscribeAll()
+
+	orders, unsubscribeOrders := bus.Subscribe(TypeOrderPlaced)
+
+	bus.Publish(New(TypeTickReceived, TickReceived{Symbol: "R_100"}))
+	bus.Publish(New(TypeOrderPlaced, OrderPlaced{Symbol: "R_100", ContractID: 1}))
+
+	assert.Equal(t, TypeTickReceived, (<-all).Type)
+	assert.Equal(t, TypeOrderPlaced, (<-all).Type)
+	assert.Equal(t, OrderPlaced{Symbol: "R_100", ContractID: 1}, (<-orders).Payload)
+
+	unsubscribeOrders()
+	unsubscribeOrders()
+
+	_, ok := <-orders
+	assert.False(t, ok)
+
+	bus.Publish(New(TypeOrderPlaced, OrderPlaced{}))
+	assert.Equal(t, TypeOrderPlaced, (<-all).Type)
+}
+
+func TestBus_PublishDoesNotBlock(t *testing.T) {
+	bus := NewBus()
+
+	events, unsubscribe := bus.Subscribe()
+	defer unsubscribe()
+
+	for range defaultBufferSize + 10 {
+		bus.Publish(New(TypeTickReceived, TickReceived{}))
+	}
+
+	assert.Len(t, events, defaultBufferSize)
+}
@@ -0,0 +1,122 @@
+package event
+
+import "time"
+
+type Type string
+
+const (
+	TypeTickReceived   Type = "tick_received"
+	TypeSignalFired    Type = "signal_fired"
+	TypeOrderPlaced    Type = "order_placed"
+	TypeOrderFailed    Type = "order_failed"
+	TypePositionClosed Type = "position_closed"
+	TypeRiskBreach     Type = "risk_breach"
+	TypeReconnect      Type = "reconnect"
+	TypeDataQuality    Type = "data_quality"
+	TypeStrategyState  Type = "strategy_state"
+)
+
+// Types lists all event types published by the bot.
+var Types = []Type{
+	TypeTickReceived,
+	TypeSignalFired,
+	TypeOrderPlaced,
+	TypeOrderFailed,
+	TypePositionClosed,
+	TypeRiskBreach,
+	TypeReconnect,
+	TypeDataQuality,
+	TypeStrategyState,
+}
+
+// Event is a typed notification about something that happened inside the bot.
+// Payload holds one of the payload structs defined in this package, matching Type.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Payload any       `json:"payload"`
+	Type    Type      `json:"type"`
+}
+
+type TickReceived struct {
+	Time   time.Time `json:"time"`
+	Symbol string    `json:"symbol"`
+	Quote  float64   `json:"quote"`
+	Ask    float64   `json:"ask"`
+	Bid    float64   `json:"bid"`
+}
+
+type SignalFired struct {
+	Strategy string  `json:"strategy"`
+	Symbol   string  `json:"symbol"`
+	Signal   string  `json:"signal"`
+	Quote    float64 `json:"quote"`
+}
+
+type OrderPlaced struct {
+	Strategy   string  `json:"strategy"`
+	Symbol     string  `json:"symbol"`
+	Side       string  `json:"side"`
+	Amount     float64 `json:"amount"`
+	Price      float64 `json:"price"`
+	Leverage   float64 `json:"leverage"`
+	ContractID int     `json:"contract_id"`
+}
+
+// OrderFailed reports a failed order. Kind is the class of the failure and Action how the strategy reacted:
+// retry, skip, pause or stop.
+type OrderFailed struct {
+	Strategy  string `json:"strategy"`
+	Symbol    string `json:"symbol"`
+	Operation string `json:"operation"`
+	Error     string `json:"error"`
+	Kind      string `json:"kind,omitempty"`
+	Action    string `json:"action,omitempty"`
+}
+
+type PositionClosed struct {
+	Strategy   string  `json:"strategy"`
+	Symbol     string  `json:"symbol"`
+	Profit     float64 `json:"profit"`
+	ContractID int     `json:"contract_id"`
+}
+
+type RiskBreach struct {
+	Strategy string `json:"strategy"`
+	Limit    string `json:"limit"`
+	Details  string `json:"details"`
+}
+
+// Reconnect reports a strategy subscribing again after its market connection dropped, Reason is the failure
+// and Attempt counts the attempts since the connection was last lost.
+type Reconnect struct {
+	Strategy string `json:"strategy"`
+	Symbol   string `json:"symbol"`
+	Reason   string `json:"reason"`
+	Attempt  int    `json:"attempt"`
+}
+
+// DataQuality reports an issue with the tick stream of a symbol: stale, recovered, out_of_order or outlier.
+type DataQuality struct {
+	Time    time.Time `json:"time"`
+	Symbol  string    `json:"symbol"`
+	Issue   string    `json:"issue"`
+	Details string    `json:"details"`
+}
+
+// StrategyState reports a transition of a strategy between lifecycle states, Reason explains transitions
+// not driven by trading, e.g. the error of a failed strategy.
+type StrategyState struct {
+	Strategy string `json:"strategy"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// New creates an event of type t with the given payload, stamped with the current time.
+func New(t Type, payload any) Event {
+	return Event{
+		Time:    time.Now(),
+		Type:    t,
+		Payload: payload,
+	}
+}
@@ -0,0 +1,12 @@
+package event
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var droppedEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "deriv_bot",
+	Name:      "events_dropped_total",
+	Help:      "Number of events not delivered to slow subscribers per event type.",
+}, []string{"type"})
@@ -4,3 +4,9 @@ type Account struct {
 	ID       string
 	Currency string
 }
+
+type Balance struct {
+	LoginID  string
+	Currency string
+	Amount   float64
+}
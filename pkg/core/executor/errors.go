@@ -0,0 +1,53 @@
+package executor
+
+import "errors"
+
+// Trading providers wrap their failures with one of these errors, so strategies can react to the cause
+// instead of stopping on every failure.
+var (
+	ErrAuth              = errors.New("authorization failed")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrMarketClosed      = errors.New("market closed")
+	ErrRateLimited       = errors.New("rate limited")
+	ErrInvalidParams     = errors.New("invalid parameters")
+	ErrTransient         = errors.New("transient failure")
+)
+
+// errorAction is how a strategy reacts to a failed trading operation.
+type errorAction string
+
+const (
+	// actionRetry keeps the strategy running and tries again after a delay.
+	actionRetry errorAction = "retry"
+	// actionSkip drops the failed order, the strategy keeps running.
+	actionSkip errorAction = "skip"
+	// actionPause pauses the strategy until it is resumed through the control interfaces.
+	actionPause errorAction = "pause"
+	// actionStop stops the strategy in the errored state.
+	actionStop errorAction = "stop"
+)
+
+// errorClasses lists the reaction to each error class, errors of no class stop the strategy.
+var errorClasses = []struct {
+	err    error
+	kind   string
+	action errorAction
+}{
+	{ErrAuth, "auth", actionStop},
+	{ErrInsufficientFunds, "insufficient_funds", actionPause},
+	{ErrMarketClosed, "market_closed", actionSkip},
+	{ErrRateLimited, "rate_limited", actionRetry},
+	{ErrInvalidParams, "invalid_params", actionSkip},
+	{ErrTransient, "transient", actionRetry},
+}
+
+// classify returns the class of err and the reaction to it.
+func classify(err error) (kind string, action errorAction) {
+	for _, c := range errorClasses {
+		if errors.Is(err, c.err) {
+			return c.kind, c.action
+		}
+	}
+
+	return "unknown", actionStop
+}
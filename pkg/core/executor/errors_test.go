@@ -0,0 +1,174 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_OrderErrors(t *testing.T) {
+	start := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		err        error
+		name       string
+		wantAction string
+		wantState  StrategyState
+		failures   int
+		wantOpened bool
+		wantBreach bool
+	}{
+		{
+			name:       "transient failure is retried after a delay",
+			err:        fmt.Errorf("%w: connection closed", ErrTransient),
+			failures:   maxOrderAttempts,
+			wantAction: "retry",
+			wantState:  StrategyStateWaitingToOpen,
+			wantOpened: true,
+		},
+		{
+			name:       "market closed skips the order",
+			err:        fmt.Errorf("%w: try later", ErrMarketClosed),
+			failures:   1,
+			wantAction: "skip",
+			wantState:  StrategyStateWaitingToOpen,
+			wantOpened: true,
+		},
+		{
+			name:       "insufficient funds pauses the strategy",
+			err:        fmt.Errorf("%w: balance 5", ErrInsufficientFunds),
+			failures:   1,
+			wantAction: "pause",
+			wantState:  StrategyStatePaused,
+			wantBreach: true,
+		},
+		{
+			name:       "invalid token stops the strategy",
+			err:        fmt.Errorf("%w: invalid token", ErrAuth),
+			failures:   1,
+			wantAction: "stop",
+			wantState:  StrategyStateErrored,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			market := &fakeMarket{ticks: make(chan signal.Tick)}
+			bus := event.NewBus()
+			trading := &fakeTrading{}
+			for range tt.failures {
+				trading.buyErrs = append(trading.buyErrs, tt.err)
+			}
+
+			svc := New(market, trading, bus)
+			svc.backoff = noBackoff
+
+			events, unsubscribe := bus.Subscribe(event.TypeOrderFailed)
+			defer unsubscribe()
+
+			breaches := riskBreaches.WithLabelValues("test", "insufficient_funds")
+			counted := testutil.ToFloat64(breaches)
+
+			require.NoError(t, svc.AddStrategy(Strategy{
+				Name:         "test",
+				Symbol:       "R_100",
+				Type:         StrategyTypeBuy,
+				Amount:       10,
+				Leverage:     100,
+				CheckToOpen:  func(signal.Tick) bool { return true },
+				CheckToClose: func(signal.Tick) bool { return false },
+			}))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+
+			go func() { done <- svc.Run(ctx) }()
+
+			market.ticks <- signal.Tick{Time: start, Quote: 100}
+
+			failed := (<-events).Payload.(event.OrderFailed)
+			assert.Equal(t, tt.wantAction, failed.Action)
+
+			if tt.wantBreach {
+				counted++
+			}
+
+			require.Eventually(t, func() bool {
+				st, err := svc.Strategy("test")
+				return err == nil && st.State == tt.wantState
+			}, time.Second, 10*time.Millisecond)
+
+			if tt.wantState != StrategyStateErrored {
+				// Orders are held back for the retry delay after a failure.
+				market.ticks <- signal.Tick{Time: start.Add(time.Second), Quote: 100}
+				market.ticks <- signal.Tick{Time: start.Add(orderRetryDelay), Quote: 100}
+				market.ticks <- signal.Tick{Time: start.Add(orderRetryDelay + time.Second), Quote: 100}
+			}
+
+			assert.InDelta(t, counted, testutil.ToFloat64(breaches), 0)
+
+			if tt.wantOpened {
+				assert.Eventually(t, func() bool { return len(svc.Positions()) == 1 }, time.Second, 10*time.Millisecond)
+			} else {
+				assert.Empty(t, svc.Positions())
+			}
+
+			cancel()
+			assert.NoError(t, <-done)
+		})
+	}
+}
+
+func TestService_CloseRetried(t *testing.T) {
+	market := &fakeMarket{ticks: make(chan signal.Tick)}
+	trading := &fakeTrading{}
+	svc := New(market, &failingClose{fakeTrading: trading, errs: []error{ErrRateLimited, ErrRateLimited, ErrRateLimited}}, event.NewBus())
+	svc.backoff = noBackoff
+
+	require.NoError(t, svc.AddStrategy(Strategy{
+		Name:         "test",
+		Symbol:       "R_100",
+		Type:         StrategyTypeBuy,
+		Amount:       10,
+		Leverage:     100,
+		CheckToOpen:  func(tick signal.Tick) bool { return tick.Quote == 100 },
+		CheckToClose: func(tick signal.Tick) bool { return tick.Quote == 110 },
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- svc.Run(ctx) }()
+
+	start := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	market.ticks <- signal.Tick{Time: start, Quote: 100}
+	require.Eventually(t, func() bool { return len(svc.Positions()) == 1 }, time.Second, 10*time.Millisecond)
+
+	market.ticks <- signal.Tick{Time: start.Add(time.Second), Quote: 110}
+	require.Eventually(t, func() bool {
+		st, err := svc.Strategy("test")
+		return err == nil && st.Order.State == OrderStateFilled && st.Order.Error != ""
+	}, time.Second, 10*time.Millisecond)
+
+	// The close signal isn't repeated, the failed close is retried once the rate limit delay has passed.
+	market.ticks <- signal.Tick{Time: start.Add(2 * time.Second), Quote: 105}
+	market.ticks <- signal.Tick{Time: start.Add(time.Second + rateLimitDelay), Quote: 105}
+	market.ticks <- signal.Tick{Time: start.Add(2*time.Second + rateLimitDelay), Quote: 105}
+
+	assert.Eventually(t, func() bool { return len(svc.Positions()) == 0 }, time.Second, 10*time.Millisecond)
+
+	trading.mu.Lock()
+	assert.Equal(t, []int{1}, trading.closed)
+	trading.mu.Unlock()
+
+	cancel()
+	assert.NoError(t, <-done)
+}
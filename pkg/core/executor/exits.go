@@ -0,0 +1,169 @@
+package executor
+
+import "math"
+
+// Exits closing a position independently of the close rule, published as the signal of the close.
+const (
+	exitTrailingStop = "trailing_stop"
+	exitBreakEven    = "break_even"
+	exitRetrace      = "retrace"
+)
+
+// TrailingMode is the unit of the distance of a trailing stop.
+type TrailingMode string
+
+const (
+	// TrailingPrice keeps the stop Distance price units away from the best quote.
+	TrailingPrice TrailingMode = "price"
+	// TrailingPercent keeps the stop Distance percent of the best quote away from it.
+	TrailingPercent TrailingMode = "percent"
+	// TrailingATR keeps the stop Distance times the average true range of ticks away from the best quote.
+	TrailingATR TrailingMode = "atr"
+)
+
+// TrailingStop follows the best quote since the position was opened and closes the position when the quote
+// falls back to the stop. The stop only moves in the direction of the trade. A zero Distance disables it.
+type TrailingStop struct {
+	Mode      TrailingMode
+	Distance  float64
+	ATRPeriod int
+}
+
+// BreakEven moves the stop to the entry price, shifted by OffsetPct percent in the direction of the trade, once
+// the quote moved ProfitPct percent in favor of the position. A zero ProfitPct disables it.
+type BreakEven struct {
+	ProfitPct float64
+	OffsetPct float64
+}
+
+// Retrace closes the position when it gives back RetracePct percent of its best profit, once the profit reached
+// ProfitPct percent of the entry price. It's disabled unless both are set.
+type Retrace struct {
+	ProfitPct  float64
+	RetracePct float64
+}
+
+// ExitPolicy defines exits of a strategy evaluated on every tick against the open position, in addition to
+// the close rule.
+type ExitPolicy struct {
+	TrailingStop TrailingStop
+	BreakEven    BreakEven
+	Retrace      Retrace
+}
+
+// exitTracker tracks the best quote and the stop of an open position. It is only used by the trading loop.
+type exitTracker struct {
+	policy   ExitPolicy
+	stopKind string
+	entry    float64
+	best     float64
+	stop     float64
+	dir      float64
+}
+
+func newExitTracker(policy ExitPolicy, typ StrategyType, entry float64) *exitTracker {
+	dir := 1.0
+	if typ == StrategyTypeSell {
+		dir = -1
+	}
+
+	return &exitTracker{policy: policy, entry: entry, best: entry, dir: dir}
+}
+
+// update moves the best quote and the stop with quote, atr is the current average true range of ticks.
+// Returns the exit triggered by quote, or an empty string.
+func (t *exitTracker) update(quote, atr float64) string {
+	if t.dir*(quote-t.best) > 0 {
+		t.best = quote
+	}
+
+	ts := t.policy.TrailingStop
+	if ts.Distance > 0 {
+		var distance float64
+
+		switch ts.Mode {
+		case TrailingPrice:
+			distance = ts.Distance
+		case TrailingPercent:
+			distance = t.best * ts.Distance / 100
+		case TrailingATR:
+			distance = atr * ts.Distance
+		}
+
+		if distance > 0 {
+			t.tighten(t.best-t.dir*distance, exitTrailingStop)
+		}
+	}
+
+	if be := t.policy.BreakEven; be.ProfitPct > 0 && t.profitPct(t.best) >= be.ProfitPct {
+		t.tighten(t.entry*(1+t.dir*be.OffsetPct/100), exitBreakEven)
+	}
+
+	if t.stopKind != "" && t.dir*(quote-t.stop) <= 0 {
+		return t.stopKind
+	}
+
+	rt := t.policy.Retrace
+	if rt.ProfitPct <= 0 || rt.RetracePct <= 0 {
+		return ""
+	}
+
+	if peak := t.profitPct(t.best); peak >= rt.ProfitPct && t.profitPct(quote) <= peak*(1-rt.RetracePct/100) {
+		return exitRetrace
+	}
+
+	return ""
+}
+
+// tighten moves the stop to level if it's closer to the best quote than the current stop.
+func (t *exitTracker) tighten(level float64, kind string) {
+	if t.stopKind == "" || t.dir*(level-t.stop) > 0 {
+		t.stop, t.stopKind = level, kind
+	}
+}
+
+// profitPct returns the move from the entry price to quote in favor of the position, in percent.
+func (t *exitTracker) profitPct(quote float64) float64 {
+	if t.entry == 0 {
+		return 0
+	}
+
+	return t.dir * (quote - t.entry) / t.entry * 100
+}
+
+// tickATR is the average true range of ticks: the absolute change between consecutive quotes, averaged over
+// period changes with Wilder's smoothing.
+type tickATR struct {
+	period int
+	count  int
+	last   float64
+	value  float64
+}
+
+func newTickATR(period int) *tickATR {
+	return &tickATR{period: period}
+}
+
+// add updates the average with quote and returns it, or zero until period changes were seen.
+func (a *tickATR) add(quote float64) float64 {
+	if a.count == 0 && a.last == 0 {
+		a.last = quote
+		return 0
+	}
+
+	tr := math.Abs(quote - a.last)
+	a.last = quote
+	a.count++
+
+	if a.count <= a.period {
+		a.value += (tr - a.value) / float64(a.count)
+	} else {
+		a.value += (tr - a.value) / float64(a.period)
+	}
+
+	if a.count < a.period {
+		return 0
+	}
+
+	return a.value
+}
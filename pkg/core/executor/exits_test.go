@@ -0,0 +1,167 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitTracker(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   ExitPolicy
+		typ      StrategyType
+		quotes   []float64
+		atr      float64
+		wantExit string
+		wantAt   int
+		wantStop float64
+	}{
+		{
+			name:     "price trailing stop follows the best quote",
+			policy:   ExitPolicy{TrailingStop: TrailingStop{Mode: TrailingPrice, Distance: 2}},
+			typ:      StrategyTypeBuy,
+			quotes:   []float64{101, 105, 104, 103},
+			wantExit: exitTrailingStop,
+			wantAt:   3,
+			wantStop: 103,
+		},
+		{
+			name:     "percent trailing stop of a short position",
+			policy:   ExitPolicy{TrailingStop: TrailingStop{Mode: TrailingPercent, Distance: 1}},
+			typ:      StrategyTypeSell,
+			quotes:   []float64{99, 90, 90.5, 91},
+			wantExit: exitTrailingStop,
+			wantAt:   3,
+			wantStop: 90.9,
+		},
+		{
+			name:     "atr trailing stop",
+			policy:   ExitPolicy{TrailingStop: TrailingStop{Mode: TrailingATR, Distance: 3}},
+			typ:      StrategyTypeBuy,
+			atr:      0.5,
+			quotes:   []float64{100.5, 102, 101},
+			wantExit: "",
+			wantStop: 100.5,
+		},
+		{
+			name:     "break even after the profit threshold",
+			policy:   ExitPolicy{BreakEven: BreakEven{ProfitPct: 1}},
+			typ:      StrategyTypeBuy,
+			quotes:   []float64{100.5, 101, 100.4, 100},
+			wantExit: exitBreakEven,
+			wantAt:   3,
+			wantStop: 100,
+		},
+		{
+			name: "break even tightens a wide trailing stop",
+			policy: ExitPolicy{
+				TrailingStop: TrailingStop{Mode: TrailingPrice, Distance: 5},
+				BreakEven:    BreakEven{ProfitPct: 2, OffsetPct: 0.5},
+			},
+			typ:      StrategyTypeBuy,
+			quotes:   []float64{102, 100.6, 100.4},
+			wantExit: exitBreakEven,
+			wantAt:   2,
+			wantStop: 100.5,
+		},
+		{
+			name:     "retrace of the best profit",
+			policy:   ExitPolicy{Retrace: Retrace{ProfitPct: 1, RetracePct: 50}},
+			typ:      StrategyTypeBuy,
+			quotes:   []float64{100.5, 102, 101.5, 100.9},
+			wantExit: exitRetrace,
+			wantAt:   3,
+		},
+		{
+			name:     "retrace isn't watched below the profit threshold",
+			policy:   ExitPolicy{Retrace: Retrace{ProfitPct: 1, RetracePct: 50}},
+			typ:      StrategyTypeBuy,
+			quotes:   []float64{100.8, 100.1, 99},
+			wantExit: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := newExitTracker(tt.policy, tt.typ, 100)
+
+			exit, at := "", -1
+
+			for i, q := range tt.quotes {
+				if exit = tracker.update(q, tt.atr); exit != "" {
+					at = i
+					break
+				}
+			}
+
+			assert.Equal(t, tt.wantExit, exit)
+			assert.InDelta(t, tt.wantStop, tracker.stop, 1e-9)
+
+			if tt.wantExit != "" {
+				assert.Equal(t, tt.wantAt, at)
+			}
+		})
+	}
+}
+
+func TestTickATR(t *testing.T) {
+	atr := newTickATR(3)
+
+	assert.Zero(t, atr.add(100))
+	assert.Zero(t, atr.add(101))
+	assert.Zero(t, atr.add(99))
+	assert.InDelta(t, 5.0/3, atr.add(101), 1e-9)
+	assert.InDelta(t, (5.0/3*2+1)/3, atr.add(100), 1e-9)
+}
+
+func TestService_TrailingStop(t *testing.T) {
+	market := &fakeMarket{ticks: make(chan signal.Tick)}
+	trading := &fakeTrading{}
+	bus := event.NewBus()
+	svc := New(market, trading, bus)
+
+	signals, unsubscribe := bus.Subscribe(event.TypeSignalFired)
+	defer unsubscribe()
+
+	require.NoError(t, svc.AddStrategy(Strategy{
+		Name:         "test",
+		Symbol:       "R_100",
+		Type:         StrategyTypeBuy,
+		Amount:       10,
+		Leverage:     100,
+		Exit:         ExitPolicy{TrailingStop: TrailingStop{Mode: TrailingPrice, Distance: 2}},
+		CheckToOpen:  func(tick signal.Tick) bool { return tick.Quote == 100 },
+		CheckToClose: func(signal.Tick) bool { return false },
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- svc.Run(ctx) }()
+
+	market.ticks <- signal.Tick{Quote: 100}
+	require.Eventually(t, func() bool { return len(svc.Positions()) == 1 }, time.Second, 5*time.Millisecond)
+
+	market.ticks <- signal.Tick{Quote: 105}
+	market.ticks <- signal.Tick{Quote: 104}
+
+	require.Eventually(t, func() bool {
+		pos := svc.Positions()
+		return len(pos) == 1 && pos[0].StopPrice == 103
+	}, time.Second, 5*time.Millisecond)
+
+	market.ticks <- signal.Tick{Quote: 102.5}
+	require.Eventually(t, func() bool { return len(svc.Positions()) == 0 }, time.Second, 5*time.Millisecond)
+
+	assert.Equal(t, signalOpen, (<-signals).Payload.(event.SignalFired).Signal)
+	assert.Equal(t, exitTrailingStop, (<-signals).Payload.(event.SignalFired).Signal)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
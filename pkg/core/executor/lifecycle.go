@@ -0,0 +1,135 @@
+package executor
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
+)
+
+// tradingDay is the length of the period MaxTradesPerDay applies to, days start at midnight UTC.
+const tradingDay = 24 * time.Hour
+
+// setState moves the strategy to state and publishes the transition. It must be called with r.mu held.
+func (r *runner) setState(to StrategyState, reason string) {
+	from := r.state
+	if from == to {
+		return
+	}
+
+	r.state = to
+	r.stateSince = time.Now()
+
+	strategyState.WithLabelValues(r.strategy.Name, string(from)).Set(0)
+	strategyState.WithLabelValues(r.strategy.Name, string(to)).Set(1)
+
+	if r.events == nil {
+		return
+	}
+
+	r.events.Publish(event.New(event.TypeStrategyState, event.StrategyState{
+		Strategy: r.strategy.Name,
+		From:     string(from),
+		To:       string(to),
+		Reason:   reason,
+	}))
+}
+
+// phase returns the state of an active, unpaused strategy at now. It must be called with r.mu held.
+func (r *runner) phase(now time.Time) StrategyState {
+	switch {
+	case r.position != nil || r.orders.inFlight():
+		return StrategyStateInPosition
+	case r.warmUp > 0:
+		return StrategyStateWarmingUp
+	case now.Before(r.cooldownUntil):
+		return StrategyStateCoolingDown
+	default:
+		return StrategyStateWaitingToOpen
+	}
+}
+
+// advance moves an active, unpaused strategy to the state matching its warm-up, position and cooldown.
+// It's called by the trading loop after every event, so a cooldown ends with the first event after it expired.
+func (r *runner) advance() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.state.active() || r.state == StrategyStatePaused {
+		return
+	}
+
+	r.setState(r.phase(time.Now()), "")
+}
+
+// warmedUp counts a tick seen by the rule towards the warm-up of the strategy.
+func (r *runner) warmedUp() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.warmUp > 0 {
+		r.warmUp--
+	}
+}
+
+// canOpen reports whether the strategy is waiting to open a position.
+func (r *runner) canOpen() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.state == StrategyStateWaitingToOpen
+}
+
+// countTrade records a position opened at now against the daily trade limit. When the limit is reached, the
+// strategy cools down until the next day once the position is closed, and a risk breach is published.
+func (r *runner) countTrade(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	day := now.UTC().Truncate(tradingDay)
+	if !day.Equal(r.tradesDay) {
+		r.tradesDay, r.tradesToday = day, 0
+	}
+
+	r.tradesToday++
+
+	limit := r.strategy.MaxTradesPerDay
+	if limit <= 0 || r.tradesToday < limit {
+		return
+	}
+
+	next := day.Add(tradingDay)
+	r.cooldownUntil = later(r.cooldownUntil, next)
+
+	slog.Warn("Daily trade limit reached, no new positions until the next day",
+		slog.String("strategy", r.strategy.Name), slog.Int("limit", limit), slog.Time("until", next))
+
+	riskBreaches.WithLabelValues(r.strategy.Name, "max_trades_per_day").Inc()
+
+	if r.events == nil {
+		return
+	}
+
+	r.events.Publish(event.New(event.TypeRiskBreach, event.RiskBreach{
+		Strategy: r.strategy.Name,
+		Limit:    "max_trades_per_day",
+		Details:  fmt.Sprintf("%d trades opened today, next position after %s", r.tradesToday, next.Format(time.RFC3339)),
+	}))
+}
+
+// startCooldown holds back new positions for the cooldown of the strategy after a position was closed at now.
+func (r *runner) startCooldown(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cooldownUntil = later(r.cooldownUntil, now.Add(r.strategy.Cooldown))
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
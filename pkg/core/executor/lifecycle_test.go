@@ -0,0 +1,145 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Lifecycle(t *testing.T) {
+	market := &fakeMarket{ticks: make(chan signal.Tick)}
+	bus := event.NewBus()
+	svc := New(market, &fakeTrading{}, bus)
+
+	events, unsubscribe := bus.Subscribe(event.TypeStrategyState)
+	defer unsubscribe()
+
+	require.NoError(t, svc.AddStrategy(Strategy{
+		Name:         "test",
+		Symbol:       "R_100",
+		Type:         StrategyTypeBuy,
+		Amount:       10,
+		Leverage:     100,
+		WarmUp:       2,
+		Cooldown:     100 * time.Millisecond,
+		CheckToOpen:  func(signal.Tick) bool { return true },
+		CheckToClose: func(tick signal.Tick) bool { return tick.Quote == 110 },
+	}))
+
+	st, err := svc.Strategy("test")
+	require.NoError(t, err)
+	assert.Equal(t, StrategyStateIdle, st.State)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- svc.Run(ctx) }()
+
+	waitState := func(want StrategyState) {
+		t.Helper()
+
+		require.Eventually(t, func() bool {
+			st, err := svc.Strategy("test")
+			return err == nil && st.State == want
+		}, time.Second, 5*time.Millisecond)
+	}
+
+	// The first tick only warms up the rule, the second one completes the warm-up and opens a position.
+	market.ticks <- signal.Tick{Quote: 100}
+	market.ticks <- signal.Tick{Quote: 100}
+	waitState(StrategyStateInPosition)
+
+	market.ticks <- signal.Tick{Quote: 110}
+	waitState(StrategyStateCoolingDown)
+
+	market.ticks <- signal.Tick{Quote: 100}
+	assert.Empty(t, svc.Positions())
+
+	time.Sleep(100 * time.Millisecond)
+
+	market.ticks <- signal.Tick{Quote: 100}
+	waitState(StrategyStateInPosition)
+
+	require.NoError(t, svc.PauseStrategy("test"))
+	require.NoError(t, svc.ResumeStrategy("test"))
+	require.NoError(t, svc.StopStrategy("test"))
+
+	var got []StrategyState
+
+	for len(got) < 9 {
+		e := <-events
+		got = append(got, StrategyState(e.Payload.(event.StrategyState).To))
+	}
+
+	assert.Equal(t, []StrategyState{
+		StrategyStateWarmingUp,
+		StrategyStateWaitingToOpen,
+		StrategyStateInPosition,
+		StrategyStateCoolingDown,
+		StrategyStateWaitingToOpen,
+		StrategyStateInPosition,
+		StrategyStatePaused,
+		StrategyStateInPosition,
+		StrategyStateStopped,
+	}, got)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestService_MaxTradesPerDay(t *testing.T) {
+	market := &fakeMarket{ticks: make(chan signal.Tick)}
+	bus := event.NewBus()
+	svc := New(market, &fakeTrading{}, bus)
+
+	breaches, unsubscribe := bus.Subscribe(event.TypeRiskBreach)
+	defer unsubscribe()
+
+	counted := testutil.ToFloat64(riskBreaches.WithLabelValues("test", "max_trades_per_day"))
+
+	require.NoError(t, svc.AddStrategy(Strategy{
+		Name:            "test",
+		Symbol:          "R_100",
+		Type:            StrategyTypeBuy,
+		Amount:          10,
+		Leverage:        100,
+		MaxTradesPerDay: 1,
+		CheckToOpen:     func(signal.Tick) bool { return true },
+		CheckToClose:    func(tick signal.Tick) bool { return tick.Quote == 110 },
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- svc.Run(ctx) }()
+
+	market.ticks <- signal.Tick{Quote: 100}
+
+	breach := (<-breaches).Payload.(event.RiskBreach)
+	assert.Equal(t, "max_trades_per_day", breach.Limit)
+	assert.InDelta(t, counted+1, testutil.ToFloat64(riskBreaches.WithLabelValues("test", "max_trades_per_day")), 0)
+
+	market.ticks <- signal.Tick{Quote: 110}
+
+	require.Eventually(t, func() bool {
+		st, err := svc.Strategy("test")
+		return err == nil && st.State == StrategyStateCoolingDown
+	}, time.Second, 5*time.Millisecond)
+
+	market.ticks <- signal.Tick{Quote: 100}
+	assert.Empty(t, svc.Positions())
+
+	st, err := svc.Strategy("test")
+	require.NoError(t, err)
+	assert.Equal(t, 1, st.TradesToday)
+	assert.Equal(t, time.Now().UTC().Truncate(tradingDay).Add(tradingDay), st.CooldownUntil)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
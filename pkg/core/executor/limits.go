@@ -0,0 +1,108 @@
+package executor
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/market"
+)
+
+// ContractLimits provides the parameters accepted for multiplier contracts on a symbol.
+type ContractLimits interface {
+	Limits(symbol string) (market.Limits, bool)
+}
+
+// SetContractLimits makes strategies check their amount and leverage against limits when they are started or updated.
+// It must be called before Run.
+func (s *Service) SetContractLimits(limits ContractLimits) {
+	s.limits = limits
+}
+
+// checkLimits checks the amount and leverage of strategy against the contract limits of its symbol.
+// When the strategy clamps limits, out of range values are replaced by the nearest accepted ones with a warning.
+// Returns the strategy to run and an error wrapping ErrContractLimits if its parameters aren't accepted.
+func (s *Service) checkLimits(strategy Strategy) (Strategy, error) {
+	if s.limits == nil {
+		return strategy, nil
+	}
+
+	limits, ok := s.limits.Limits(strategy.Symbol)
+	if !ok {
+		return strategy, fmt.Errorf("%w: no limits known for symbol %s", ErrContractLimits, strategy.Symbol)
+	}
+
+	amount := clampStake(strategy.Amount, limits)
+	leverage := nearestMultiplier(strategy.Leverage, limits.Multipliers)
+
+	if amount == strategy.Amount && leverage == strategy.Leverage {
+		return strategy, nil
+	}
+
+	riskBreaches.WithLabelValues(strategy.Name, "contract_limits").Inc()
+
+	if !strategy.ClampLimits {
+		return strategy, fmt.Errorf("%w: strategy %s trades amount %g with leverage %g, %s accepts stakes %s and multipliers %v",
+			ErrContractLimits, strategy.Name, strategy.Amount, strategy.Leverage, strategy.Symbol,
+			stakeRange(limits), limits.Multipliers)
+	}
+
+	slog.Warn("Strategy parameters clamped to contract limits",
+		slog.String("strategy", strategy.Name),
+		slog.Float64("amount", strategy.Amount),
+		slog.Float64("clamped_amount", amount),
+		slog.Float64("leverage", strategy.Leverage),
+		slog.Float64("clamped_leverage", leverage),
+	)
+
+	strategy.Amount = amount
+	strategy.Leverage = leverage
+
+	return strategy, nil
+}
+
+// clampStake returns amount limited to the published stake bounds.
+func clampStake(amount float64, limits market.Limits) float64 {
+	if limits.MinStake > 0 && amount < limits.MinStake {
+		return limits.MinStake
+	}
+
+	if limits.MaxStake > 0 && amount > limits.MaxStake {
+		return limits.MaxStake
+	}
+
+	return amount
+}
+
+// nearestMultiplier returns leverage if it is accepted, otherwise the closest accepted multiplier,
+// preferring the lower one on ties. Any leverage is accepted when no multipliers are published.
+func nearestMultiplier(leverage float64, multipliers []float64) float64 {
+	if len(multipliers) == 0 || slices.Contains(multipliers, leverage) {
+		return leverage
+	}
+
+	nearest := multipliers[0]
+
+	for _, m := range multipliers[1:] {
+		d, best := math.Abs(m-leverage), math.Abs(nearest-leverage)
+		if d < best || (d == best && m < nearest) {
+			nearest = m
+		}
+	}
+
+	return nearest
+}
+
+func stakeRange(limits market.Limits) string {
+	switch {
+	case limits.MinStake > 0 && limits.MaxStake > 0:
+		return fmt.Sprintf("%g to %g", limits.MinStake, limits.MaxStake)
+	case limits.MinStake > 0:
+		return fmt.Sprintf("from %g", limits.MinStake)
+	case limits.MaxStake > 0:
+		return fmt.Sprintf("up to %g", limits.MaxStake)
+	default:
+		return "of any size"
+	}
+}
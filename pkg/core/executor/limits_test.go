@@ -0,0 +1,129 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
+	"github.com/ksysoev/deriv-bot/pkg/core/market"
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLimits map[string]market.Limits
+
+func (l fakeLimits) Limits(symbol string) (market.Limits, bool) {
+	limits, ok := l[symbol]
+	return limits, ok
+}
+
+func TestService_CheckLimits(t *testing.T) {
+	limits := fakeLimits{"R_100": {Multipliers: []float64{40, 100, 200}, MinStake: 1, MaxStake: 2000}}
+
+	tests := []struct {
+		name         string
+		strategy     Strategy
+		wantErr      bool
+		wantBreach   bool
+		wantAmount   float64
+		wantLeverage float64
+	}{
+		{
+			name:         "accepted",
+			strategy:     Strategy{Symbol: "R_100", Amount: 10, Leverage: 100},
+			wantAmount:   10,
+			wantLeverage: 100,
+		},
+		{
+			name:       "invalid multiplier",
+			strategy:   Strategy{Symbol: "R_100", Amount: 10, Leverage: 150},
+			wantErr:    true,
+			wantBreach: true,
+		},
+		{
+			name:       "stake too high",
+			strategy:   Strategy{Symbol: "R_100", Amount: 5000, Leverage: 100},
+			wantErr:    true,
+			wantBreach: true,
+		},
+		{
+			name:     "unknown symbol",
+			strategy: Strategy{Symbol: "R_1000", Amount: 10, Leverage: 100, ClampLimits: true},
+			wantErr:  true,
+		},
+		{
+			name:         "clamped",
+			strategy:     Strategy{Symbol: "R_100", Amount: 0.5, Leverage: 150, ClampLimits: true},
+			wantBreach:   true,
+			wantAmount:   1,
+			wantLeverage: 100,
+		},
+		{
+			name:         "clamped to nearest multiplier",
+			strategy:     Strategy{Symbol: "R_100", Amount: 5000, Leverage: 1000, ClampLimits: true},
+			wantBreach:   true,
+			wantAmount:   2000,
+			wantLeverage: 200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := New(&fakeMarket{}, &fakeTrading{}, event.NewBus())
+			svc.SetContractLimits(limits)
+
+			breaches := riskBreaches.WithLabelValues(tt.strategy.Name, "contract_limits")
+			counted := testutil.ToFloat64(breaches)
+
+			got, err := svc.checkLimits(tt.strategy)
+
+			if tt.wantBreach {
+				counted++
+			}
+
+			assert.InDelta(t, counted, testutil.ToFloat64(breaches), 0)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrContractLimits)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.InDelta(t, tt.wantAmount, got.Amount, 0)
+			assert.InDelta(t, tt.wantLeverage, got.Leverage, 0)
+		})
+	}
+}
+
+func TestService_StartOutsideLimits(t *testing.T) {
+	market := &fakeMarket{ticks: make(chan signal.Tick)}
+	svc := New(market, &fakeTrading{}, event.NewBus())
+	svc.SetContractLimits(fakeLimits{"R_100": {Multipliers: []float64{40, 100}}})
+
+	strategy := Strategy{
+		Name:         "test",
+		Symbol:       "R_100",
+		Type:         StrategyTypeBuy,
+		Amount:       10,
+		Leverage:     10,
+		CheckToOpen:  func(signal.Tick) bool { return false },
+		CheckToClose: func(signal.Tick) bool { return false },
+	}
+
+	require.NoError(t, svc.AddStrategy(strategy))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.ErrorIs(t, svc.Run(ctx), ErrContractLimits)
+
+	strategy.ClampLimits = true
+	require.NoError(t, svc.UpdateStrategy(strategy))
+
+	st, err := svc.Strategy("test")
+	require.NoError(t, err)
+	assert.InDelta(t, 40, st.Leverage, 0)
+}
@@ -0,0 +1,50 @@
+package executor
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	openPositions = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "deriv_bot",
+		Name:      "open_positions",
+		Help:      "Number of open positions per strategy.",
+	}, []string{"strategy"})
+
+	realizedPnL = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "deriv_bot",
+		Name:      "realized_pnl",
+		Help:      "Profit and loss of closed positions per strategy since the process start, in account currency.",
+	}, []string{"strategy"})
+
+	unrealizedPnL = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "deriv_bot",
+		Name:      "unrealized_pnl",
+		Help:      "Estimated profit and loss of the open position per strategy, in account currency.",
+	}, []string{"strategy"})
+
+	strategyState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "deriv_bot",
+		Name:      "strategy_state",
+		Help:      "Lifecycle state of each strategy, 1 for the current state and 0 for the others.",
+	}, []string{"strategy", "state"})
+
+	orderRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "deriv_bot",
+		Name:      "order_retries_total",
+		Help:      "Number of orders sent again after a transient or rate limit failure, per strategy and operation.",
+	}, []string{"strategy", "operation"})
+
+	riskBreaches = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "deriv_bot",
+		Name:      "risk_breaches_total",
+		Help:      "Number of times a strategy reached a risk limit, per strategy and limit.",
+	}, []string{"strategy", "limit"})
+
+	wsReconnects = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "deriv_bot",
+		Name:      "ws_reconnects_total",
+		Help:      "Number of times a strategy lost its market connection and subscribed again, per strategy.",
+	}, []string{"strategy"})
+)
@@ -0,0 +1,183 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+)
+
+// OrderState is a step in the lifecycle of a strategy order. An opening order goes from pending to submitted and ends
+// up filled or rejected. Closing a filled order moves it to closing, then to closed, or back to filled when the
+// close fails.
+type OrderState string
+
+const (
+	OrderStatePending   OrderState = "pending"
+	OrderStateSubmitted OrderState = "submitted"
+	OrderStateFilled    OrderState = "filled"
+	OrderStateRejected  OrderState = "rejected"
+	OrderStateClosing   OrderState = "closing"
+	OrderStateClosed    OrderState = "closed"
+)
+
+// Order is the latest order of a strategy. Error holds the failure of the last operation on the order, if any.
+type Order struct {
+	UpdatedAt  time.Time
+	State      OrderState
+	Error      string
+	Price      float64
+	ID         int
+	ContractID int
+}
+
+// orderResult is the outcome of an order operation executed by the order manager.
+type orderResult struct {
+	err        error
+	tick       signal.Tick
+	operation  string
+	contractID int
+	soldFor    float64
+}
+
+// orderManager executes the orders of a strategy outside of its trading loop, one at a time, and tracks the state
+// of the latest order. Outcomes are delivered on results, which is only received from by the trading loop.
+type orderManager struct {
+	results chan orderResult
+	order   *Order
+	nextID  int
+	busy    bool
+	mu      sync.Mutex
+}
+
+func newOrderManager() *orderManager {
+	return &orderManager{results: make(chan orderResult, 1)}
+}
+
+// submitOpen starts opening a position at tick with place, executed in a new goroutine.
+// Returns false if another order is in flight.
+func (m *orderManager) submitOpen(ctx context.Context, tick signal.Tick, place func(context.Context) (int, error)) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.busy {
+		return false
+	}
+
+	m.busy = true
+	m.nextID++
+	m.order = &Order{ID: m.nextID, State: OrderStatePending, Price: tick.Quote, UpdatedAt: time.Now()}
+
+	go func() {
+		m.setState(OrderStateSubmitted)
+
+		cid, err := place(ctx)
+		m.results <- orderResult{operation: signalOpen, tick: tick, contractID: cid, err: err}
+	}()
+
+	return true
+}
+
+// submitClose starts closing the position of contractID with sell, executed in a new goroutine.
+// Returns false if another order is in flight.
+func (m *orderManager) submitClose(
+	ctx context.Context,
+	tick signal.Tick,
+	contractID int,
+	sell func(context.Context) (float64, error),
+) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.busy {
+		return false
+	}
+
+	// A position adopted without an order of this process, or one opened before a restart, gets an order on close.
+	if m.order == nil || m.order.ContractID != contractID {
+		m.nextID++
+		m.order = &Order{ID: m.nextID, ContractID: contractID}
+	}
+
+	m.busy = true
+	m.order.State = OrderStateClosing
+	m.order.Error = ""
+	m.order.UpdatedAt = time.Now()
+
+	go func() {
+		soldFor, err := sell(ctx)
+		m.results <- orderResult{operation: signalClose, tick: tick, contractID: contractID, soldFor: soldFor, err: err}
+	}()
+
+	return true
+}
+
+// finish records the outcome of the order in flight.
+func (m *orderManager) finish(res orderResult) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.busy = false
+
+	if m.order == nil {
+		return
+	}
+
+	m.order.UpdatedAt = time.Now()
+	m.order.Error = ""
+
+	if res.err != nil {
+		m.order.Error = res.err.Error()
+	}
+
+	switch {
+	case res.operation == signalOpen && res.err != nil:
+		m.order.State = OrderStateRejected
+	case res.operation == signalOpen:
+		m.order.State = OrderStateFilled
+		m.order.ContractID = res.contractID
+	case res.err != nil:
+		m.order.State = OrderStateFilled
+	default:
+		m.order.State = OrderStateClosed
+	}
+}
+
+func (m *orderManager) setState(state OrderState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.order.State = state
+	m.order.UpdatedAt = time.Now()
+}
+
+// inFlight reports whether an order is being executed.
+func (m *orderManager) inFlight() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.busy
+}
+
+// closing reports whether the order in flight closes a position.
+func (m *orderManager) closing() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.busy && m.order.State == OrderStateClosing
+}
+
+// current returns a copy of the latest order, or nil if the strategy hasn't ordered yet.
+func (m *orderManager) current() *Order {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.order == nil {
+		return nil
+	}
+
+	order := *m.order
+
+	return &order
+}
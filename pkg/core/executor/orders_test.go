@@ -0,0 +1,132 @@
+package executor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowFill holds buy orders until release is closed.
+type slowFill struct {
+	*fakeTrading
+	release chan struct{}
+	buys    atomic.Int32
+}
+
+func (t *slowFill) Buy(ctx context.Context, pos Position) (int, error) {
+	t.buys.Add(1)
+	<-t.release
+
+	return t.fakeTrading.Buy(ctx, pos)
+}
+
+func TestService_OrderInFlight(t *testing.T) {
+	market := &fakeMarket{ticks: make(chan signal.Tick)}
+	trading := &slowFill{fakeTrading: &fakeTrading{}, release: make(chan struct{})}
+	svc := New(market, trading, event.NewBus())
+
+	var seen atomic.Int32
+
+	require.NoError(t, svc.AddStrategy(Strategy{
+		Name:     "test",
+		Symbol:   "R_100",
+		Type:     StrategyTypeBuy,
+		Amount:   10,
+		Leverage: 100,
+		CheckToOpen: func(signal.Tick) bool {
+			seen.Add(1)
+			return true
+		},
+		CheckToClose: func(tick signal.Tick) bool {
+			seen.Add(1)
+			return tick.Quote == 110
+		},
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- svc.Run(ctx) }()
+
+	market.ticks <- signal.Tick{Quote: 100}
+
+	require.Eventually(t, func() bool { return trading.buys.Load() == 1 }, time.Second, 10*time.Millisecond)
+
+	// The strategy keeps seeing ticks while the order is in flight, without ordering again.
+	for _, quote := range []float64{101, 110, 102} {
+		market.ticks <- signal.Tick{Quote: quote}
+	}
+
+	st, err := svc.Strategy("test")
+	require.NoError(t, err)
+	require.NotNil(t, st.Order)
+	assert.Equal(t, OrderStateSubmitted, st.Order.State)
+	assert.GreaterOrEqual(t, seen.Load(), int32(3))
+	assert.Equal(t, int32(1), trading.buys.Load())
+
+	// The close signal fired while opening is acted on once the position is filled.
+	close(trading.release)
+
+	require.Eventually(t, func() bool {
+		st, err := svc.Strategy("test")
+		return err == nil && st.Order.State == OrderStateClosed
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Empty(t, svc.Positions())
+	assert.Equal(t, int32(1), trading.buys.Load())
+
+	trading.mu.Lock()
+	assert.Equal(t, []int{1}, trading.closed)
+	trading.mu.Unlock()
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestOrderManager_States(t *testing.T) {
+	m := newOrderManager()
+	ctx := context.Background()
+
+	assert.Nil(t, m.current())
+
+	require.True(t, m.submitOpen(ctx, signal.Tick{Quote: 100}, func(context.Context) (int, error) { return 7, nil }))
+	assert.False(t, m.submitOpen(ctx, signal.Tick{Quote: 101}, func(context.Context) (int, error) { return 8, nil }))
+
+	m.finish(<-m.results)
+
+	order := m.current()
+	assert.Equal(t, OrderStateFilled, order.State)
+	assert.Equal(t, 7, order.ContractID)
+	assert.InDelta(t, 100, order.Price, 1e-9)
+	assert.False(t, m.inFlight())
+
+	require.True(t, m.submitClose(ctx, signal.Tick{}, 7, func(context.Context) (float64, error) { return 0, ErrTransient }))
+	assert.True(t, m.closing())
+
+	m.finish(<-m.results)
+
+	order = m.current()
+	assert.Equal(t, OrderStateFilled, order.State)
+	assert.Equal(t, ErrTransient.Error(), order.Error)
+
+	require.True(t, m.submitClose(ctx, signal.Tick{}, 7, func(context.Context) (float64, error) { return 12, nil }))
+
+	res := <-m.results
+	m.finish(res)
+
+	assert.InDelta(t, 12, res.soldFor, 1e-9)
+	assert.Equal(t, OrderStateClosed, m.current().State)
+	assert.Equal(t, 1, m.current().ID)
+
+	require.True(t, m.submitOpen(ctx, signal.Tick{}, func(context.Context) (int, error) { return 0, ErrMarketClosed }))
+	m.finish(<-m.results)
+
+	assert.Equal(t, OrderStateRejected, m.current().State)
+	assert.Equal(t, 2, m.current().ID)
+}
@@ -1,5 +1,13 @@
 package executor
 
+import "time"
+
+// Types of the multiplier contracts opened by strategies.
+const (
+	ContractTypeUp   = "MULTUP"
+	ContractTypeDown = "MULTDOWN"
+)
+
 type Position struct {
 	Symbol   string
 	Currency string
@@ -7,3 +15,66 @@ type Position struct {
 	Price    float64
 	Leverage float64
 }
+
+// OpenPosition describes a position opened by a strategy which is not closed yet.
+type OpenPosition struct {
+	OpenedAt time.Time
+	Strategy string
+	Symbol   string
+	Type     StrategyType
+	Amount   float64
+	Price    float64
+	Leverage float64
+	// StopPrice is the quote at which the trailing or break-even stop closes the position, zero without a stop.
+	StopPrice  float64
+	ContractID int
+}
+
+// ClosedTrade describes a position opened and closed by a strategy.
+type ClosedTrade struct {
+	OpenedAt   time.Time
+	ClosedAt   time.Time
+	Strategy   string
+	Symbol     string
+	Type       StrategyType
+	Amount     float64
+	Price      float64
+	Leverage   float64
+	Profit     float64
+	ContractID int
+}
+
+// Contract describes an open contract on the trading account, including contracts not opened by the bot.
+type Contract struct {
+	PurchasedAt  time.Time
+	LoginID      string
+	Symbol       string
+	ContractType string
+	Currency     string
+	BuyPrice     float64
+	// Multiplier is the multiplier of the contract, zero if the trading provider doesn't report it.
+	Multiplier float64
+	ID         int
+}
+
+// PnL estimates the profit or loss of the position at the given quote.
+// For multiplier contracts the stake is scaled by leverage and the relative price move, commission is not accounted for.
+func (p *OpenPosition) PnL(quote float64) float64 {
+	if p.Price == 0 {
+		return 0
+	}
+
+	pnl := p.Amount * p.Leverage * (quote - p.Price) / p.Price
+	if p.Type == StrategyTypeSell {
+		pnl = -pnl
+	}
+
+	return pnl
+}
+
+// SoldContract describes a contract of the trading account which was sold, by the bot or by the platform.
+type SoldContract struct {
+	SoldAt    time.Time
+	SellPrice float64
+	ID        int
+}
@@ -0,0 +1,114 @@
+package executor_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
+	"github.com/ksysoev/deriv-bot/pkg/core/executor"
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+	"github.com/ksysoev/deriv-bot/pkg/prov/deriv"
+	"github.com/ksysoev/deriv-bot/pkg/prov/deriv/derivtest"
+	"github.com/ksysoev/deriv-bot/pkg/repo/subsmng"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_ResubscribesAfterDisconnect(t *testing.T) {
+	srv := derivtest.New(
+		derivtest.WithTicks("R_100", 100, 101, 102),
+		derivtest.WithTickInterval(10*time.Millisecond),
+		derivtest.WithAccount("token", derivtest.Account{LoginID: "VRTC1", Currency: "USD", Balance: 1000}),
+	)
+
+	endpoint, stop := derivtest.Start(srv)
+	defer stop()
+
+	unthrottled := deriv.RateLimit{RatePerMinute: 60_000, Burst: 100}
+
+	api, err := deriv.New(deriv.Config{
+		Endpoint:   endpoint,
+		AppID:      1,
+		Origin:     "http://localhost",
+		RateLimits: deriv.RateLimits{Trading: unthrottled, MarketData: unthrottled},
+	})
+	require.NoError(t, err)
+
+	defer api.Close()
+
+	bus := event.NewBus()
+
+	events, unsubscribe := bus.Subscribe(event.TypeOrderPlaced, event.TypePositionClosed)
+	defer unsubscribe()
+
+	reconnects, unsubscribeReconnects := bus.Subscribe(event.TypeReconnect)
+	defer unsubscribeReconnects()
+
+	next := func(want event.Type) {
+		t.Helper()
+
+		select {
+		case e := <-events:
+			require.Equal(t, want, e.Type)
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "no event", want)
+		}
+	}
+
+	var closeNow atomic.Bool
+
+	svc := executor.New(signal.New(api, subsmng.New(), bus), api, bus)
+	require.NoError(t, svc.AddStrategy(executor.Strategy{
+		Name:         "r100",
+		Token:        "token",
+		Symbol:       "R_100",
+		Type:         executor.StrategyTypeBuy,
+		Amount:       10,
+		Leverage:     100,
+		CheckToOpen:  func(signal.Tick) bool { return !closeNow.Load() },
+		CheckToClose: func(signal.Tick) bool { return closeNow.Load() },
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- svc.Run(ctx) }()
+
+	defer func() {
+		cancel()
+		require.NoError(t, <-done)
+	}()
+
+	state := func() executor.StrategyState {
+		status, err := svc.Strategy("r100")
+		require.NoError(t, err)
+
+		return status.State
+	}
+
+	next(event.TypeOrderPlaced)
+	require.Len(t, srv.Contracts(), 1)
+
+	srv.Disconnect()
+
+	select {
+	case e := <-reconnects:
+		reconnect := e.Payload.(event.Reconnect)
+		assert.Equal(t, "r100", reconnect.Strategy)
+		assert.Equal(t, 1, reconnect.Attempt)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "no reconnect event")
+	}
+
+	// The strategy keeps its position while the connection is restored.
+	assert.Never(t, func() bool { return state() != executor.StrategyStateInPosition }, 200*time.Millisecond,
+		10*time.Millisecond)
+
+	// Closing the position takes ticks of the new subscription and a sell over the new connection.
+	closeNow.Store(true)
+	next(event.TypePositionClosed)
+
+	assert.Empty(t, srv.Contracts())
+}
@@ -0,0 +1,197 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	// maxOrderAttempts limits the number of times an order is sent before its failure is reported.
+	maxOrderAttempts = 3
+	retryBaseDelay   = 500 * time.Millisecond
+	retryMaxDelay    = 5 * time.Second
+)
+
+// retryBackoff returns the delay before the retry of an order after the attempt-th failure.
+// The delay doubles with every attempt up to retryMaxDelay, half of it is random to spread retries of strategies
+// failing at the same time.
+func retryBackoff(attempt int) time.Duration {
+	d := min(retryBaseDelay<<(attempt-1), retryMaxDelay)
+
+	return d/2 + rand.N(d/2+1)
+}
+
+// placeOrder sends the order of the strategy for pos, retrying transient and rate limit failures with backoff.
+// A transient failure is ambiguous, the order may have been filled before the connection failed, so the portfolio
+// is checked for the contract before the order is sent again. When the check itself fails, it is repeated before
+// the next order of the strategy.
+// Returns the contract ID of the order and an error if it can't be placed.
+func (r *runner) placeOrder(ctx context.Context, s *Service, pos Position) (int, error) {
+	if !r.unverifiedSince.IsZero() {
+		cid, found, err := r.findPlaced(ctx, s, pos, r.unverifiedSince)
+		if err != nil {
+			return 0, fmt.Errorf("failed to check the result of an earlier order: %w", err)
+		}
+
+		r.unverifiedSince = time.Time{}
+
+		if found {
+			return cid, nil
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		sent := time.Now()
+
+		cid, err := r.sendOrder(ctx, pos)
+		if err == nil {
+			// The contract was placed by the strategy, so no other strategy holds it and the claim can't fail.
+			s.claimContract(r.strategy.Name, cid)
+
+			return cid, nil
+		}
+
+		if errors.Is(err, ErrTransient) {
+			cid, found, lookupErr := r.findPlaced(ctx, s, pos, sent)
+
+			switch {
+			case lookupErr != nil:
+				r.unverifiedSince = sent
+
+				return 0, err
+			case found:
+				return cid, nil
+			}
+		}
+
+		if err := r.retryWait(ctx, s, signalOpen, attempt, err); err != nil {
+			return 0, err
+		}
+	}
+}
+
+func (r *runner) sendOrder(ctx context.Context, pos Position) (int, error) {
+	switch r.strategy.Type {
+	case StrategyTypeBuy:
+		return r.tradingSession().Buy(ctx, pos)
+	case StrategyTypeSell:
+		return r.tradingSession().Sell(ctx, pos)
+	case StrategyTypeNotSet:
+		return 0, fmt.Errorf("strategy type not set")
+	default:
+		return 0, fmt.Errorf("unknown strategy type: %d", r.strategy.Type)
+	}
+}
+
+// findPlaced looks for a contract for pos bought since the given time which isn't owned by any strategy.
+// The contract must match the symbol, stake, contract type and multiplier of the order, and is claimed for the
+// strategy, so strategies trading the same stake never take over each other's contracts.
+// Returns the contract ID, whether it was found and an error if the portfolio can't be retrieved.
+func (r *runner) findPlaced(ctx context.Context, s *Service, pos Position, since time.Time) (int, bool, error) {
+	contracts, err := r.tradingSession().Portfolio(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+
+	contractType := r.strategy.Type.ContractType()
+
+	// Purchase times have a resolution of a second.
+	since = since.Truncate(time.Second)
+
+	for _, c := range contracts {
+		if c.Symbol != pos.Symbol || c.BuyPrice != pos.Amount || c.ContractType != contractType ||
+			c.Multiplier != pos.Leverage || c.PurchasedAt.Before(since) {
+			continue
+		}
+
+		if !s.claimContract(r.strategy.Name, c.ID) {
+			continue
+		}
+
+		slog.Warn("Order was placed despite the failure, taking over its contract",
+			slog.String("strategy", r.strategy.Name), slog.Int("contract_id", c.ID))
+
+		return c.ID, true, nil
+	}
+
+	return 0, false, nil
+}
+
+// sellContract closes the contract of pos, retrying transient and rate limit failures with backoff.
+// When closing fails because of the connection or because the contract can't be sold, the contract may have
+// been sold already, by an earlier attempt or by the platform on stop out, so sold contracts are checked first.
+// Returns the amount the contract was sold for and an error if it can't be closed.
+func (r *runner) sellContract(ctx context.Context, s *Service, pos *OpenPosition) (float64, error) {
+	for attempt := 1; ; attempt++ {
+		soldFor, err := r.tradingSession().ClosePosition(ctx, pos.ContractID)
+		if err == nil {
+			return soldFor, nil
+		}
+
+		if errors.Is(err, ErrTransient) || errors.Is(err, ErrInvalidParams) {
+			if soldFor, sold := r.findSold(ctx, s, pos); sold {
+				return soldFor, nil
+			}
+		}
+
+		if err := r.retryWait(ctx, s, signalClose, attempt, err); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// findSold checks whether the contract of pos is no longer open and looks up the amount it was sold for.
+// Returns the amount and true if the contract was sold.
+func (r *runner) findSold(ctx context.Context, s *Service, pos *OpenPosition) (float64, bool) {
+	contracts, err := r.tradingSession().Portfolio(ctx)
+	if err != nil {
+		return 0, false
+	}
+
+	for _, c := range contracts {
+		if c.ID == pos.ContractID {
+			return 0, false
+		}
+	}
+
+	sold, err := r.tradingSession().SoldContracts(ctx, pos.OpenedAt.Truncate(time.Second))
+	if err != nil {
+		return 0, false
+	}
+
+	for _, c := range sold {
+		if c.ID == pos.ContractID {
+			slog.Warn("Contract was sold despite the failure",
+				slog.String("strategy", r.strategy.Name), slog.Int("contract_id", c.ID), slog.Float64("sold_for", c.SellPrice))
+
+			return c.SellPrice, true
+		}
+	}
+
+	return 0, false
+}
+
+// retryWait waits before the next attempt of a failed order.
+// Returns err if the failure isn't retried or attempts are exhausted, and an error if ctx is cancelled.
+func (r *runner) retryWait(ctx context.Context, s *Service, operation string, attempt int, err error) error {
+	if _, action := classify(err); action != actionRetry || attempt >= maxOrderAttempts {
+		return err
+	}
+
+	delay := s.backoff(attempt)
+
+	orderRetries.WithLabelValues(r.strategy.Name, operation).Inc()
+	slog.Warn("Order failed, retrying", slog.String("strategy", r.strategy.Name), slog.String("operation", operation),
+		slog.Int("attempt", attempt), slog.Duration("delay", delay), slog.Any("error", err))
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", err, ctx.Err())
+	case <-time.After(delay):
+		return nil
+	}
+}
@@ -0,0 +1,189 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lostReply fills buy orders but fails them as if the connection dropped before the reply.
+type lostReply struct {
+	*fakeTrading
+}
+
+func (t *lostReply) Buy(ctx context.Context, pos Position) (int, error) {
+	cid, _ := t.fakeTrading.Buy(ctx, pos)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.portfolio = append(t.portfolio, Contract{
+		ID:           cid,
+		Symbol:       pos.Symbol,
+		ContractType: ContractTypeUp,
+		BuyPrice:     pos.Amount,
+		Multiplier:   pos.Leverage,
+		PurchasedAt:  time.Now(),
+	})
+
+	return 0, fmt.Errorf("%w: connection closed", ErrTransient)
+}
+
+// failingClose fails the first close requests with errs.
+type failingClose struct {
+	*fakeTrading
+	errs []error
+}
+
+func (t *failingClose) ClosePosition(ctx context.Context, contractID int) (float64, error) {
+	t.mu.Lock()
+
+	if len(t.errs) > 0 {
+		err := t.errs[0]
+		t.errs = t.errs[1:]
+		t.mu.Unlock()
+
+		return 0, err
+	}
+
+	t.mu.Unlock()
+
+	return t.fakeTrading.ClosePosition(ctx, contractID)
+}
+
+func TestService_OrderRetries(t *testing.T) {
+	tests := []struct {
+		trading    func(*fakeTrading) TradingProvider
+		name       string
+		wantClosed []int
+		wantProfit float64
+	}{
+		{
+			name: "transient failures are retried",
+			trading: func(t *fakeTrading) TradingProvider {
+				t.buyErrs = []error{fmt.Errorf("%w: timeout", ErrTransient), fmt.Errorf("%w: slow down", ErrRateLimited)}
+				return t
+			},
+			wantClosed: []int{1},
+			wantProfit: 0,
+		},
+		{
+			name:       "filled order is taken over instead of sent again",
+			trading:    func(t *fakeTrading) TradingProvider { return &lostReply{fakeTrading: t} },
+			wantClosed: []int{1},
+			wantProfit: 0,
+		},
+		{
+			name: "sold contract is reconciled",
+			trading: func(t *fakeTrading) TradingProvider {
+				t.sold = []SoldContract{{ID: 1, SellPrice: 12}}
+				return &failingClose{fakeTrading: t, errs: []error{fmt.Errorf("%w: not open", ErrInvalidParams)}}
+			},
+			wantProfit: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			market := &fakeMarket{ticks: make(chan signal.Tick)}
+			trading := &fakeTrading{}
+			bus := event.NewBus()
+			svc := New(market, tt.trading(trading), bus)
+			svc.backoff = noBackoff
+
+			events, unsubscribe := bus.Subscribe(event.TypePositionClosed)
+			defer unsubscribe()
+
+			require.NoError(t, svc.AddStrategy(Strategy{
+				Name:         "test",
+				Symbol:       "R_100",
+				Type:         StrategyTypeBuy,
+				Amount:       10,
+				Leverage:     100,
+				CheckToOpen:  func(tick signal.Tick) bool { return tick.Quote == 100 },
+				CheckToClose: func(tick signal.Tick) bool { return tick.Quote == 110 },
+			}))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+
+			go func() { done <- svc.Run(ctx) }()
+
+			market.ticks <- signal.Tick{Quote: 100}
+			market.ticks <- signal.Tick{Quote: 110}
+
+			closed := (<-events).Payload.(event.PositionClosed)
+			assert.Equal(t, 1, closed.ContractID)
+			assert.InDelta(t, tt.wantProfit, closed.Profit, 1e-9)
+
+			trading.mu.Lock()
+			assert.Equal(t, 1, trading.nextID, "a single contract is bought")
+			assert.Equal(t, tt.wantClosed, trading.closed)
+			trading.mu.Unlock()
+
+			cancel()
+			assert.NoError(t, <-done)
+		})
+	}
+}
+
+func TestRunner_FindPlacedSameStake(t *testing.T) {
+	now := time.Now()
+	trading := &fakeTrading{portfolio: []Contract{
+		{ID: 1, Symbol: "R_100", ContractType: ContractTypeUp, BuyPrice: 10, Multiplier: 100, PurchasedAt: now},
+		{ID: 2, Symbol: "R_100", ContractType: ContractTypeDown, BuyPrice: 10, Multiplier: 100, PurchasedAt: now},
+		{ID: 3, Symbol: "R_100", ContractType: ContractTypeUp, BuyPrice: 10, Multiplier: 50, PurchasedAt: now},
+		{ID: 4, Symbol: "R_100", ContractType: ContractTypeUp, BuyPrice: 10, Multiplier: 100, PurchasedAt: now},
+	}}
+	svc := New(&fakeMarket{}, trading, event.NewBus())
+
+	for _, st := range []Strategy{
+		{Name: "holder", Symbol: "R_100", Type: StrategyTypeBuy, Amount: 10, Leverage: 100},
+		{Name: "long", Symbol: "R_100", Type: StrategyTypeBuy, Amount: 10, Leverage: 100},
+		{Name: "second-long", Symbol: "R_100", Type: StrategyTypeBuy, Amount: 10, Leverage: 100},
+		{Name: "short", Symbol: "R_100", Type: StrategyTypeSell, Amount: 10, Leverage: 100},
+	} {
+		require.NoError(t, svc.AddStrategy(st))
+		svc.runners[st.Name].trading = trading
+	}
+
+	svc.runners["holder"].setPosition(&OpenPosition{ContractID: 1, Strategy: "holder"})
+
+	pos := Position{Symbol: "R_100", Amount: 10, Leverage: 100}
+	since := now.Add(-time.Second)
+
+	tests := []struct {
+		strategy string
+		wantID   int
+		wantOK   bool
+	}{
+		// Contract 1 is held, 2 is short and 3 has another multiplier.
+		{strategy: "long", wantID: 4, wantOK: true},
+		// Contract 4 is claimed by the first strategy with the same stake.
+		{strategy: "second-long", wantOK: false},
+		{strategy: "short", wantID: 2, wantOK: true},
+		// A strategy keeps its own claim when it looks again.
+		{strategy: "long", wantID: 4, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		cid, ok, err := svc.runners[tt.strategy].findPlaced(context.Background(), svc, pos, since)
+
+		require.NoError(t, err)
+		assert.Equal(t, tt.wantOK, ok, tt.strategy)
+		assert.Equal(t, tt.wantID, cid, tt.strategy)
+	}
+
+	svc.runners["long"].setPosition(&OpenPosition{ContractID: 4, Strategy: "long"})
+	svc.releaseClaim(4)
+
+	_, ok, err := svc.runners["second-long"].findPlaced(context.Background(), svc, pos, since)
+	require.NoError(t, err)
+	assert.False(t, ok, "contract held by another strategy is not taken over")
+}
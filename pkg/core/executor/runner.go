@@ -0,0 +1,870 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+)
+
+const (
+	signalOpen  = "open"
+	signalClose = "close"
+
+	// orderRetryDelay is the delay before a strategy orders again after a failed order it recovers from.
+	orderRetryDelay = 5 * time.Second
+	// rateLimitDelay is the delay before a strategy orders again after it was rate limited.
+	rateLimitDelay = 30 * time.Second
+)
+
+var (
+	// errResubscribe is returned by the trading loop when an update changed the token or symbol of the strategy.
+	errResubscribe = errors.New("strategy token or symbol changed")
+	// errStreamEnded is returned by the trading loop when the tick stream ended, e.g. because the connection dropped.
+	errStreamEnded = fmt.Errorf("%w: tick stream ended", ErrTransient)
+	// errOrderInFlight is returned when an order is submitted while another order of the strategy is in flight.
+	errOrderInFlight = errors.New("order in flight")
+)
+
+type runner struct {
+	cancel          context.CancelFunc
+	done            chan struct{}
+	shutdownCh      chan struct{}
+	closeReq        chan chan error
+	updateCh        chan struct{}
+	pending         *Strategy
+	orders          *orderManager
+	trading         TradingProvider
+	events          Publisher
+	exit            *exitTracker
+	atr             *tickATR
+	position        *OpenPosition
+	shutdownResult  *ShutdownResult
+	err             error
+	closeWaiters    []chan error
+	strategy        Strategy
+	state           StrategyState
+	stateSince      time.Time
+	cooldownUntil   time.Time
+	tradesDay       time.Time
+	retryAt         time.Time
+	unverifiedSince time.Time
+	warmUp          int
+	tradesToday     int
+	marketClosed    bool
+	outOfSchedule   bool
+	closePending    bool
+	mu              sync.Mutex
+}
+
+func newRunner(strategy Strategy) *runner {
+	strategyState.WithLabelValues(strategy.Name, string(StrategyStateIdle)).Set(1)
+
+	return &runner{
+		strategy:   strategy,
+		state:      StrategyStateIdle,
+		stateSince: time.Now(),
+		orders:     newOrderManager(),
+		closeReq:   make(chan chan error),
+		updateCh:   make(chan struct{}, 1),
+	}
+}
+
+// status returns a snapshot of the runner state.
+func (r *runner) status() StrategyStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	st := StrategyStatus{
+		Name:          r.strategy.Name,
+		Symbol:        r.strategy.Symbol,
+		Type:          r.strategy.Type,
+		Amount:        r.strategy.Amount,
+		Leverage:      r.strategy.Leverage,
+		State:         r.state,
+		StateSince:    r.stateSince,
+		CooldownUntil: r.cooldownUntil,
+		Order:         r.orders.current(),
+	}
+
+	if r.tradesDay.Equal(time.Now().UTC().Truncate(tradingDay)) {
+		st.TradesToday = r.tradesToday
+	}
+
+	if r.position != nil {
+		pos := *r.position
+		st.Position = &pos
+	}
+
+	if r.err != nil {
+		st.Error = r.err.Error()
+	}
+
+	return st
+}
+
+// token returns the API token of the strategy.
+func (r *runner) token() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.strategy.Token
+}
+
+// start checks the strategy against the contract limits and launches its loop in a new goroutine tracked by wg.
+// The strategy warms up first, the cooldown and daily trade count of an earlier run still apply.
+// Returns ErrInvalidState if the strategy is already active, and ErrContractLimits if its amount
+// or leverage isn't accepted.
+func (r *runner) start(ctx context.Context, s *Service, wg *sync.WaitGroup) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.state.active() {
+		return fmt.Errorf("%w: strategy %s is %s", ErrInvalidState, r.strategy.Name, r.state)
+	}
+
+	strategy, err := s.checkLimits(r.strategy)
+	if err != nil {
+		return err
+	}
+
+	r.strategy = strategy
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	r.cancel = cancel
+	r.done = make(chan struct{})
+	r.shutdownCh = make(chan struct{})
+	r.shutdownResult = nil
+	r.events = s.events
+	r.warmUp = strategy.WarmUp
+	r.err = nil
+
+	r.setState(StrategyStateWarmingUp, "")
+
+	shutdownCh := r.shutdownCh
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		defer close(r.done)
+		defer cancel()
+
+		err := r.run(ctx, s, shutdownCh)
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		if err != nil {
+			slog.Error("Strategy failed", slog.String("strategy", r.strategy.Name), slog.Any("error", err))
+
+			r.err = err
+			r.setState(StrategyStateErrored, err.Error())
+
+			return
+		}
+
+		r.setState(StrategyStateStopped, "")
+	}()
+
+	return nil
+}
+
+// applyLimits checks the strategy against the contract limits and keeps the clamped parameters, if any.
+func (r *runner) applyLimits(s *Service) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	strategy, err := s.checkLimits(r.strategy)
+	if err != nil {
+		return err
+	}
+
+	r.strategy = strategy
+
+	return nil
+}
+
+// stop cancels the strategy loop and waits until it exits.
+// Returns ErrInvalidState if the strategy is not active.
+func (r *runner) stop() error {
+	r.mu.Lock()
+
+	if !r.state.active() {
+		r.mu.Unlock()
+		return fmt.Errorf("%w: strategy %s is %s", ErrInvalidState, r.strategy.Name, r.state)
+	}
+
+	cancel, done := r.cancel, r.done
+	r.mu.Unlock()
+
+	cancel()
+	<-done
+
+	return nil
+}
+
+// setPaused pauses an active strategy for reason, or resumes a paused one in the state matching its position.
+// A paused strategy doesn't open new positions but keeps managing the open one.
+func (r *runner) setPaused(paused bool, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch {
+	case paused && r.state.active() && r.state != StrategyStatePaused:
+		r.setState(StrategyStatePaused, reason)
+	case !paused && r.state == StrategyStatePaused:
+		r.setState(r.phase(time.Now()), reason)
+	default:
+		return fmt.Errorf("%w: strategy %s is %s", ErrInvalidState, r.strategy.Name, r.state)
+	}
+
+	return nil
+}
+
+// requestClose asks the strategy loop to close the open position and waits for the result.
+func (r *runner) requestClose(ctx context.Context) error {
+	r.mu.Lock()
+	done, name := r.done, r.strategy.Name
+	r.mu.Unlock()
+
+	if done == nil {
+		return fmt.Errorf("%w: strategy %s is not running", ErrInvalidState, name)
+	}
+
+	reply := make(chan error, 1)
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-done:
+		return fmt.Errorf("%w: strategy %s is not running", ErrInvalidState, name)
+	case r.closeReq <- reply:
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-reply:
+		return err
+	}
+}
+
+// update replaces the strategy parameters. If the strategy loop is active, the update is handed over to it
+// and applied once the strategy is flat.
+func (r *runner) update(strategy Strategy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.state.active() {
+		r.strategy = strategy
+		r.pending = nil
+
+		return
+	}
+
+	r.pending = &strategy
+
+	select {
+	case r.updateCh <- struct{}{}:
+	default:
+	}
+}
+
+// applyPending switches a flat strategy without an order in flight to the pending parameters, if there are any.
+// Returns errResubscribe if the token or symbol changed, so the loop can authorize and subscribe again.
+func (r *runner) applyPending() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.pending == nil || r.position != nil || r.orders.inFlight() {
+		return nil
+	}
+
+	prev := r.strategy
+	r.strategy = *r.pending
+	r.pending = nil
+
+	slog.Info("Strategy updated", slog.String("strategy", r.strategy.Name))
+
+	if prev.Token != r.strategy.Token || prev.Symbol != r.strategy.Symbol {
+		return errResubscribe
+	}
+
+	return nil
+}
+
+func (r *runner) contractID() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.position == nil {
+		return 0
+	}
+
+	return r.position.ContractID
+}
+
+// heldPosition returns a copy of the open position, or nil if the strategy is flat.
+func (r *runner) heldPosition() *OpenPosition {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.position == nil {
+		return nil
+	}
+
+	pos := *r.position
+
+	return &pos
+}
+
+// tradingSession returns the trading session of the strategy token.
+func (r *runner) tradingSession() TradingProvider {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.trading
+}
+
+func (r *runner) setPosition(pos *OpenPosition) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.position = pos
+}
+
+// run monitors market signals for the strategy symbol, opens a position when CheckToOpen is satisfied and closes it
+// when CheckToClose is satisfied. Orders are executed by the order manager, so the rules keep seeing ticks while
+// an order is in flight, and no other order is submitted until it completes. It also serves close requests coming
+// from the control interfaces and applies parameter updates while the strategy is flat.
+// When shutdownCh is closed, the shutdown policy is applied to the open position before returning.
+// When the tick stream ends, the session is authorized and the symbol subscribed again, and authorization and
+// subscription failing with a transient or rate limit error are retried with backoff.
+// Returns nil when ctx is cancelled or shutdown completes, and an error if authorization, subscription or a trading
+// operation fails in a way the strategy can't recover from.
+func (r *runner) run(ctx context.Context, s *Service, shutdownCh <-chan struct{}) error {
+	for attempt := 1; ; attempt++ {
+		err := r.trade(ctx, s, shutdownCh)
+
+		switch {
+		case errors.Is(err, errResubscribe):
+			attempt = 0
+			continue
+		case err == nil || ctx.Err() != nil:
+			return err
+		}
+
+		if _, action := classify(err); action != actionRetry {
+			return err
+		}
+
+		delay := s.backoff(attempt)
+
+		wsReconnects.WithLabelValues(r.strategy.Name).Inc()
+		slog.Warn("Strategy lost market connection, resubscribing", slog.String("strategy", r.strategy.Name),
+			slog.Int("attempt", attempt), slog.Duration("delay", delay), slog.Any("error", err))
+
+		s.events.Publish(event.New(event.TypeReconnect, event.Reconnect{
+			Strategy: r.strategy.Name,
+			Symbol:   r.strategy.Symbol,
+			Reason:   err.Error(),
+			Attempt:  attempt,
+		}))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-shutdownCh:
+			return nil
+		case <-time.After(delay):
+		}
+	}
+}
+
+// trade authorizes the trading session of the strategy token, subscribes to the strategy symbol and runs the
+// trading loop. Returns errResubscribe if an applied update changed the token or symbol.
+func (r *runner) trade(ctx context.Context, s *Service, shutdownCh <-chan struct{}) error {
+	r.mu.Lock()
+	strategy := r.strategy
+	r.mu.Unlock()
+
+	trading, err := s.session(strategy.Token)
+	if err != nil {
+		return err
+	}
+
+	// Orders read the session from their own goroutines.
+	r.mu.Lock()
+	r.trading = trading
+	r.mu.Unlock()
+
+	acc, err := trading.Authorize(ctx, strategy.Token)
+	if err != nil {
+		return fmt.Errorf("failed to authorize trading provider: %w", err)
+	}
+
+	// The subscription is left when the loop exits, a changed symbol must not keep receiving ticks of the old one.
+	subCtx, unsubscribe := context.WithCancel(ctx)
+	defer unsubscribe()
+
+	tickChan, err := s.marketSignals.SubscribeOnMarket(subCtx, strategy.Symbol)
+	if err != nil {
+		return err
+	}
+
+	var stale staleWatch
+
+	stale.reset(strategy.StaleData.After)
+	defer stale.stop()
+
+	// The outcome of an order is always handled, even when the loop exits while the order is in flight.
+	defer r.drainOrder(s, acc)
+
+	for {
+		r.advance()
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-shutdownCh:
+			r.shutdown(ctx, s, acc, tickChan)
+			return nil
+		case reply := <-r.closeReq:
+			r.serveClose(ctx, s, reply)
+		case res := <-r.orders.results:
+			if err := r.handleOrder(ctx, s, acc, res); err != nil {
+				return err
+			}
+
+			if err := r.applyPending(); err != nil {
+				return err
+			}
+		case <-r.updateCh:
+			if err := r.applyPending(); err != nil {
+				return err
+			}
+		case <-stale.C:
+			r.handleStale(ctx, s)
+
+			stale.reset(r.strategy.StaleData.After)
+		case tick, ok := <-tickChan:
+			if !ok {
+				return errStreamEnded
+			}
+
+			stale.reset(r.strategy.StaleData.After)
+
+			// Shutdown takes priority over ticks, so no new position is opened once it's requested.
+			select {
+			case <-shutdownCh:
+				r.shutdown(ctx, s, acc, tickChan)
+				return nil
+			default:
+			}
+
+			r.warmedUp()
+			r.advance()
+			r.handleTick(ctx, s, acc, tick)
+
+			if err := r.applyPending(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// handleTick evaluates the rules and exits of the strategy on tick and submits the order they call for. While
+// an order is in flight the rules keep seeing ticks, but nothing is submitted: a close signal of a position being
+// opened is kept and acted on once the position is filled.
+func (r *runner) handleTick(ctx context.Context, s *Service, acc *Account, tick signal.Tick) {
+	strategy := r.strategy
+	open := r.marketOpen(s, tick)
+	busy := r.orders.inFlight()
+	atr := r.trackATR(tick)
+
+	if r.contractID() == 0 && !busy {
+		// Outside of the schedule the open rule isn't consulted. Within it the rule sees every tick to keep its
+		// state current, even when no position can be opened.
+		if !r.inSchedule(tick) || !strategy.CheckToOpen(tick) || !r.canOpen() || !open || tick.Time.Before(r.retryAt) {
+			return
+		}
+
+		r.publishSignal(s, signalOpen, tick)
+		r.openPosition(ctx, s, acc, tick)
+
+		return
+	}
+
+	pos := r.heldPosition()
+	if pos != nil {
+		unrealizedPnL.WithLabelValues(strategy.Name).Set(pos.PnL(tick.Quote))
+	}
+
+	ruleClose := strategy.CheckToClose(tick)
+	exit := r.checkExit(tick, atr)
+
+	switch {
+	case r.closePending:
+	case ruleClose:
+		r.publishSignal(s, signalClose, tick)
+
+		r.closePending = true
+	case exit != "":
+		slog.Info("Exit triggered", slog.String("strategy", strategy.Name), slog.String("exit", exit),
+			slog.Float64("quote", tick.Quote))
+		r.publishSignal(s, exit, tick)
+
+		r.closePending = true
+	case pos != nil && r.heldTooLong(pos, time.Now()):
+		slog.Info("Max holding time reached, closing position", slog.String("strategy", strategy.Name),
+			slog.Duration("max_holding", strategy.Schedule.MaxHolding))
+		r.publishSignal(s, exitMaxHolding, tick)
+
+		r.closePending = true
+	}
+
+	r.submitPendingClose(ctx, s, tick)
+}
+
+// trackATR adds tick to the average true range used by an ATR trailing stop and returns the current value.
+// Returns zero when the strategy has no ATR trailing stop.
+func (r *runner) trackATR(tick signal.Tick) float64 {
+	ts := r.strategy.Exit.TrailingStop
+	if ts.Mode != TrailingATR {
+		r.atr = nil
+		return 0
+	}
+
+	if r.atr == nil || r.atr.period != ts.ATRPeriod {
+		r.atr = newTickATR(ts.ATRPeriod)
+	}
+
+	return r.atr.add(tick.Quote)
+}
+
+// checkExit updates the exits tracked for the open position with tick and returns the one triggered, if any.
+func (r *runner) checkExit(tick signal.Tick, atr float64) string {
+	if r.exit == nil {
+		return ""
+	}
+
+	exit := r.exit.update(tick.Quote, atr)
+
+	if r.exit.stopKind != "" {
+		r.setStopPrice(r.exit.stop)
+	}
+
+	return exit
+}
+
+func (r *runner) setStopPrice(stop float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.position != nil {
+		r.position.StopPrice = stop
+	}
+}
+
+// submitPendingClose submits the close of the open position once the close rule fired, unless an order is in flight
+// or a failed close is waiting for its retry delay.
+func (r *runner) submitPendingClose(ctx context.Context, s *Service, tick signal.Tick) {
+	if !r.closePending || tick.Time.Before(r.retryAt) {
+		return
+	}
+
+	// The only errors are a flat strategy and an order in flight, a pending close is retried on later ticks.
+	_ = r.submitClose(ctx, s, tick)
+}
+
+// handleOrder completes the order that left flight with res. Failed orders submitted by the rules or the stale
+// data policy are handled by react, a pending close of a freshly filled position is submitted right away.
+// Returns an error if the strategy has to stop.
+func (r *runner) handleOrder(ctx context.Context, s *Service, acc *Account, res orderResult) error {
+	fromRule := res.operation == signalOpen || r.closePending
+
+	err := r.completeOrder(s, acc, res)
+
+	// A close signal fired while the rejected position was being opened has nothing left to close.
+	if err != nil && res.operation == signalOpen {
+		r.closePending = false
+	}
+
+	if fromRule {
+		if err := r.react(s, res.tick, err); err != nil {
+			return err
+		}
+	}
+
+	if err == nil && res.operation == signalOpen {
+		r.submitPendingClose(ctx, s, res.tick)
+	}
+
+	return nil
+}
+
+// serveClose submits the close of the open position requested through the control interfaces, reply receives the
+// outcome once the close completes. A request arriving while the position is being closed waits for that close.
+func (r *runner) serveClose(ctx context.Context, s *Service, reply chan error) {
+	if r.orders.closing() {
+		r.closeWaiters = append(r.closeWaiters, reply)
+		return
+	}
+
+	err := r.submitClose(ctx, s, signal.Tick{Time: time.Now()})
+
+	switch {
+	case errors.Is(err, errOrderInFlight):
+		reply <- fmt.Errorf("%w: strategy %s is opening a position", ErrInvalidState, r.strategy.Name)
+	case err != nil:
+		reply <- err
+	default:
+		r.closeWaiters = append(r.closeWaiters, reply)
+	}
+}
+
+// drainOrder waits for the order in flight, if any, and completes it, so its outcome is known to the strategy
+// before the trading loop exits or the shutdown policy is applied.
+func (r *runner) drainOrder(s *Service, acc *Account) {
+	if !r.orders.inFlight() {
+		return
+	}
+
+	if err := r.completeOrder(s, acc, <-r.orders.results); err != nil {
+		slog.Warn("Order in flight failed", slog.String("strategy", r.strategy.Name), slog.Any("error", err))
+	}
+}
+
+// react handles a failed order according to the class of err. A failed close is retried on later ticks
+// until the position is closed. Returns err if the strategy has to stop, and nil if it keeps running.
+func (r *runner) react(s *Service, tick signal.Tick, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	kind, action := classify(err)
+	logger := slog.With(slog.String("strategy", r.strategy.Name), slog.String("kind", kind), slog.Any("error", err))
+
+	delay := orderRetryDelay
+	if kind == "rate_limited" {
+		delay = rateLimitDelay
+	}
+
+	switch action {
+	case actionStop:
+		return err
+	case actionPause:
+		riskBreaches.WithLabelValues(r.strategy.Name, kind).Inc()
+
+		if pauseErr := r.setPaused(true, "order failed: "+kind); pauseErr == nil {
+			logger.Warn("Strategy paused after failed order, resume it once the cause is fixed")
+		}
+	case actionRetry, actionSkip:
+		logger.Warn("Order failed, strategy keeps running", slog.String("action", string(action)), slog.Duration("delay", delay))
+	}
+
+	r.retryAt = tick.Time.Add(delay)
+
+	return nil
+}
+
+// marketOpen reports whether the market of the strategy symbol is open at the tick time and logs changes.
+// It is only called from the trading loop.
+func (r *runner) marketOpen(s *Service, tick signal.Tick) bool {
+	if s.marketHours == nil {
+		return true
+	}
+
+	open := s.marketHours.IsOpen(r.strategy.Symbol, tick.Time)
+
+	if open == r.marketClosed {
+		r.marketClosed = !open
+
+		if open {
+			slog.Info("Market reopened", slog.String("strategy", r.strategy.Name), slog.String("symbol", r.strategy.Symbol))
+		} else {
+			slog.Info("Market closed, new positions are suspended",
+				slog.String("strategy", r.strategy.Name), slog.String("symbol", r.strategy.Symbol))
+		}
+	}
+
+	return open
+}
+
+// openPosition submits the order opening a position of the strategy at tick, its outcome is handled by completeOrder.
+func (r *runner) openPosition(ctx context.Context, s *Service, acc *Account, tick signal.Tick) {
+	strategy := r.strategy
+
+	pos := Position{
+		Symbol:   strategy.Symbol,
+		Amount:   strategy.Amount,
+		Leverage: strategy.Leverage,
+		Price:    tick.Quote,
+		Currency: acc.Currency,
+	}
+
+	r.orders.submitOpen(ctx, tick, func(ctx context.Context) (int, error) {
+		return r.placeOrder(ctx, s, pos)
+	})
+}
+
+// submitClose submits the order closing the open position, its outcome is handled by completeOrder.
+// Returns an error wrapping ErrNoOpenPosition if the strategy is flat and errOrderInFlight if another order is
+// in flight.
+func (r *runner) submitClose(ctx context.Context, s *Service, tick signal.Tick) error {
+	pos := r.heldPosition()
+	if pos == nil {
+		return fmt.Errorf("%w: strategy %s", ErrNoOpenPosition, r.strategy.Name)
+	}
+
+	if !r.orders.submitClose(ctx, tick, pos.ContractID, func(ctx context.Context) (float64, error) {
+		return r.sellContract(ctx, s, pos)
+	}) {
+		return errOrderInFlight
+	}
+
+	return nil
+}
+
+// closePosition closes the open position and waits for the outcome. An order in flight is completed first.
+func (r *runner) closePosition(ctx context.Context, s *Service, acc *Account) error {
+	r.drainOrder(s, acc)
+
+	if err := r.submitClose(ctx, s, signal.Tick{Time: time.Now()}); err != nil {
+		return err
+	}
+
+	return r.completeOrder(s, acc, <-r.orders.results)
+}
+
+// completeOrder records the outcome of an order in the order manager and the strategy, and replies to the close
+// requests waiting for it. Returns the error of the order.
+func (r *runner) completeOrder(s *Service, acc *Account, res orderResult) error {
+	r.orders.finish(res)
+
+	if res.operation == signalOpen {
+		return r.completeOpen(s, acc, res)
+	}
+
+	err := r.completeClose(s, acc, res)
+
+	for _, reply := range r.closeWaiters {
+		reply <- err
+	}
+
+	r.closeWaiters = nil
+
+	return err
+}
+
+func (r *runner) completeOpen(s *Service, acc *Account, res orderResult) error {
+	strategy := r.strategy
+
+	if res.err != nil {
+		r.publishOrderFailed(s, strategy.Type.String(), res.err)
+
+		return fmt.Errorf("failed to open position for account %s: %w", acc.ID, res.err)
+	}
+
+	r.setPosition(&OpenPosition{
+		ContractID: res.contractID,
+		Strategy:   strategy.Name,
+		Symbol:     strategy.Symbol,
+		Type:       strategy.Type,
+		Amount:     strategy.Amount,
+		Leverage:   strategy.Leverage,
+		Price:      res.tick.Quote,
+		OpenedAt:   time.Now(),
+	})
+	s.releaseClaim(res.contractID)
+
+	openPositions.WithLabelValues(strategy.Name).Set(1)
+	r.countTrade(time.Now())
+
+	r.exit = newExitTracker(strategy.Exit, strategy.Type, res.tick.Quote)
+
+	s.events.Publish(event.New(event.TypeOrderPlaced, event.OrderPlaced{
+		Strategy:   strategy.Name,
+		Symbol:     strategy.Symbol,
+		Side:       strategy.Type.String(),
+		Amount:     strategy.Amount,
+		Price:      res.tick.Quote,
+		Leverage:   strategy.Leverage,
+		ContractID: res.contractID,
+	}))
+
+	return nil
+}
+
+func (r *runner) completeClose(s *Service, acc *Account, res orderResult) error {
+	pos := r.heldPosition()
+
+	if res.err != nil {
+		r.publishOrderFailed(s, signalClose, res.err)
+
+		return fmt.Errorf("failed to close position for account %s contract ID %d: %w", acc.ID, res.contractID, res.err)
+	}
+
+	r.setPosition(nil)
+	r.closePending = false
+	r.exit = nil
+	r.startCooldown(time.Now())
+
+	name := r.strategy.Name
+	profit := res.soldFor - pos.Amount
+
+	s.recordTrade(ClosedTrade{
+		OpenedAt:   pos.OpenedAt,
+		ClosedAt:   time.Now(),
+		Strategy:   name,
+		Symbol:     pos.Symbol,
+		Type:       pos.Type,
+		Amount:     pos.Amount,
+		Price:      pos.Price,
+		Leverage:   pos.Leverage,
+		Profit:     profit,
+		ContractID: pos.ContractID,
+	})
+
+	realizedPnL.WithLabelValues(name).Add(profit)
+	unrealizedPnL.WithLabelValues(name).Set(0)
+	openPositions.WithLabelValues(name).Set(0)
+
+	s.events.Publish(event.New(event.TypePositionClosed, event.PositionClosed{
+		Strategy:   name,
+		Symbol:     pos.Symbol,
+		Profit:     profit,
+		ContractID: pos.ContractID,
+	}))
+
+	return nil
+}
+
+func (r *runner) publishSignal(s *Service, sig string, tick signal.Tick) {
+	s.events.Publish(event.New(event.TypeSignalFired, event.SignalFired{
+		Strategy: r.strategy.Name,
+		Symbol:   r.strategy.Symbol,
+		Signal:   sig,
+		Quote:    tick.Quote,
+	}))
+}
+
+func (r *runner) publishOrderFailed(s *Service, operation string, err error) {
+	kind, action := classify(err)
+
+	s.events.Publish(event.New(event.TypeOrderFailed, event.OrderFailed{
+		Strategy:  r.strategy.Name,
+		Symbol:    r.strategy.Symbol,
+		Operation: operation,
+		Error:     err.Error(),
+		Kind:      kind,
+		Action:    string(action),
+	}))
+}
@@ -0,0 +1,98 @@
+package executor
+
+import (
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+)
+
+// exitMaxHolding is the signal of a close forced by the max holding time of the schedule.
+const exitMaxHolding = "max_holding"
+
+// DayWindow is a period of the day, as offsets from midnight. A window with To before From spans midnight.
+type DayWindow struct {
+	From time.Duration
+	To   time.Duration
+}
+
+// Window is a period between two points in time.
+type Window struct {
+	From time.Time
+	To   time.Time
+}
+
+// Schedule limits when a strategy opens positions: on Days, within one of Hours, both in Location, and outside
+// of Blackouts. Empty Days or Hours allow every day or the whole day. A position held for MaxHolding is closed,
+// a zero MaxHolding keeps it until the rules close it.
+type Schedule struct {
+	Location   *time.Location
+	Days       []time.Weekday
+	Hours      []DayWindow
+	Blackouts  []Window
+	MaxHolding time.Duration
+}
+
+// allows reports whether the schedule lets the strategy open a position at t.
+func (s Schedule) allows(t time.Time) bool {
+	for _, b := range s.Blackouts {
+		if !t.Before(b.From) && t.Before(b.To) {
+			return false
+		}
+	}
+
+	if s.Location != nil {
+		t = t.In(s.Location)
+	}
+
+	if len(s.Days) > 0 && !slices.Contains(s.Days, t.Weekday()) {
+		return false
+	}
+
+	if len(s.Hours) == 0 {
+		return true
+	}
+
+	// The offset is taken from the wall clock, so windows keep their local times across DST changes.
+	h, m, sec := t.Clock()
+	offset := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(sec)*time.Second
+
+	for _, w := range s.Hours {
+		inside := offset >= w.From && offset < w.To
+		if w.To < w.From {
+			inside = offset >= w.From || offset < w.To
+		}
+
+		if inside {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inSchedule reports whether the schedule of the strategy allows opening a position at the tick time and logs
+// changes. It is only called from the trading loop.
+func (r *runner) inSchedule(tick signal.Tick) bool {
+	allowed := r.strategy.Schedule.allows(tick.Time)
+
+	if allowed == r.outOfSchedule {
+		r.outOfSchedule = !allowed
+
+		if allowed {
+			slog.Info("Trading window opened", slog.String("strategy", r.strategy.Name))
+		} else {
+			slog.Info("Outside of the trading schedule, new positions are suspended", slog.String("strategy", r.strategy.Name))
+		}
+	}
+
+	return allowed
+}
+
+// heldTooLong reports whether the open position reached the max holding time of the schedule at now.
+func (r *runner) heldTooLong(pos *OpenPosition, now time.Time) bool {
+	limit := r.strategy.Schedule.MaxHolding
+
+	return limit > 0 && now.Sub(pos.OpenedAt) >= limit
+}
@@ -0,0 +1,110 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Allows(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+
+	schedule := Schedule{
+		Location: london,
+		Days:     []time.Weekday{time.Monday, time.Tuesday},
+		Hours: []DayWindow{
+			{From: 8 * time.Hour, To: 12 * time.Hour},
+			{From: 22 * time.Hour, To: 2 * time.Hour},
+		},
+		Blackouts: []Window{{
+			From: time.Date(2026, 10, 19, 10, 0, 0, 0, london),
+			To:   time.Date(2026, 10, 19, 11, 0, 0, 0, london),
+		}},
+	}
+
+	tests := []struct {
+		at   time.Time
+		name string
+		want bool
+	}{
+		{name: "within hours in the schedule timezone", at: time.Date(2026, 10, 20, 7, 30, 0, 0, time.UTC), want: true},
+		{name: "before hours", at: time.Date(2026, 10, 20, 6, 30, 0, 0, time.UTC)},
+		{name: "end of the window is excluded", at: time.Date(2026, 10, 20, 12, 0, 0, 0, london)},
+		{name: "window spanning midnight", at: time.Date(2026, 10, 20, 1, 0, 0, 0, london), want: true},
+		{name: "day not allowed", at: time.Date(2026, 10, 21, 9, 0, 0, 0, london)},
+		{name: "blackout", at: time.Date(2026, 10, 19, 10, 30, 0, 0, london)},
+		{name: "after blackout", at: time.Date(2026, 10, 19, 11, 0, 0, 0, london), want: true},
+		{name: "winter time keeps local hours", at: time.Date(2026, 11, 2, 8, 0, 0, 0, time.UTC), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, schedule.allows(tt.at))
+		})
+	}
+
+	assert.True(t, Schedule{}.allows(time.Now()))
+}
+
+func TestService_Schedule(t *testing.T) {
+	market := &fakeMarket{ticks: make(chan signal.Tick)}
+	trading := &fakeTrading{}
+	bus := event.NewBus()
+	svc := New(market, trading, bus)
+
+	signals, unsubscribe := bus.Subscribe(event.TypeSignalFired)
+	defer unsubscribe()
+
+	var consulted []float64
+
+	require.NoError(t, svc.AddStrategy(Strategy{
+		Name:     "test",
+		Symbol:   "R_100",
+		Type:     StrategyTypeBuy,
+		Amount:   10,
+		Leverage: 100,
+		Schedule: Schedule{
+			Hours:      []DayWindow{{From: 9 * time.Hour, To: 17 * time.Hour}},
+			MaxHolding: 50 * time.Millisecond,
+		},
+		CheckToOpen: func(tick signal.Tick) bool {
+			consulted = append(consulted, tick.Quote)
+			return true
+		},
+		CheckToClose: func(signal.Tick) bool { return false },
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- svc.Run(ctx) }()
+
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+
+	// The second send returns only after the loop has finished handling the first tick.
+	market.ticks <- signal.Tick{Time: day.Add(8 * time.Hour), Quote: 100}
+	market.ticks <- signal.Tick{Time: day.Add(17 * time.Hour), Quote: 101}
+	assert.Empty(t, svc.Positions())
+
+	market.ticks <- signal.Tick{Time: day.Add(9 * time.Hour), Quote: 102}
+	require.Eventually(t, func() bool { return len(svc.Positions()) == 1 }, time.Second, 5*time.Millisecond)
+
+	time.Sleep(50 * time.Millisecond)
+
+	market.ticks <- signal.Tick{Time: day.Add(10 * time.Hour), Quote: 103}
+	require.Eventually(t, func() bool { return len(svc.Positions()) == 0 }, time.Second, 5*time.Millisecond)
+
+	assert.Equal(t, signalOpen, (<-signals).Payload.(event.SignalFired).Signal)
+	assert.Equal(t, exitMaxHolding, (<-signals).Payload.(event.SignalFired).Signal)
+
+	cancel()
+	assert.NoError(t, <-done)
+
+	assert.Equal(t, []float64{102}, consulted)
+}
@@ -0,0 +1,229 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+)
+
+// shutdownGrace bounds the time given to trading operations of the shutdown policies,
+// on top of the wait timeout of the strategies.
+const shutdownGrace = 30 * time.Second
+
+// ShutdownAction is the outcome of a shutdown policy for a strategy position.
+type ShutdownAction string
+
+const (
+	ShutdownActionFlat      ShutdownAction = "flat"
+	ShutdownActionLeft      ShutdownAction = "left"
+	ShutdownActionClosed    ShutdownAction = "closed"
+	ShutdownActionProtected ShutdownAction = "protected"
+	ShutdownActionFailed    ShutdownAction = "failed"
+)
+
+// ShutdownResult describes what happened with a strategy position during shutdown.
+type ShutdownResult struct {
+	Err        error
+	Strategy   string
+	Action     ShutdownAction
+	ContractID int
+}
+
+// requestShutdown signals a running strategy to apply its shutdown policy and exit.
+// Returns the shutdown policy of the strategy, and false if the strategy is not running.
+func (r *runner) requestShutdown() (ShutdownPolicy, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.shutdownCh == nil || !r.state.active() {
+		return ShutdownPolicy{}, false
+	}
+
+	close(r.shutdownCh)
+	r.shutdownCh = nil
+
+	return r.strategy.Shutdown, true
+}
+
+// shutdown applies the shutdown policy of the strategy to its open position and records the result.
+func (r *runner) shutdown(ctx context.Context, s *Service, acc *Account, tickChan <-chan signal.Tick) {
+	// The policy applies to the position as it is once the order in flight, if any, completed.
+	r.drainOrder(s, acc)
+
+	res := ShutdownResult{Strategy: r.strategy.Name, Action: ShutdownActionFlat}
+
+	if pos := r.heldPosition(); pos != nil {
+		res.ContractID = pos.ContractID
+		res.Action, res.Err = r.applyShutdownPolicy(ctx, s, acc, tickChan, r.strategy.Shutdown.Mode)
+	}
+
+	r.mu.Lock()
+	r.shutdownResult = &res
+	r.mu.Unlock()
+}
+
+func (r *runner) applyShutdownPolicy(
+	ctx context.Context,
+	s *Service,
+	acc *Account,
+	tickChan <-chan signal.Tick,
+	mode ShutdownMode,
+) (ShutdownAction, error) {
+	policy := r.strategy.Shutdown
+
+	switch mode {
+	case ShutdownClose:
+		if err := r.closePosition(ctx, s, acc); err != nil {
+			return ShutdownActionFailed, err
+		}
+
+		return ShutdownActionClosed, nil
+	case ShutdownProtect:
+		if err := r.tradingSession().SetLimits(ctx, r.contractID(), policy.TakeProfit, policy.StopLoss); err != nil {
+			return ShutdownActionFailed, err
+		}
+
+		return ShutdownActionProtected, nil
+	case ShutdownWait:
+		return r.waitForClose(ctx, s, acc, tickChan)
+	case ShutdownLeave, "":
+		return ShutdownActionLeft, nil
+	default:
+		return ShutdownActionFailed, fmt.Errorf("unknown shutdown mode %q", mode)
+	}
+}
+
+// waitForClose keeps evaluating the close rule on incoming ticks until the position is closed
+// or the policy timeout expires, in which case the fallback mode is applied.
+func (r *runner) waitForClose(
+	ctx context.Context,
+	s *Service,
+	acc *Account,
+	tickChan <-chan signal.Tick,
+) (ShutdownAction, error) {
+	policy := r.strategy.Shutdown
+
+	fallback := policy.Fallback
+	if fallback == "" || fallback == ShutdownWait {
+		fallback = ShutdownClose
+	}
+
+	timer := time.NewTimer(policy.Timeout)
+	defer timer.Stop()
+
+	slog.Info("Waiting for close rule before shutdown",
+		slog.String("strategy", r.strategy.Name),
+		slog.Duration("timeout", policy.Timeout),
+		slog.String("fallback", string(fallback)),
+	)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ShutdownActionFailed, ctx.Err()
+		case <-timer.C:
+			return r.applyShutdownPolicy(ctx, s, acc, tickChan, fallback)
+		case tick, ok := <-tickChan:
+			if !ok {
+				return r.applyShutdownPolicy(ctx, s, acc, tickChan, fallback)
+			}
+
+			if !r.strategy.CheckToClose(tick) {
+				continue
+			}
+
+			r.publishSignal(s, signalClose, tick)
+
+			if err := r.closePosition(ctx, s, acc); err != nil {
+				return ShutdownActionFailed, err
+			}
+
+			return ShutdownActionClosed, nil
+		}
+	}
+}
+
+// shutdown asks all running strategies to apply their shutdown policies and waits for them to finish.
+// If strategies don't finish within the wait timeout plus a grace period, their context is cancelled.
+// A summary of the actions taken is logged at the end.
+func (s *Service) shutdown(cancel context.CancelFunc) {
+	s.mu.Lock()
+
+	runners := make([]*runner, 0, len(s.names))
+	for _, name := range s.names {
+		runners = append(runners, s.runners[name])
+	}
+
+	s.mu.Unlock()
+
+	deadline := shutdownGrace
+
+	for _, r := range runners {
+		if policy, ok := r.requestShutdown(); ok && policy.Mode == ShutdownWait {
+			deadline = max(deadline, policy.Timeout+shutdownGrace)
+		}
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(deadline):
+		slog.Warn("Shutdown policies didn't finish in time, cancelling strategies", slog.Duration("deadline", deadline))
+		cancel()
+		<-done
+	}
+
+	cancel()
+
+	logShutdownSummary(runners)
+}
+
+func logShutdownSummary(runners []*runner) {
+	counts := make(map[ShutdownAction]int)
+
+	for _, r := range runners {
+		r.mu.Lock()
+		res := r.shutdownResult
+		r.mu.Unlock()
+
+		if res == nil {
+			continue
+		}
+
+		counts[res.Action]++
+
+		if res.Action == ShutdownActionFlat {
+			continue
+		}
+
+		attrs := []any{
+			slog.String("strategy", res.Strategy),
+			slog.String("action", string(res.Action)),
+			slog.Int("contract_id", res.ContractID),
+		}
+
+		if res.Err != nil {
+			slog.Error("Shutdown policy failed", append(attrs, slog.Any("error", res.Err))...)
+			continue
+		}
+
+		slog.Info("Shutdown policy applied", attrs...)
+	}
+
+	slog.Info("Shutdown summary",
+		slog.Int("flat", counts[ShutdownActionFlat]),
+		slog.Int("closed", counts[ShutdownActionClosed]),
+		slog.Int("protected", counts[ShutdownActionProtected]),
+		slog.Int("left", counts[ShutdownActionLeft]),
+		slog.Int("failed", counts[ShutdownActionFailed]),
+	)
+}
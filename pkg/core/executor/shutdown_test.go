@@ -0,0 +1,118 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_ShutdownPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        ShutdownPolicy
+		ticks         []float64
+		wantAction    ShutdownAction
+		wantClosed    bool
+		wantProtected bool
+	}{
+		{
+			name:       "leave",
+			policy:     ShutdownPolicy{Mode: ShutdownLeave},
+			wantAction: ShutdownActionLeft,
+		},
+		{
+			name:       "close",
+			policy:     ShutdownPolicy{Mode: ShutdownClose},
+			wantAction: ShutdownActionClosed,
+			wantClosed: true,
+		},
+		{
+			name:          "protect",
+			policy:        ShutdownPolicy{Mode: ShutdownProtect, TakeProfit: 5, StopLoss: 3},
+			wantAction:    ShutdownActionProtected,
+			wantProtected: true,
+		},
+		{
+			name:       "wait for close rule",
+			policy:     ShutdownPolicy{Mode: ShutdownWait, Fallback: ShutdownProtect, Timeout: time.Minute},
+			ticks:      []float64{100, 105},
+			wantAction: ShutdownActionClosed,
+			wantClosed: true,
+		},
+		{
+			name:          "wait timeout falls back",
+			policy:        ShutdownPolicy{Mode: ShutdownWait, Fallback: ShutdownProtect, Timeout: 10 * time.Millisecond},
+			wantAction:    ShutdownActionProtected,
+			wantProtected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			market := &fakeMarket{ticks: make(chan signal.Tick)}
+			trading := &fakeTrading{}
+			svc := New(market, trading, event.NewBus())
+
+			require.NoError(t, svc.AddStrategy(Strategy{
+				Name:         "test",
+				Symbol:       "R_100",
+				Type:         StrategyTypeBuy,
+				Amount:       10,
+				Leverage:     10,
+				Shutdown:     tt.policy,
+				CheckToOpen:  func(signal.Tick) bool { return true },
+				CheckToClose: func(tick signal.Tick) bool { return tick.Quote > 104 },
+			}))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+
+			go func() { done <- svc.Run(ctx) }()
+
+			market.ticks <- signal.Tick{Quote: 100}
+
+			require.Eventually(t, func() bool { return len(svc.Positions()) == 1 }, time.Second, 10*time.Millisecond)
+
+			contractID := svc.Positions()[0].ContractID
+
+			cancel()
+
+			r := svc.runners["test"]
+
+			require.Eventually(t, func() bool {
+				r.mu.Lock()
+				defer r.mu.Unlock()
+
+				return r.shutdownCh == nil
+			}, time.Second, time.Millisecond)
+
+			for _, quote := range tt.ticks {
+				market.ticks <- signal.Tick{Quote: quote}
+			}
+
+			require.NoError(t, <-done)
+
+			res := r.shutdownResult
+			require.NotNil(t, res)
+			assert.Equal(t, tt.wantAction, res.Action)
+			assert.NoError(t, res.Err)
+
+			if tt.wantClosed {
+				assert.Equal(t, []int{contractID}, trading.closed)
+			} else {
+				assert.Empty(t, trading.closed)
+			}
+
+			if tt.wantProtected {
+				assert.Equal(t, []int{contractID}, trading.protected)
+			} else {
+				assert.Empty(t, trading.protected)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package executor
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+)
+
+// staleWatch fires on C when the strategy received no tick for the threshold of its stale data policy.
+// It is only used by the trading loop.
+type staleWatch struct {
+	timer *time.Timer
+	C     <-chan time.Time
+}
+
+// reset restarts the countdown with after, a zero after disables the watch.
+func (w *staleWatch) reset(after time.Duration) {
+	switch {
+	case after <= 0:
+		w.stop()
+	case w.timer == nil:
+		w.timer = time.NewTimer(after)
+		w.C = w.timer.C
+	default:
+		w.timer.Reset(after)
+	}
+}
+
+func (w *staleWatch) stop() {
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+
+	w.timer, w.C = nil, nil
+}
+
+// handleStale applies the stale data policy of the strategy when its tick stream stalled.
+// The close action submits the close of the open position, a failed close is retried on later ticks like one
+// fired by the close rule.
+func (r *runner) handleStale(ctx context.Context, s *Service) {
+	policy := r.strategy.StaleData
+	logger := slog.With(slog.String("strategy", r.strategy.Name), slog.String("symbol", r.strategy.Symbol),
+		slog.Duration("after", policy.After), slog.String("action", string(policy.Action)))
+
+	logger.Warn("No ticks received, market data is stale")
+
+	switch policy.Action {
+	case StaleResubscribe:
+		if err := s.marketSignals.Resubscribe(ctx, r.strategy.Symbol); err != nil {
+			logger.Error("Failed to resubscribe to market data", slog.Any("error", err))
+		}
+	case StalePause:
+		if err := r.setPaused(true, "stale market data"); err == nil {
+			logger.Warn("Strategy paused on stale market data, resume it once data is back")
+		}
+	case StaleClose:
+		if r.contractID() == 0 {
+			return
+		}
+
+		r.closePending = true
+
+		_ = r.submitClose(ctx, s, signal.Tick{Time: time.Now()})
+	}
+}
@@ -0,0 +1,86 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_StaleData(t *testing.T) {
+	tests := []struct {
+		check  func(t *testing.T, svc *Service, market *fakeMarket, trading *fakeTrading)
+		name   string
+		action StaleAction
+	}{
+		{
+			name:   "resubscribe",
+			action: StaleResubscribe,
+			check: func(t *testing.T, _ *Service, market *fakeMarket, _ *fakeTrading) {
+				assert.Eventually(t, func() bool {
+					market.mu.Lock()
+					defer market.mu.Unlock()
+
+					return market.resubscribed >= 2
+				}, time.Second, 5*time.Millisecond, "resubscribes after every stale period")
+			},
+		},
+		{
+			name:   "pause",
+			action: StalePause,
+			check: func(t *testing.T, svc *Service, _ *fakeMarket, _ *fakeTrading) {
+				assert.Eventually(t, func() bool {
+					st, err := svc.Strategy("test")
+					return err == nil && st.State == StrategyStatePaused
+				}, time.Second, 5*time.Millisecond)
+			},
+		},
+		{
+			name:   "close",
+			action: StaleClose,
+			check: func(t *testing.T, _ *Service, _ *fakeMarket, trading *fakeTrading) {
+				assert.Eventually(t, func() bool {
+					trading.mu.Lock()
+					defer trading.mu.Unlock()
+
+					return len(trading.closed) == 1
+				}, time.Second, 5*time.Millisecond)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			market := &fakeMarket{ticks: make(chan signal.Tick)}
+			trading := &fakeTrading{}
+			svc := New(market, trading, event.NewBus())
+
+			require.NoError(t, svc.AddStrategy(Strategy{
+				Name:         "test",
+				Symbol:       "R_100",
+				Type:         StrategyTypeBuy,
+				Amount:       10,
+				Leverage:     100,
+				StaleData:    StaleDataPolicy{Action: tt.action, After: 30 * time.Millisecond},
+				CheckToOpen:  func(tick signal.Tick) bool { return tick.Quote == 100 },
+				CheckToClose: func(signal.Tick) bool { return false },
+			}))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+
+			go func() { done <- svc.Run(ctx) }()
+
+			market.ticks <- signal.Tick{Time: time.Now(), Quote: 100}
+
+			tt.check(t, svc, market, trading)
+
+			cancel()
+			assert.NoError(t, <-done)
+		})
+	}
+}
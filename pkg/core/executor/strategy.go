@@ -1,6 +1,10 @@
 package executor
 
-import "github.com/ksysoev/deriv-bot/pkg/core/signal"
+import (
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+)
 
 type StrategyType int
 
@@ -10,12 +14,143 @@ const (
 	StrategyTypeSell
 )
 
+// String returns the lower case name of the strategy type.
+func (t StrategyType) String() string {
+	switch t {
+	case StrategyTypeBuy:
+		return "buy"
+	case StrategyTypeSell:
+		return "sell"
+	case StrategyTypeNotSet:
+		return "not_set"
+	default:
+		return "unknown"
+	}
+}
+
+// ContractType returns the type of the multiplier contracts opened by strategies of type t, empty if t is unknown.
+func (t StrategyType) ContractType() string {
+	switch t {
+	case StrategyTypeBuy:
+		return ContractTypeUp
+	case StrategyTypeSell:
+		return ContractTypeDown
+	case StrategyTypeNotSet:
+		return ""
+	default:
+		return ""
+	}
+}
+
+// StrategyState is a step in the lifecycle of a strategy. A registered strategy is idle until it's started.
+// A started strategy warms up until its rule has seen enough ticks, then waits to open a position, holds it
+// and cools down after closing it, before waiting to open again. It can be paused at any of these steps,
+// and ends up stopped, or errored when it fails.
+type StrategyState string
+
+const (
+	StrategyStateIdle          StrategyState = "idle"
+	StrategyStateWarmingUp     StrategyState = "warming_up"
+	StrategyStateWaitingToOpen StrategyState = "waiting_to_open"
+	StrategyStateInPosition    StrategyState = "in_position"
+	StrategyStateCoolingDown   StrategyState = "cooling_down"
+	StrategyStatePaused        StrategyState = "paused"
+	StrategyStateStopped       StrategyState = "stopped"
+	StrategyStateErrored       StrategyState = "errored"
+)
+
+// active reports whether the trading loop of a strategy in the state is running.
+func (s StrategyState) active() bool {
+	switch s {
+	case StrategyStateWarmingUp, StrategyStateWaitingToOpen, StrategyStateInPosition, StrategyStateCoolingDown,
+		StrategyStatePaused:
+		return true
+	case StrategyStateIdle, StrategyStateStopped, StrategyStateErrored:
+		return false
+	default:
+		return false
+	}
+}
+
+type ShutdownMode string
+
+const (
+	// ShutdownLeave leaves an open position untouched and unmanaged.
+	ShutdownLeave ShutdownMode = "leave"
+	// ShutdownClose closes an open position at market price.
+	ShutdownClose ShutdownMode = "close"
+	// ShutdownProtect attaches native take profit and stop loss orders to an open position and leaves it.
+	ShutdownProtect ShutdownMode = "protect"
+	// ShutdownWait keeps evaluating the close rule until the position is closed or the timeout expires,
+	// then applies the fallback mode.
+	ShutdownWait ShutdownMode = "wait"
+)
+
+// ShutdownPolicy defines what happens with an open position when the bot shuts down.
+// TakeProfit and StopLoss are amounts in account currency used by the protect mode.
+type ShutdownPolicy struct {
+	Mode       ShutdownMode
+	Fallback   ShutdownMode
+	Timeout    time.Duration
+	TakeProfit float64
+	StopLoss   float64
+}
+
+// StaleAction is the reaction of a strategy to a tick stream without ticks for the stale threshold.
+type StaleAction string
+
+const (
+	// StaleResubscribe replaces the market subscription of the symbol.
+	StaleResubscribe StaleAction = "resubscribe"
+	// StalePause pauses the strategy, so it opens no new position until it's resumed.
+	StalePause StaleAction = "pause"
+	// StaleClose closes the open position at market price.
+	StaleClose StaleAction = "close"
+)
+
+// StaleDataPolicy defines how a strategy reacts when no tick arrived for After. A zero After disables the check.
+// The action is repeated after every further period of After without ticks.
+type StaleDataPolicy struct {
+	Action StaleAction
+	After  time.Duration
+}
+
 type Strategy struct {
 	CheckToOpen  func(tick signal.Tick) bool
 	CheckToClose func(tick signal.Tick) bool
+	Shutdown     ShutdownPolicy
+	StaleData    StaleDataPolicy
+	Exit         ExitPolicy
+	Schedule     Schedule
+	Name         string
 	Token        string
 	Symbol       string
 	Amount       float64
 	Type         StrategyType
 	Leverage     float64
+	// WarmUp is the number of ticks the rule needs to see after the start before its open signals are acted on.
+	WarmUp int
+	// Cooldown is the time after closing a position during which no new position is opened.
+	Cooldown time.Duration
+	// MaxTradesPerDay limits the number of positions opened per UTC day, zero means no limit.
+	MaxTradesPerDay int
+	// ClampLimits replaces an amount or leverage not accepted for the symbol with the nearest accepted value,
+	// instead of refusing to start the strategy.
+	ClampLimits bool
+}
+
+// StrategyStatus is a point in time snapshot of a registered strategy.
+type StrategyStatus struct {
+	StateSince    time.Time
+	CooldownUntil time.Time
+	Position      *OpenPosition
+	Order         *Order
+	Name          string
+	Symbol        string
+	State         StrategyState
+	Error         string
+	Type          StrategyType
+	Amount        float64
+	Leverage      float64
+	TradesToday   int
 }
@@ -2,96 +2,490 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"sync"
+	"time"
 
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
 	"github.com/ksysoev/deriv-bot/pkg/core/signal"
 )
 
+// maxTradeHistory limits the number of closed trades kept in memory.
+const maxTradeHistory = 10_000
+
+var (
+	ErrStrategyNotFound = errors.New("strategy not found")
+	ErrStrategyExists   = errors.New("strategy already exists")
+	ErrInvalidState     = errors.New("invalid strategy state")
+	ErrNoOpenPosition   = errors.New("no open position")
+	ErrNotRunning       = errors.New("executor is not running")
+	ErrContractLimits   = errors.New("strategy parameters outside contract limits")
+)
+
 type MarketSignals interface {
 	SubscribeOnMarket(ctx context.Context, symbol string) (<-chan signal.Tick, error)
+	Resubscribe(ctx context.Context, symbol string) error
 }
 
 type TradingProvider interface {
 	Authorize(ctx context.Context, token string) (*Account, error)
 	Buy(ctx context.Context, pos Position) (int, error)
 	Sell(ctx context.Context, pos Position) (int, error)
-	ClosePosition(ctx context.Context, contractID int) error
+	ClosePosition(ctx context.Context, contractID int) (float64, error)
+	SetLimits(ctx context.Context, contractID int, takeProfit, stopLoss float64) error
+	Balance(ctx context.Context) (*Balance, error)
+	Portfolio(ctx context.Context) ([]Contract, error)
+	SoldContracts(ctx context.Context, since time.Time) ([]SoldContract, error)
+}
+
+// SessionProvider is implemented by trading providers which keep a separate session per account, so strategies
+// trading with different tokens never act on each other's account. Providers without it share one session,
+// authorized with the token of the strategy which started last.
+type SessionProvider interface {
+	Session(token string) (TradingProvider, error)
+}
+
+// MarketHours reports whether a symbol can be traded at a point in time.
+type MarketHours interface {
+	IsOpen(symbol string, t time.Time) bool
+}
+
+type Publisher interface {
+	Publish(e event.Event)
 }
 
 type Service struct {
 	marketSignals MarketSignals
 	tradingProv   TradingProvider
+	events        Publisher
+	marketHours   MarketHours
+	limits        ContractLimits
+	backoff       func(attempt int) time.Duration
+	ctx           context.Context
+	runCtx        context.Context
+	runners       map[string]*runner
+	claims        map[int]string
+	names         []string
+	trades        []ClosedTrade
+	wg            sync.WaitGroup
+	mu            sync.Mutex
 }
 
 // New creates and returns a new Service instance with the provided marketSignals and tradingProv dependencies.
 // marketSignals provides market data subscription capabilities.
 // tradingProv handles trading operations like buy and sell.
-func New(marketSignals MarketSignals, tradingProv TradingProvider) *Service {
+// events receives notifications about signals, orders and closed positions.
+func New(marketSignals MarketSignals, tradingProv TradingProvider, events Publisher) *Service {
 	return &Service{
 		marketSignals: marketSignals,
 		tradingProv:   tradingProv,
+		events:        events,
+		runners:       make(map[string]*runner),
+		claims:        make(map[int]string),
+		backoff:       retryBackoff,
+	}
+}
+
+// SetMarketHours makes strategies skip opening positions while the market of their symbol is closed.
+// Open positions are still managed. It must be called before Run.
+func (s *Service) SetMarketHours(hours MarketHours) {
+	s.marketHours = hours
+}
+
+// AddStrategy registers a strategy with the executor in the idle state.
+// Registered strategies are started by Run, or later by StartStrategy.
+// Returns ErrStrategyExists if a strategy with the same name is already registered.
+func (s *Service) AddStrategy(strategy Strategy) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.runners[strategy.Name]; ok {
+		return fmt.Errorf("%w: %s", ErrStrategyExists, strategy.Name)
 	}
+
+	s.runners[strategy.Name] = newRunner(strategy)
+	s.names = append(s.names, strategy.Name)
+
+	return nil
 }
 
-// ExecuteStrategy monitors market signals for a given symbol and executes a buy operation when the evaluation condition is met.
-// It subscribes to market signals and iterates through incoming ticks. If `eval` returns true for a tick, the service buys the symbol.
-// ctx is the context for managing the subscription and operation lifecycle.
-// symbol specifies the market symbol to trade, and amount is the quantity to buy.
-// eval is a callback function that evaluates tick data to decide when to trigger the buy action.
-// Returns the transaction ID of the buy operation and an error if subscribing to market signals or executing the buy fails.
-func (s *Service) ExecuteStrategy(ctx context.Context, stategy Strategy) error {
-	acc, err := s.tradingProv.Authorize(ctx, stategy.Token)
+// Run starts all registered strategies and blocks until ctx is cancelled.
+// Failed strategies are logged and kept in the errored state, so they can be inspected and restarted through
+// the control interfaces without stopping the process.
+// Strategies run detached from ctx cancellation, so on shutdown they can still trade while applying
+// their shutdown policies to open positions. The trading provider must stay connected until Run returns.
+// Returns nil after all strategies have stopped, and an error wrapping ErrContractLimits without starting any
+// strategy if the amount or leverage of a strategy isn't accepted for its symbol.
+func (s *Service) Run(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+
+	s.mu.Lock()
+
+	// Strategies are checked upfront, so none is started when the parameters of any are rejected.
+	var errs []error
+
+	for _, name := range s.names {
+		if err := s.runners[name].applyLimits(s); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		s.mu.Unlock()
+		return errors.Join(errs...)
+	}
+
+	s.ctx = ctx
+	s.runCtx = runCtx
+
+	for _, name := range s.names {
+		if err := s.runners[name].start(runCtx, s, &s.wg); err != nil {
+			s.mu.Unlock()
+			return err
+		}
+	}
+
+	s.mu.Unlock()
+
+	<-ctx.Done()
+
+	s.shutdown(cancel)
+
+	return nil
+}
+
+// Strategies returns the status of all registered strategies in registration order.
+func (s *Service) Strategies() []StrategyStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]StrategyStatus, 0, len(s.names))
+	for _, name := range s.names {
+		statuses = append(statuses, s.runners[name].status())
+	}
+
+	return statuses
+}
+
+// Strategy returns the status of the strategy with the given name.
+// Returns ErrStrategyNotFound if the strategy is not registered.
+func (s *Service) Strategy(name string) (StrategyStatus, error) {
+	r, err := s.runner(name)
 	if err != nil {
-		return fmt.Errorf("failed to authorize trading provider: %w", err)
+		return StrategyStatus{}, err
 	}
 
-	tickChan, err := s.marketSignals.SubscribeOnMarket(ctx, stategy.Symbol)
+	return r.status(), nil
+}
+
+// StartStrategy starts an idle, stopped or errored strategy.
+// Returns ErrNotRunning if the executor is not running, ErrStrategyNotFound if the strategy is not registered,
+// ErrInvalidState if the strategy is already active and ErrContractLimits if its amount or leverage isn't accepted.
+func (s *Service) StartStrategy(name string) error {
+	r, err := s.runner(name)
 	if err != nil {
 		return err
 	}
 
-	cid := 0
+	s.mu.Lock()
+	ctx, runCtx := s.ctx, s.runCtx
+	s.mu.Unlock()
 
-	for tick := range tickChan {
-		if cid == 0 && stategy.CheckToOpen(tick) {
-			var err error
+	if ctx == nil || ctx.Err() != nil {
+		return ErrNotRunning
+	}
 
-			pos := Position{
-				Symbol:   stategy.Symbol,
-				Amount:   stategy.Amount,
-				Leverage: stategy.Leverage,
-				Price:    tick.Quote,
-				Currency: acc.Currency,
-			}
+	return r.start(runCtx, s, &s.wg)
+}
 
-			switch stategy.Type {
-			case StrategyTypeBuy:
-				cid, err = s.tradingProv.Buy(ctx, pos)
-			case StrategyTypeSell:
-				cid, err = s.tradingProv.Sell(ctx, pos)
-			case StrategyTypeNotSet:
-				return fmt.Errorf("strategy type not set")
-			default:
-				return fmt.Errorf("unknown strategy type: %d", stategy.Type)
-			}
+// StopStrategy stops an active strategy and waits for its loop to exit.
+// An open position is left untouched and is no longer managed by the strategy.
+// Returns ErrStrategyNotFound if the strategy is not registered and ErrInvalidState if it is not active.
+func (s *Service) StopStrategy(name string) error {
+	r, err := s.runner(name)
+	if err != nil {
+		return err
+	}
 
-			if err != nil {
-				return fmt.Errorf("failed to open position for symbol %s: %w", acc.ID, err)
-			}
+	return r.stop()
+}
 
-			continue
+// PauseStrategy prevents an active strategy from opening new positions, an open position is still managed.
+// Returns ErrStrategyNotFound if the strategy is not registered and ErrInvalidState if it is not active or already paused.
+func (s *Service) PauseStrategy(name string) error {
+	r, err := s.runner(name)
+	if err != nil {
+		return err
+	}
+
+	return r.setPaused(true, "")
+}
+
+// ResumeStrategy allows a paused strategy to open new positions again, it moves to the state matching its position,
+// warm-up and cooldown.
+// Returns ErrStrategyNotFound if the strategy is not registered and ErrInvalidState if it is not paused.
+func (s *Service) ResumeStrategy(name string) error {
+	r, err := s.runner(name)
+	if err != nil {
+		return err
+	}
+
+	return r.setPaused(false, "")
+}
+
+// UpdateStrategy replaces the parameters of a registered strategy with the ones of strategy, matched by name.
+// A stopped or flat strategy is updated right away, a strategy holding a position keeps trading with the current
+// parameters until the position is closed. A changed token or symbol is re-authorized and re-subscribed.
+// Returns ErrStrategyNotFound if the strategy is not registered and ErrContractLimits if its amount or leverage
+// isn't accepted for its symbol.
+func (s *Service) UpdateStrategy(strategy Strategy) error {
+	r, err := s.runner(strategy.Name)
+	if err != nil {
+		return err
+	}
+
+	if strategy, err = s.checkLimits(strategy); err != nil {
+		return err
+	}
+
+	r.update(strategy)
+
+	return nil
+}
+
+// RemoveStrategy stops the strategy if it is running and unregisters it.
+// An open position is left untouched and is no longer managed by the strategy.
+// Returns ErrStrategyNotFound if the strategy is not registered.
+func (s *Service) RemoveStrategy(name string) error {
+	r, err := s.runner(name)
+	if err != nil {
+		return err
+	}
+
+	if err := r.stop(); err != nil && !errors.Is(err, ErrInvalidState) {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.runners, name)
+	s.names = slices.DeleteFunc(s.names, func(n string) bool { return n == name })
+
+	return nil
+}
+
+// Positions returns positions currently held by the registered strategies.
+func (s *Service) Positions() []OpenPosition {
+	statuses := s.Strategies()
+	positions := make([]OpenPosition, 0, len(statuses))
+
+	for _, st := range statuses {
+		if st.Position != nil {
+			positions = append(positions, *st.Position)
+		}
+	}
+
+	return positions
+}
+
+// claimContract makes the strategy the owner of the contract until its position is recorded, so a strategy
+// looking for the contract of a failed order never takes over a contract placed or taken over by another one.
+// Returns false if another strategy holds or claimed the contract.
+func (s *Service) claimContract(strategy string, contractID int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if owner, ok := s.claims[contractID]; ok && owner != strategy {
+		return false
+	}
+
+	for name, r := range s.runners {
+		if name != strategy && r.contractID() == contractID {
+			return false
+		}
+	}
+
+	s.claims[contractID] = strategy
+
+	return true
+}
+
+// releaseClaim drops the claim of a contract once the position holding it is recorded by its strategy.
+func (s *Service) releaseClaim(contractID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.claims, contractID)
+}
+
+// ClosePosition closes the position with the given contract ID through the strategy that owns it.
+// Returns ErrNoOpenPosition if no strategy holds the contract, or an error if closing fails.
+func (s *Service) ClosePosition(ctx context.Context, contractID int) error {
+	s.mu.Lock()
+
+	var owner *runner
+
+	for _, name := range s.names {
+		if r := s.runners[name]; r.contractID() == contractID {
+			owner = r
+			break
+		}
+	}
+
+	s.mu.Unlock()
+
+	if owner == nil {
+		return fmt.Errorf("%w: contract ID %d", ErrNoOpenPosition, contractID)
+	}
+
+	return owner.requestClose(ctx)
+}
+
+// Trades returns trades closed since the given time, oldest first.
+// Only the most recent trades are kept in memory, and the history starts with the process.
+func (s *Service) Trades(since time.Time) []ClosedTrade {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trades := make([]ClosedTrade, 0)
+
+	for _, t := range s.trades {
+		if !t.ClosedAt.Before(since) {
+			trades = append(trades, t)
+		}
+	}
+
+	return trades
+}
+
+func (s *Service) recordTrade(t ClosedTrade) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.trades) >= maxTradeHistory {
+		s.trades = s.trades[1:]
+	}
+
+	s.trades = append(s.trades, t)
+}
+
+// Contracts returns all open contracts on the trading accounts of strategies, including ones not opened by the bot.
+func (s *Service) Contracts(ctx context.Context) ([]Contract, error) {
+	sessions, err := s.accounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	contracts := make([]Contract, 0)
+
+	for _, sess := range sessions {
+		portfolio, err := sess.prov.Portfolio(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get open contracts of account %s: %w", sess.acc.ID, err)
+		}
+
+		for _, c := range portfolio {
+			c.LoginID = sess.acc.ID
+			contracts = append(contracts, c)
+		}
+	}
+
+	return contracts, nil
+}
+
+// Balances returns the balances of the trading accounts of strategies.
+func (s *Service) Balances(ctx context.Context) ([]Balance, error) {
+	sessions, err := s.accounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]Balance, 0, len(sessions))
+
+	for _, sess := range sessions {
+		balance, err := sess.prov.Balance(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get balance of account %s: %w", sess.acc.ID, err)
+		}
+
+		balances = append(balances, *balance)
+	}
+
+	return balances, nil
+}
+
+// accountSession is an authorized trading session of an account.
+type accountSession struct {
+	prov TradingProvider
+	acc  *Account
+}
+
+// accounts authorizes a session for every distinct token of registered strategies.
+// Returns one session per account and an error if a session can't be opened or authorized.
+func (s *Service) accounts(ctx context.Context) ([]accountSession, error) {
+	s.mu.Lock()
+
+	tokens := make([]string, 0, len(s.names))
+
+	for _, name := range s.names {
+		if token := s.runners[name].token(); !slices.Contains(tokens, token) {
+			tokens = append(tokens, token)
 		}
+	}
 
-		if cid != 0 && stategy.CheckToClose(tick) {
-			if err := s.tradingProv.ClosePosition(ctx, cid); err != nil {
-				return fmt.Errorf("failed to close position for account %s contract ID %d: %w", acc.ID, cid, err)
-			}
+	s.mu.Unlock()
 
-			cid = 0 // Reset contract ID after closing position
+	sessions := make([]accountSession, 0, len(tokens))
 
+	for _, token := range tokens {
+		prov, err := s.session(token)
+		if err != nil {
+			return nil, err
+		}
+
+		acc, err := prov.Authorize(ctx, token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to authorize trading provider: %w", err)
+		}
+
+		// Providers without sessions authorize every token on the same account.
+		if slices.ContainsFunc(sessions, func(a accountSession) bool { return a.acc.ID == acc.ID }) {
 			continue
 		}
+
+		sessions = append(sessions, accountSession{prov: prov, acc: acc})
 	}
 
-	return nil
+	return sessions, nil
+}
+
+// session returns the trading session of the account with token.
+func (s *Service) session(token string) (TradingProvider, error) {
+	sp, ok := s.tradingProv.(SessionProvider)
+	if !ok {
+		return s.tradingProv, nil
+	}
+
+	prov, err := sp.Session(token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trading session: %w", err)
+	}
+
+	return prov, nil
+}
+
+func (s *Service) runner(name string) (*runner, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.runners[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrStrategyNotFound, name)
+	}
+
+	return r, nil
 }
@@ -0,0 +1,563 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+	"github.com/ksysoev/deriv-bot/pkg/repo/subsmng"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMarket struct {
+	ticks        chan signal.Tick
+	resubscribed int
+	mu           sync.Mutex
+}
+
+func (m *fakeMarket) SubscribeOnMarket(_ context.Context, _ string) (<-chan signal.Tick, error) {
+	return m.ticks, nil
+}
+
+func (m *fakeMarket) Resubscribe(_ context.Context, _ string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.resubscribed++
+
+	return nil
+}
+
+// fakeTicks is a market data provider for the real signal service, ticks are sent to its latest subscription.
+type fakeTicks struct {
+	subs []chan signal.Tick
+	ctxs []context.Context
+	mu   sync.Mutex
+}
+
+func (p *fakeTicks) SubscribeToTicks(ctx context.Context, _ string) (<-chan signal.Tick, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ch := make(chan signal.Tick)
+	p.subs = append(p.subs, ch)
+	p.ctxs = append(p.ctxs, ctx)
+
+	return ch, nil
+}
+
+// ended reports whether the i-th subscription was cancelled.
+func (p *fakeTicks) ended(i int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.ctxs[i].Err() != nil
+}
+
+func (p *fakeTicks) send(quote float64) {
+	p.mu.Lock()
+	ch := p.subs[len(p.subs)-1]
+	p.mu.Unlock()
+
+	ch <- signal.Tick{Time: time.Now(), Quote: quote}
+}
+
+func (p *fakeTicks) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.subs)
+}
+
+type fakeTrading struct {
+	account   string
+	buyErrs   []error
+	portfolio []Contract
+	sold      []SoldContract
+	closed    []int
+	protected []int
+	nextID    int
+	mu        sync.Mutex
+}
+
+func (t *fakeTrading) Authorize(_ context.Context, _ string) (*Account, error) {
+	return &Account{ID: t.loginID(), Currency: "USD"}, nil
+}
+
+func (t *fakeTrading) loginID() string {
+	if t.account == "" {
+		return "CR123"
+	}
+
+	return t.account
+}
+
+func (t *fakeTrading) Buy(_ context.Context, _ Position) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.buyErrs) > 0 {
+		err := t.buyErrs[0]
+		t.buyErrs = t.buyErrs[1:]
+
+		return 0, err
+	}
+
+	t.nextID++
+
+	return t.nextID, nil
+}
+
+func (t *fakeTrading) Sell(ctx context.Context, pos Position) (int, error) {
+	return t.Buy(ctx, pos)
+}
+
+func (t *fakeTrading) ClosePosition(_ context.Context, contractID int) (float64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.closed = append(t.closed, contractID)
+
+	return 10, nil
+}
+
+func (t *fakeTrading) SetLimits(_ context.Context, contractID int, _, _ float64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.protected = append(t.protected, contractID)
+
+	return nil
+}
+
+func (t *fakeTrading) Balance(_ context.Context) (*Balance, error) {
+	return &Balance{LoginID: t.loginID(), Currency: "USD", Amount: 100}, nil
+}
+
+func (t *fakeTrading) Portfolio(_ context.Context) ([]Contract, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.portfolio, nil
+}
+
+func (t *fakeTrading) SoldContracts(_ context.Context, _ time.Time) ([]SoldContract, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.sold, nil
+}
+
+// fakeSessions keeps a separate fake trading session per token.
+type fakeSessions struct {
+	fakeTrading
+	sessions map[string]*fakeTrading
+}
+
+func (s *fakeSessions) Session(token string) (TradingProvider, error) {
+	return s.sessions[token], nil
+}
+
+func noBackoff(int) time.Duration { return 0 }
+
+func TestService_StrategyLifecycle(t *testing.T) {
+	market := &fakeMarket{ticks: make(chan signal.Tick)}
+	trading := &fakeTrading{}
+	bus := event.NewBus()
+	svc := New(market, trading, bus)
+
+	events, unsubscribe := bus.Subscribe(event.TypeOrderPlaced, event.TypePositionClosed)
+	defer unsubscribe()
+
+	require.NoError(t, svc.AddStrategy(Strategy{
+		Name:         "test",
+		Symbol:       "R_100",
+		Type:         StrategyTypeBuy,
+		Amount:       10,
+		Leverage:     10,
+		CheckToOpen:  func(tick signal.Tick) bool { return tick.Quote > 100 },
+		CheckToClose: func(signal.Tick) bool { return false },
+	}))
+	assert.ErrorIs(t, svc.AddStrategy(Strategy{Name: "test"}), ErrStrategyExists)
+	assert.ErrorIs(t, svc.StartStrategy("test"), ErrNotRunning)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- svc.Run(ctx) }()
+
+	assert.Eventually(t, func() bool {
+		st, err := svc.Strategy("test")
+		return err == nil && st.State == StrategyStateWaitingToOpen
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, svc.PauseStrategy("test"))
+	// The second send returns only after the loop has finished handling the first tick.
+	market.ticks <- signal.Tick{Quote: 101}
+	market.ticks <- signal.Tick{Quote: 100}
+	assert.Empty(t, svc.Positions())
+
+	require.NoError(t, svc.ResumeStrategy("test"))
+	assert.ErrorIs(t, svc.ResumeStrategy("test"), ErrInvalidState)
+
+	market.ticks <- signal.Tick{Quote: 101}
+
+	assert.Eventually(t, func() bool { return len(svc.Positions()) == 1 }, time.Second, 10*time.Millisecond)
+
+	pos := svc.Positions()[0]
+	assert.Equal(t, "test", pos.Strategy)
+	assert.InDelta(t, 101, pos.Price, 0)
+
+	assert.ErrorIs(t, svc.ClosePosition(ctx, pos.ContractID+1), ErrNoOpenPosition)
+	require.NoError(t, svc.ClosePosition(ctx, pos.ContractID))
+	assert.Equal(t, []int{pos.ContractID}, trading.closed)
+	assert.Empty(t, svc.Positions())
+
+	placed := <-events
+	assert.Equal(t, event.TypeOrderPlaced, placed.Type)
+	assert.Equal(t, pos.ContractID, placed.Payload.(event.OrderPlaced).ContractID)
+
+	closed := <-events
+	assert.Equal(t, event.TypePositionClosed, closed.Type)
+	assert.Equal(t, event.PositionClosed{Strategy: "test", Symbol: "R_100", ContractID: pos.ContractID}, closed.Payload)
+
+	require.NoError(t, svc.StopStrategy("test"))
+	assert.ErrorIs(t, svc.StopStrategy("test"), ErrInvalidState)
+	assert.ErrorIs(t, svc.StopStrategy("missing"), ErrStrategyNotFound)
+
+	st, err := svc.Strategy("test")
+	require.NoError(t, err)
+	assert.Equal(t, StrategyStateStopped, st.State)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestService_UpdateStrategy(t *testing.T) {
+	market := &fakeMarket{ticks: make(chan signal.Tick)}
+	svc := New(market, &fakeTrading{}, event.NewBus())
+
+	strategy := Strategy{
+		Name:         "test",
+		Symbol:       "R_100",
+		Type:         StrategyTypeBuy,
+		Amount:       10,
+		Leverage:     10,
+		CheckToOpen:  func(tick signal.Tick) bool { return tick.Quote > 100 },
+		CheckToClose: func(tick signal.Tick) bool { return tick.Quote > 110 },
+	}
+
+	require.NoError(t, svc.AddStrategy(strategy))
+
+	updated := strategy
+	updated.Amount = 20
+
+	assert.ErrorIs(t, svc.UpdateStrategy(Strategy{Name: "missing"}), ErrStrategyNotFound)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- svc.Run(ctx) }()
+
+	market.ticks <- signal.Tick{Quote: 101}
+
+	require.Eventually(t, func() bool { return len(svc.Positions()) == 1 }, time.Second, 10*time.Millisecond)
+
+	// The update waits until the strategy is flat.
+	require.NoError(t, svc.UpdateStrategy(updated))
+	market.ticks <- signal.Tick{Quote: 105}
+	market.ticks <- signal.Tick{Quote: 106}
+
+	st, err := svc.Strategy("test")
+	require.NoError(t, err)
+	assert.InDelta(t, 10, st.Amount, 0)
+
+	market.ticks <- signal.Tick{Quote: 111}
+
+	assert.Eventually(t, func() bool {
+		st, err := svc.Strategy("test")
+		return err == nil && st.Amount == 20
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, svc.RemoveStrategy("test"))
+	assert.Empty(t, svc.Strategies())
+	assert.ErrorIs(t, svc.RemoveStrategy("test"), ErrStrategyNotFound)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+type fakeHours struct {
+	open bool
+	mu   sync.Mutex
+}
+
+func (h *fakeHours) IsOpen(_ string, _ time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.open
+}
+
+func (h *fakeHours) set(open bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.open = open
+}
+
+func TestService_MarketClosed(t *testing.T) {
+	market := &fakeMarket{ticks: make(chan signal.Tick)}
+	hours := &fakeHours{}
+	svc := New(market, &fakeTrading{}, event.NewBus())
+	svc.SetMarketHours(hours)
+
+	checked := 0
+
+	require.NoError(t, svc.AddStrategy(Strategy{
+		Name:     "test",
+		Symbol:   "R_100",
+		Type:     StrategyTypeBuy,
+		Amount:   10,
+		Leverage: 10,
+		CheckToOpen: func(tick signal.Tick) bool {
+			checked++
+			return tick.Quote > 100
+		},
+		CheckToClose: func(signal.Tick) bool { return false },
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- svc.Run(ctx) }()
+
+	// The last send returns only after the loop has finished handling the previous ticks.
+	market.ticks <- signal.Tick{Quote: 101}
+	market.ticks <- signal.Tick{Quote: 102}
+	market.ticks <- signal.Tick{Quote: 100}
+	assert.Empty(t, svc.Positions())
+
+	hours.set(true)
+
+	market.ticks <- signal.Tick{Quote: 103}
+
+	assert.Eventually(t, func() bool { return len(svc.Positions()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 4, checked)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestService_SessionPerToken(t *testing.T) {
+	market := &fakeMarket{ticks: make(chan signal.Tick)}
+	trading := &fakeSessions{sessions: map[string]*fakeTrading{
+		"first":  {account: "CR1", portfolio: []Contract{{ID: 7, Symbol: "R_100"}}},
+		"second": {account: "CR2"},
+	}}
+	svc := New(market, trading, event.NewBus())
+
+	for _, name := range []string{"first", "second", "third"} {
+		token := name
+		if name == "third" {
+			token = "first"
+		}
+
+		require.NoError(t, svc.AddStrategy(Strategy{
+			Name:         name,
+			Token:        token,
+			Symbol:       "R_100",
+			Type:         StrategyTypeBuy,
+			Amount:       10,
+			Leverage:     10,
+			CheckToOpen:  func(signal.Tick) bool { return name != "third" },
+			CheckToClose: func(signal.Tick) bool { return false },
+		}))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- svc.Run(ctx) }()
+
+	// Every tick is received by one of the strategies sharing the fake subscription.
+	require.Eventually(t, func() bool {
+		select {
+		case market.ticks <- signal.Tick{Quote: 101}:
+		default:
+		}
+
+		return len(svc.Positions()) == 2
+	}, time.Second, 5*time.Millisecond)
+
+	assert.Equal(t, 1, trading.sessions["first"].nextID)
+	assert.Equal(t, 1, trading.sessions["second"].nextID)
+	assert.Zero(t, trading.nextID)
+
+	balances, err := svc.Balances(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []Balance{
+		{LoginID: "CR1", Currency: "USD", Amount: 100},
+		{LoginID: "CR2", Currency: "USD", Amount: 100},
+	}, balances)
+
+	contracts, err := svc.Contracts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []Contract{{ID: 7, Symbol: "R_100", LoginID: "CR1"}}, contracts)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestService_RestartSharingSubscription(t *testing.T) {
+	prov := &fakeTicks{}
+	bus := event.NewBus()
+	svc := New(signal.New(prov, subsmng.New(), bus), &fakeTrading{}, bus)
+
+	var mu sync.Mutex
+
+	seen := make(map[string][]float64)
+	seenBy := func(name string) []float64 {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return seen[name]
+	}
+
+	for _, name := range []string{"first", "second"} {
+		require.NoError(t, svc.AddStrategy(Strategy{
+			Name:     name,
+			Symbol:   "R_100",
+			Type:     StrategyTypeBuy,
+			Amount:   10,
+			Leverage: 10,
+			CheckToOpen: func(tick signal.Tick) bool {
+				mu.Lock()
+				defer mu.Unlock()
+
+				seen[name] = append(seen[name], tick.Quote)
+
+				return false
+			},
+			CheckToClose: func(signal.Tick) bool { return false },
+		}))
+	}
+
+	waitingToOpen := func(name string) func() bool {
+		return func() bool {
+			st, err := svc.Strategy(name)
+			return err == nil && st.State == StrategyStateWaitingToOpen
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- svc.Run(ctx) }()
+
+	require.Eventually(t, waitingToOpen("first"), time.Second, 5*time.Millisecond)
+	require.Eventually(t, waitingToOpen("second"), time.Second, 5*time.Millisecond)
+
+	prov.send(100)
+
+	require.Eventually(t, func() bool { return len(seenBy("first")) == 1 && len(seenBy("second")) == 1 },
+		time.Second, 5*time.Millisecond)
+
+	// The restarted strategy joins the subscription the other strategy keeps alive.
+	require.NoError(t, svc.StopStrategy("first"))
+	require.NoError(t, svc.StartStrategy("first"))
+	require.Eventually(t, waitingToOpen("first"), time.Second, 5*time.Millisecond)
+
+	prov.send(101)
+	prov.send(102)
+
+	require.Eventually(t, func() bool { return len(seenBy("first")) == 3 && len(seenBy("second")) == 3 },
+		time.Second, 5*time.Millisecond)
+	assert.Equal(t, []float64{100, 101, 102}, seenBy("first"))
+	assert.Equal(t, []float64{100, 101, 102}, seenBy("second"))
+	assert.Equal(t, 1, prov.count())
+
+	// Once every strategy stopped, starting again opens a new subscription.
+	require.NoError(t, svc.StopStrategy("first"))
+	require.NoError(t, svc.StopStrategy("second"))
+	require.Eventually(t, func() bool { return prov.ended(0) }, time.Second, 5*time.Millisecond)
+	require.NoError(t, svc.StartStrategy("first"))
+	require.Eventually(t, waitingToOpen("first"), time.Second, 5*time.Millisecond)
+	require.Eventually(t, func() bool { return prov.count() == 2 }, time.Second, 5*time.Millisecond)
+
+	prov.send(103)
+
+	require.Eventually(t, func() bool { return len(seenBy("first")) == 4 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestService_UpdateSymbolLeavesSubscription(t *testing.T) {
+	prov := &fakeTicks{}
+	bus := event.NewBus()
+	svc := New(signal.New(prov, subsmng.New(), bus), &fakeTrading{}, bus)
+
+	var mu sync.Mutex
+
+	var seen []float64
+
+	seenTicks := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(seen)
+	}
+
+	// Ticks sent before the strategy joined the subscription are dropped, so they're sent until one is seen.
+	sendUntilSeen := func(quote float64, want int) func() bool {
+		return func() bool {
+			prov.send(quote)
+			return seenTicks() == want
+		}
+	}
+
+	strategy := Strategy{
+		Name:     "test",
+		Symbol:   "R_100",
+		Type:     StrategyTypeBuy,
+		Amount:   10,
+		Leverage: 10,
+		CheckToOpen: func(tick signal.Tick) bool {
+			mu.Lock()
+			defer mu.Unlock()
+
+			seen = append(seen, tick.Quote)
+
+			return false
+		},
+		CheckToClose: func(signal.Tick) bool { return false },
+	}
+
+	require.NoError(t, svc.AddStrategy(strategy))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- svc.Run(ctx) }()
+
+	require.Eventually(t, func() bool { return prov.count() == 1 }, time.Second, 5*time.Millisecond)
+	require.Eventually(t, sendUntilSeen(100, 1), time.Second, 5*time.Millisecond)
+
+	updated := strategy
+	updated.Symbol = "R_50"
+
+	require.NoError(t, svc.UpdateStrategy(updated))
+
+	// The subscription of the old symbol ends with its only subscriber, ticks come from the new symbol.
+	require.Eventually(t, func() bool { return prov.count() == 2 && prov.ended(0) }, time.Second, 5*time.Millisecond)
+
+	require.Eventually(t, sendUntilSeen(101, 2), time.Second, 5*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
@@ -0,0 +1,209 @@
+// Package market keeps the catalogue of tradable symbols with their metadata and trading hours.
+package market
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+const refreshInterval = 5 * time.Minute
+
+var ErrUnknownSymbol = errors.New("unknown symbol")
+
+// Symbol describes a tradable symbol. Limits are loaded only for symbols used by strategies.
+type Symbol struct {
+	Name         string
+	DisplayName  string
+	Market       string
+	Submarket    string
+	Limits       Limits
+	Pip          float64
+	ExchangeOpen bool
+	Suspended    bool
+}
+
+// Limits are the parameters accepted for multiplier contracts on a symbol.
+// A zero MinStake or MaxStake means the bound isn't published.
+type Limits struct {
+	Multipliers []float64
+	MinStake    float64
+	MaxStake    float64
+}
+
+// Session is a period of a day during which a symbol can be traded.
+type Session struct {
+	Open  time.Time
+	Close time.Time
+}
+
+type Provider interface {
+	ActiveSymbols(ctx context.Context) ([]Symbol, error)
+	TradingTimes(ctx context.Context, date time.Time) (map[string][]Session, error)
+	ContractLimits(ctx context.Context, symbol string) (Limits, error)
+}
+
+// Catalogue caches active symbols and trading times of the provider and refreshes them periodically.
+type Catalogue struct {
+	prov        Provider
+	symbols     map[string]Symbol
+	sessions    map[string][]Session
+	limits      map[string]Limits
+	sessionsDay string
+	mu          sync.RWMutex
+}
+
+// New creates an empty catalogue of the symbols offered by prov. Load fills it.
+func New(prov Provider) *Catalogue {
+	return &Catalogue{
+		prov:     prov,
+		symbols:  make(map[string]Symbol),
+		sessions: make(map[string][]Session),
+		limits:   make(map[string]Limits),
+	}
+}
+
+// Load refreshes active symbols and today's trading times, and loads the contract limits of symbols.
+// Returns an error wrapping ErrUnknownSymbol for every symbol that isn't active,
+// and an error if the provider requests fail.
+func (c *Catalogue) Load(ctx context.Context, symbols []string) error {
+	if err := c.refresh(ctx, time.Now()); err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, name := range symbols {
+		if _, ok := c.Symbol(name); !ok {
+			errs = append(errs, fmt.Errorf("%w %q", ErrUnknownSymbol, name))
+			continue
+		}
+
+		if _, loaded := c.Limits(name); loaded {
+			continue
+		}
+
+		limits, err := c.prov.ContractLimits(ctx, name)
+		if err != nil {
+			return fmt.Errorf("failed to load contract limits of %s: %w", name, err)
+		}
+
+		c.mu.Lock()
+		c.limits[name] = limits
+		c.mu.Unlock()
+	}
+
+	return errors.Join(errs...)
+}
+
+// Run refreshes the catalogue every few minutes until ctx is cancelled, trading times are reloaded when the
+// UTC day changes. Refresh failures are logged and the cached data is kept.
+// Returns nil when ctx is cancelled.
+func (c *Catalogue) Run(ctx context.Context) error {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			if err := c.refresh(ctx, now); err != nil {
+				slog.Warn("Failed to refresh market catalogue", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// Symbol returns the metadata of the symbol name, and false if the symbol isn't active.
+func (c *Catalogue) Symbol(name string) (Symbol, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	sym, ok := c.symbols[name]
+	if !ok {
+		return Symbol{}, false
+	}
+
+	sym.Limits, _ = c.limitsLocked(name)
+
+	return sym, true
+}
+
+// Limits returns the contract limits of the symbol name, and false if they aren't loaded.
+func (c *Catalogue) Limits(name string) (Limits, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.limitsLocked(name)
+}
+
+func (c *Catalogue) limitsLocked(name string) (Limits, bool) {
+	limits, ok := c.limits[name]
+	limits.Multipliers = slices.Clone(limits.Multipliers)
+
+	return limits, ok
+}
+
+// IsOpen reports whether symbol can be traded at t: it has to be active, not suspended and, when trading times
+// of the day of t are known, inside one of the sessions. Otherwise the open flag of the exchange is used.
+func (c *Catalogue) IsOpen(symbol string, t time.Time) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	sym, ok := c.symbols[symbol]
+	if !ok || sym.Suspended {
+		return false
+	}
+
+	if c.sessionsDay != t.UTC().Format(time.DateOnly) {
+		return sym.ExchangeOpen
+	}
+
+	for _, s := range c.sessions[symbol] {
+		if !t.Before(s.Open) && t.Before(s.Close) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// refresh reloads active symbols, and trading times if they aren't loaded for the day of now yet.
+func (c *Catalogue) refresh(ctx context.Context, now time.Time) error {
+	active, err := c.prov.ActiveSymbols(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load active symbols: %w", err)
+	}
+
+	symbols := make(map[string]Symbol, len(active))
+	for _, sym := range active {
+		symbols[sym.Name] = sym
+	}
+
+	c.mu.Lock()
+	c.symbols = symbols
+	day := c.sessionsDay
+	c.mu.Unlock()
+
+	today := now.UTC().Format(time.DateOnly)
+	if day == today {
+		return nil
+	}
+
+	sessions, err := c.prov.TradingTimes(ctx, now.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to load trading times: %w", err)
+	}
+
+	c.mu.Lock()
+	c.sessions = sessions
+	c.sessionsDay = today
+	c.mu.Unlock()
+
+	return nil
+}
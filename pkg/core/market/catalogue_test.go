@@ -0,0 +1,108 @@
+package market
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProvider struct {
+	err        error
+	sessions   map[string][]Session
+	limits     map[string]Limits
+	symbols    []Symbol
+	timesCalls int
+}
+
+func (p *fakeProvider) ActiveSymbols(_ context.Context) ([]Symbol, error) {
+	return p.symbols, p.err
+}
+
+func (p *fakeProvider) TradingTimes(_ context.Context, _ time.Time) (map[string][]Session, error) {
+	p.timesCalls++
+
+	return p.sessions, nil
+}
+
+func (p *fakeProvider) ContractLimits(_ context.Context, symbol string) (Limits, error) {
+	return p.limits[symbol], nil
+}
+
+func TestCatalogue_Load(t *testing.T) {
+	prov := &fakeProvider{
+		symbols: []Symbol{
+			{Name: "R_100", Market: "synthetic_index", Pip: 0.01, ExchangeOpen: true},
+			{Name: "frxEURUSD", Market: "forex", Pip: 0.00001, ExchangeOpen: true},
+		},
+		limits: map[string]Limits{"R_100": {Multipliers: []float64{40, 100}, MinStake: 1, MaxStake: 2000}},
+	}
+	c := New(prov)
+
+	err := c.Load(context.Background(), []string{"R_100", "R_1000", "frxXAUUSD"})
+	require.ErrorIs(t, err, ErrUnknownSymbol)
+	assert.ErrorContains(t, err, `"R_1000"`)
+	assert.ErrorContains(t, err, `"frxXAUUSD"`)
+
+	sym, ok := c.Symbol("R_100")
+	require.True(t, ok)
+	assert.Equal(t, "synthetic_index", sym.Market)
+	assert.Equal(t, Limits{Multipliers: []float64{40, 100}, MinStake: 1, MaxStake: 2000}, sym.Limits)
+
+	_, ok = c.Symbol("R_1000")
+	assert.False(t, ok)
+
+	_, ok = c.Limits("frxEURUSD")
+	assert.False(t, ok, "limits are loaded only for requested symbols")
+
+	require.NoError(t, c.Load(context.Background(), []string{"frxEURUSD"}))
+	assert.Equal(t, 1, prov.timesCalls, "trading times are loaded once a day")
+
+	prov.err = errors.New("connection lost")
+	assert.ErrorContains(t, c.Load(context.Background(), nil), "connection lost")
+}
+
+func TestCatalogue_IsOpen(t *testing.T) {
+	now := time.Now().UTC()
+	day := now.Truncate(24 * time.Hour)
+
+	prov := &fakeProvider{
+		symbols: []Symbol{
+			{Name: "R_100", ExchangeOpen: true},
+			{Name: "frxEURUSD", ExchangeOpen: true},
+			{Name: "frxXAUUSD", ExchangeOpen: true, Suspended: true},
+			{Name: "WLDAUD", ExchangeOpen: false},
+		},
+		sessions: map[string][]Session{
+			"R_100":     {{Open: day, Close: day.Add(24 * time.Hour)}},
+			"frxEURUSD": {{Open: day.Add(8 * time.Hour), Close: day.Add(16 * time.Hour)}},
+			"frxXAUUSD": {{Open: day, Close: day.Add(24 * time.Hour)}},
+		},
+	}
+	c := New(prov)
+
+	require.NoError(t, c.Load(context.Background(), nil))
+
+	tests := []struct {
+		at     time.Time
+		symbol string
+		want   bool
+	}{
+		{symbol: "R_100", at: now, want: true},
+		{symbol: "frxEURUSD", at: day.Add(7 * time.Hour), want: false},
+		{symbol: "frxEURUSD", at: day.Add(8 * time.Hour), want: true},
+		{symbol: "frxEURUSD", at: day.Add(16 * time.Hour), want: false},
+		{symbol: "frxXAUUSD", at: now, want: false},
+		{symbol: "R_1000", at: now, want: false},
+		// Without trading times of the day, the exchange flag decides.
+		{symbol: "frxEURUSD", at: day.Add(-time.Hour), want: true},
+		{symbol: "WLDAUD", at: day.Add(-time.Hour), want: false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, c.IsOpen(tt.symbol, tt.at), "%s at %s", tt.symbol, tt.at.Format(time.DateTime))
+	}
+}
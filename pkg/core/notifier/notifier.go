@@ -0,0 +1,171 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
+	"golang.org/x/time/rate"
+)
+
+const sendTimeout = 10 * time.Second
+
+// DefaultEvents are the event types delivered to a sink when no filter is configured.
+var DefaultEvents = []event.Type{
+	event.TypeOrderPlaced,
+	event.TypePositionClosed,
+	event.TypeRiskBreach,
+}
+
+// Notification is a formatted event ready to be delivered to a sink.
+type Notification struct {
+	Event event.Event
+	Title string
+	Text  string
+}
+
+type Sink interface {
+	Send(ctx context.Context, n Notification) error
+}
+
+type EventSubscriber interface {
+	Subscribe(types ...event.Type) (<-chan event.Event, func())
+}
+
+// SinkConfig describes where and which notifications are delivered.
+// RatePerMinute limits the number of notifications sent to the sink, notifications above the limit are dropped.
+// Zero means no limit. Burst allows short spikes above the rate, it defaults to 1.
+type SinkConfig struct {
+	Sink          Sink
+	Name          string
+	Events        []event.Type
+	RatePerMinute int
+	Burst         int
+}
+
+type Service struct {
+	events EventSubscriber
+	sinks  []SinkConfig
+}
+
+// New creates a notifier delivering events from events to the configured sinks.
+// Returns a pointer to the initialized Service.
+func New(events EventSubscriber, sinks []SinkConfig) *Service {
+	return &Service{
+		events: events,
+		sinks:  sinks,
+	}
+}
+
+// Run subscribes every sink to its events and delivers notifications until ctx is cancelled.
+// Each sink is served by its own goroutine, so a slow or failing sink doesn't delay the others.
+// Delivery failures are logged and don't stop the notifier. Returns nil when ctx is cancelled.
+func (s *Service) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+
+	for _, sink := range s.sinks {
+		types := sink.Events
+		if len(types) == 0 {
+			types = DefaultEvents
+		}
+
+		events, unsubscribe := s.events.Subscribe(types...)
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer unsubscribe()
+
+			serveSink(ctx, sink, events)
+		}()
+	}
+
+	wg.Wait()
+
+	return nil
+}
+
+func serveSink(ctx context.Context, sink SinkConfig, events <-chan event.Event) {
+	limiter := rate.NewLimiter(rate.Inf, 0)
+
+	if sink.RatePerMinute > 0 {
+		limiter = rate.NewLimiter(rate.Every(time.Minute/time.Duration(sink.RatePerMinute)), max(sink.Burst, 1))
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+
+			if !limiter.Allow() {
+				slog.Warn("Notification dropped by rate limit", slog.String("sink", sink.Name), slog.String("event", string(e.Type)))
+				continue
+			}
+
+			sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+			err := sink.Sink.Send(sendCtx, Format(e))
+
+			cancel()
+
+			if err != nil {
+				slog.Error("Failed to send notification", slog.String("sink", sink.Name), slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// Format renders an event as a human readable notification.
+func Format(e event.Event) Notification {
+	n := Notification{Event: e, Title: string(e.Type)}
+
+	switch p := e.Payload.(type) {
+	case event.OrderPlaced:
+		n.Title = "Position opened"
+		n.Text = fmt.Sprintf("%s: %s %s, stake %.2f x%.0f at %.5f, contract %d",
+			p.Strategy, p.Side, p.Symbol, p.Amount, p.Leverage, p.Price, p.ContractID)
+	case event.PositionClosed:
+		n.Title = "Position closed"
+		n.Text = fmt.Sprintf("%s: %s contract %d closed with profit %.2f", p.Strategy, p.Symbol, p.ContractID, p.Profit)
+	case event.OrderFailed:
+		n.Title = "Order failed"
+		n.Text = fmt.Sprintf("%s: %s %s failed: %s", p.Strategy, p.Operation, p.Symbol, p.Error)
+
+		if p.Action != "" {
+			n.Text += fmt.Sprintf(" (%s, %s)", p.Kind, p.Action)
+		}
+	case event.RiskBreach:
+		n.Title = "Risk limit breached"
+		n.Text = fmt.Sprintf("%s: %s, %s", p.Strategy, p.Limit, p.Details)
+	case event.SignalFired:
+		n.Title = "Signal fired"
+		n.Text = fmt.Sprintf("%s: %s signal on %s at %.5f", p.Strategy, p.Signal, p.Symbol, p.Quote)
+	case event.Reconnect:
+		n.Title = "Reconnecting"
+		n.Text = fmt.Sprintf("%s: %s attempt %d: %s", p.Strategy, p.Symbol, p.Attempt, p.Reason)
+	case event.DataQuality:
+		n.Title = "Market data issue"
+		n.Text = fmt.Sprintf("%s: %s, %s", p.Symbol, p.Issue, p.Details)
+	case event.StrategyState:
+		n.Title = "Strategy state changed"
+		n.Text = fmt.Sprintf("%s: %s -> %s", p.Strategy, p.From, p.To)
+
+		if p.Reason != "" {
+			n.Text += ": " + p.Reason
+		}
+	case event.TickReceived:
+		n.Title = "Tick"
+		n.Text = fmt.Sprintf("%s %.5f", p.Symbol, p.Quote)
+	default:
+		n.Text = fmt.Sprintf("%+v", e.Payload)
+	}
+
+	return n
+}
@@ -0,0 +1,80 @@
+package notifier
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSink struct {
+	sent []Notification
+	mu   sync.Mutex
+}
+
+func (s *fakeSink) Send(_ context.Context, n Notification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sent = append(s.sent, n)
+
+	return nil
+}
+
+func (s *fakeSink) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.sent)
+}
+
+// countingBus signals every subscription, so the test can publish once all sinks are subscribed.
+type countingBus struct {
+	*event.Bus
+	subscribed sync.WaitGroup
+}
+
+func (b *countingBus) Subscribe(types ...event.Type) (<-chan event.Event, func()) {
+	defer b.subscribed.Done()
+
+	return b.Bus.Subscribe(types...)
+}
+
+func TestService_Run(t *testing.T) {
+	bus := &countingBus{Bus: event.NewBus()}
+	bus.subscribed.Add(2)
+
+	defaults, limited := &fakeSink{}, &fakeSink{}
+
+	svc := New(bus, []SinkConfig{
+		{Name: "defaults", Sink: defaults},
+		{Name: "limited", Sink: limited, Events: []event.Type{event.TypeOrderFailed}, RatePerMinute: 1, Burst: 2},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- svc.Run(ctx) }()
+
+	bus.subscribed.Wait()
+
+	bus.Publish(event.New(event.TypeTickReceived, event.TickReceived{Symbol: "R_100"}))
+	bus.Publish(event.New(event.TypeOrderPlaced, event.OrderPlaced{Symbol: "R_100"}))
+	bus.Publish(event.New(event.TypePositionClosed, event.PositionClosed{Symbol: "R_100"}))
+
+	for range 5 {
+		bus.Publish(event.New(event.TypeOrderFailed, event.OrderFailed{Symbol: "R_100"}))
+	}
+
+	assert.Eventually(t, func() bool { return defaults.count() == 2 && limited.count() == 2 }, time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+
+	assert.Equal(t, "Position opened", defaults.sent[0].Title)
+	assert.Equal(t, "Position closed", defaults.sent[1].Title)
+	assert.Equal(t, 2, limited.count())
+}
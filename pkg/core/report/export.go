@@ -0,0 +1,257 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	chartWidth  = 800
+	chartHeight = 240
+)
+
+// Export formats.
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatHTML = "html"
+)
+
+// Formats lists the supported export formats.
+var Formats = []string{FormatJSON, FormatCSV, FormatHTML}
+
+// Write exports the report and its trades to w in format.
+// Returns an error if the format is unknown or writing fails.
+func Write(w io.Writer, format string, r *Report, trades []Trade) error {
+	switch format {
+	case FormatJSON:
+		return WriteJSON(w, r)
+	case FormatCSV:
+		return WriteCSV(w, r, trades)
+	case FormatHTML:
+		return WriteHTML(w, r)
+	default:
+		return fmt.Errorf("unknown report format %q", format)
+	}
+}
+
+// WriteJSON writes the report with its curves as indented JSON.
+func WriteJSON(w io.Writer, r *Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(r)
+}
+
+// WriteCSV writes a row per trade ordered by close time, with the equity and drawdown after the trade.
+// trades must be the trades the report was computed from.
+func WriteCSV(w io.Writer, r *Report, trades []Trade) error {
+	if len(trades) != len(r.Equity) {
+		return fmt.Errorf("report has %d trades, got %d", len(r.Equity), len(trades))
+	}
+
+	sorted := append([]Trade(nil), trades...)
+	sortByClose(sorted)
+
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"opened_at", "closed_at", "symbol", "stake", "profit", "equity", "drawdown"}); err != nil {
+		return err
+	}
+
+	for i, t := range sorted {
+		err := cw.Write([]string{
+			t.OpenedAt.UTC().Format(time.RFC3339Nano),
+			t.ClosedAt.UTC().Format(time.RFC3339Nano),
+			t.Symbol,
+			formatFloat(t.Stake),
+			formatFloat(t.Profit),
+			formatFloat(r.Equity[i].Value),
+			formatFloat(r.Drawdown[i].Value),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// WriteHTML writes a self-contained HTML page with the statistics and SVG charts of the equity and drawdown curves.
+func WriteHTML(w io.Writer, r *Report) error {
+	return htmlTemplate.Execute(w, struct {
+		*Report
+		EquityPath   string
+		DrawdownPath string
+	}{
+		Report:       r,
+		EquityPath:   svgPath(r.Equity, r.InitialBalance),
+		DrawdownPath: svgPath(r.Drawdown, 0),
+	})
+}
+
+// svgPath scales the curve, starting at start, into the chart area and returns it as SVG path data.
+func svgPath(points []Point, start float64) string {
+	values := make([]float64, 0, len(points)+1)
+	values = append(values, start)
+
+	for _, p := range points {
+		values = append(values, p.Value)
+	}
+
+	lo, hi := values[0], values[0]
+
+	for _, v := range values {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+
+	if hi == lo {
+		hi = lo + 1
+	}
+
+	var sb strings.Builder
+
+	for i, v := range values {
+		x := chartWidth * float64(i) / float64(max(len(values)-1, 1))
+		y := chartHeight - chartHeight*(v-lo)/(hi-lo)
+
+		cmd := "L"
+		if i == 0 {
+			cmd = "M"
+		}
+
+		fmt.Fprintf(&sb, "%s%.1f %.1f ", cmd, x, y)
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func optional(v *float64) string {
+	if v == nil {
+		return "n/a"
+	}
+
+	return fmt.Sprintf("%.2f", *v)
+}
+
+func pct(v float64) string {
+	return fmt.Sprintf("%.1f%%", v*100)
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"optional": optional,
+	"pct":      pct,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Trading performance report</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2rem; }
+td { padding: 0.25rem 1rem 0.25rem 0; }
+td:last-child { text-align: right; font-variant-numeric: tabular-nums; }
+svg { border: 1px solid #ddd; background: #fafafa; display: block; margin-bottom: 2rem; }
+</style>
+</head>
+<body>
+<h1>Trading performance report</h1>
+<table>
+<tr><td>Trades</td><td>{{.Trades}}</td></tr>
+<tr><td>Win rate</td><td>{{pct .WinRate}}</td></tr>
+<tr><td>Net PnL</td><td>{{printf "%.2f" .NetPnL}}</td></tr>
+<tr><td>Profit factor</td><td>{{optional .ProfitFactor}}</td></tr>
+<tr><td>Expectancy</td><td>{{printf "%.2f" .Expectancy}}</td></tr>
+<tr><td>Average win</td><td>{{printf "%.2f" .AverageWin}}</td></tr>
+<tr><td>Average loss</td><td>{{printf "%.2f" .AverageLoss}}</td></tr>
+<tr><td>Longest losing streak</td><td>{{.LongestLosingStreak}}</td></tr>
+<tr><td>Sharpe</td><td>{{printf "%.2f" .Sharpe}}</td></tr>
+<tr><td>Sortino</td><td>{{printf "%.2f" .Sortino}}</td></tr>
+<tr><td>Calmar</td><td>{{optional .Calmar}}</td></tr>
+<tr><td>Max drawdown</td><td>{{printf "%.2f" .MaxDrawdown}}</td></tr>
+</table>
+<h2>Equity</h2>
+<svg width="800" height="240" viewBox="0 0 800 240"><path d="{{.EquityPath}}" fill="none" stroke="#2a7ab0" stroke-width="1.5"/></svg>
+<h2>Drawdown</h2>
+<svg width="800" height="240" viewBox="0 0 800 240"><path d="{{.DrawdownPath}}" fill="none" stroke="#c0392b" stroke-width="1.5"/></svg>
+</body>
+</html>
+`))
+
+// ReadCSV reads trades from CSV data with a header row naming the opened_at, closed_at, symbol, stake and profit
+// columns, times in RFC 3339 format. Other columns are ignored, so reports exported with WriteCSV can be read back.
+// Returns an error if a column is missing or a value is malformed.
+func ReadCSV(r io.Reader) ([]Trade, error) {
+	cr := csv.NewReader(r)
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	cols := make(map[string]int, len(header))
+	for i, name := range header {
+		cols[strings.TrimSpace(name)] = i
+	}
+
+	for _, name := range []string{"opened_at", "closed_at", "symbol", "stake", "profit"} {
+		if _, ok := cols[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	trades := make([]Trade, 0, len(rows))
+
+	for i, row := range rows {
+		t, err := parseTrade(row, cols)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+2, err)
+		}
+
+		trades = append(trades, t)
+	}
+
+	return trades, nil
+}
+
+func parseTrade(row []string, cols map[string]int) (Trade, error) {
+	var (
+		t   = Trade{Symbol: row[cols["symbol"]]}
+		err error
+	)
+
+	if t.OpenedAt, err = time.Parse(time.RFC3339Nano, row[cols["opened_at"]]); err != nil {
+		return Trade{}, fmt.Errorf("invalid opened_at: %w", err)
+	}
+
+	if t.ClosedAt, err = time.Parse(time.RFC3339Nano, row[cols["closed_at"]]); err != nil {
+		return Trade{}, fmt.Errorf("invalid closed_at: %w", err)
+	}
+
+	if t.Stake, err = strconv.ParseFloat(row[cols["stake"]], 64); err != nil {
+		return Trade{}, fmt.Errorf("invalid stake: %w", err)
+	}
+
+	if t.Profit, err = strconv.ParseFloat(row[cols["profit"]], 64); err != nil {
+		return Trade{}, fmt.Errorf("invalid profit: %w", err)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,161 @@
+// Package report computes performance statistics of closed trades and exports them as JSON, CSV and HTML.
+package report
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Trade is a closed trade, from a backtest or live trading.
+type Trade struct {
+	OpenedAt time.Time `json:"opened_at"`
+	ClosedAt time.Time `json:"closed_at"`
+	Symbol   string    `json:"symbol"`
+	Stake    float64   `json:"stake"`
+	Profit   float64   `json:"profit"`
+}
+
+// Point is a value of a curve at the close of a trade.
+type Point struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// Report holds performance statistics of trades. Ratios are computed from per trade returns relative to the stake
+// and are not annualized. ProfitFactor and Calmar are nil when undefined, i.e. without losses or drawdown.
+type Report struct {
+	ProfitFactor        *float64 `json:"profit_factor"`
+	Calmar              *float64 `json:"calmar"`
+	Equity              []Point  `json:"equity"`
+	Drawdown            []Point  `json:"drawdown"`
+	Trades              int      `json:"trades"`
+	Wins                int      `json:"wins"`
+	Losses              int      `json:"losses"`
+	LongestLosingStreak int      `json:"longest_losing_streak"`
+	InitialBalance      float64  `json:"initial_balance"`
+	NetPnL              float64  `json:"net_pnl"`
+	GrossProfit         float64  `json:"gross_profit"`
+	GrossLoss           float64  `json:"gross_loss"`
+	WinRate             float64  `json:"win_rate"`
+	Expectancy          float64  `json:"expectancy"`
+	AverageWin          float64  `json:"average_win"`
+	AverageLoss         float64  `json:"average_loss"`
+	Sharpe              float64  `json:"sharpe"`
+	Sortino             float64  `json:"sortino"`
+	MaxDrawdown         float64  `json:"max_drawdown"`
+}
+
+// New computes the report of trades for an account starting with initialBalance.
+// Trades are ordered by close time, the equity curve starts at initialBalance and
+// the drawdown curve holds the distance of equity from its running peak.
+func New(trades []Trade, initialBalance float64) *Report {
+	trades = append([]Trade(nil), trades...)
+	sortByClose(trades)
+
+	r := &Report{
+		Trades:         len(trades),
+		InitialBalance: initialBalance,
+		Equity:         make([]Point, 0, len(trades)),
+		Drawdown:       make([]Point, 0, len(trades)),
+	}
+
+	equity, peak := initialBalance, initialBalance
+	returns := make([]float64, 0, len(trades))
+	streak := 0
+
+	for _, t := range trades {
+		equity += t.Profit
+		peak = math.Max(peak, equity)
+
+		r.Equity = append(r.Equity, Point{Time: t.ClosedAt, Value: equity})
+		r.Drawdown = append(r.Drawdown, Point{Time: t.ClosedAt, Value: peak - equity})
+		r.MaxDrawdown = math.Max(r.MaxDrawdown, peak-equity)
+
+		if t.Stake > 0 {
+			returns = append(returns, t.Profit/t.Stake)
+		}
+
+		if t.Profit > 0 {
+			r.Wins++
+			r.GrossProfit += t.Profit
+			streak = 0
+
+			continue
+		}
+
+		r.Losses++
+		r.GrossLoss -= t.Profit
+		streak++
+		r.LongestLosingStreak = max(r.LongestLosingStreak, streak)
+	}
+
+	r.NetPnL = r.GrossProfit - r.GrossLoss
+
+	if r.Trades > 0 {
+		r.WinRate = float64(r.Wins) / float64(r.Trades)
+		r.Expectancy = r.NetPnL / float64(r.Trades)
+	}
+
+	if r.Wins > 0 {
+		r.AverageWin = r.GrossProfit / float64(r.Wins)
+	}
+
+	if r.Losses > 0 {
+		r.AverageLoss = r.GrossLoss / float64(r.Losses)
+	}
+
+	if r.GrossLoss > 0 {
+		pf := r.GrossProfit / r.GrossLoss
+		r.ProfitFactor = &pf
+	}
+
+	if r.MaxDrawdown > 0 {
+		calmar := r.NetPnL / r.MaxDrawdown
+		r.Calmar = &calmar
+	}
+
+	r.Sharpe, r.Sortino = ratios(returns)
+
+	return r
+}
+
+// ratios returns the Sharpe and Sortino ratios of returns: the mean return divided by the standard deviation
+// and by the downside deviation respectively. They are 0 with less than two returns or without deviation.
+func ratios(returns []float64) (sharpe, sortino float64) {
+	n := float64(len(returns))
+	if n < 2 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, ret := range returns {
+		sum += ret
+	}
+
+	mean := sum / n
+
+	var variance, downside float64
+
+	for _, ret := range returns {
+		variance += (ret - mean) * (ret - mean)
+
+		if ret < 0 {
+			downside += ret * ret
+		}
+	}
+
+	if sd := math.Sqrt(variance / (n - 1)); sd > 0 {
+		sharpe = mean / sd
+	}
+
+	if dd := math.Sqrt(downside / n); dd > 0 {
+		sortino = mean / dd
+	}
+
+	return sharpe, sortino
+}
+
+func sortByClose(trades []Trade) {
+	sort.SliceStable(trades, func(i, j int) bool { return trades[i].ClosedAt.Before(trades[j].ClosedAt) })
+}
@@ -0,0 +1,107 @@
+package report
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTrades() []Trade {
+	start := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	profits := []float64{20, -10, -5, 15, -10}
+	trades := make([]Trade, len(profits))
+
+	for i, p := range profits {
+		trades[i] = Trade{
+			OpenedAt: start.Add(time.Duration(i) * time.Minute),
+			ClosedAt: start.Add(time.Duration(i)*time.Minute + 30*time.Second),
+			Symbol:   "R_100",
+			Stake:    10,
+			Profit:   p,
+		}
+	}
+
+	// Out of order input is sorted by close time.
+	trades[0], trades[4] = trades[4], trades[0]
+
+	return trades
+}
+
+func TestNew(t *testing.T) {
+	r := New(testTrades(), 100)
+
+	assert.Equal(t, 5, r.Trades)
+	assert.Equal(t, 2, r.Wins)
+	assert.Equal(t, 3, r.Losses)
+	assert.Equal(t, 2, r.LongestLosingStreak)
+	assert.InDelta(t, 10, r.NetPnL, 1e-9)
+	assert.InDelta(t, 0.4, r.WinRate, 1e-9)
+	assert.InDelta(t, 2, r.Expectancy, 1e-9)
+	assert.InDelta(t, 17.5, r.AverageWin, 1e-9)
+	assert.InDelta(t, 25.0/3, r.AverageLoss, 1e-9)
+	assert.InDelta(t, 15, r.MaxDrawdown, 1e-9)
+
+	require.NotNil(t, r.ProfitFactor)
+	assert.InDelta(t, 1.4, *r.ProfitFactor, 1e-9)
+	require.NotNil(t, r.Calmar)
+	assert.InDelta(t, 10.0/15, *r.Calmar, 1e-9)
+
+	// Returns 2, -1, -0.5, 1.5, -1: mean 0.2, sample deviation sqrt(8.3/4), downside deviation sqrt(2.25/5).
+	assert.InDelta(t, 0.2/math.Sqrt(8.3/4), r.Sharpe, 1e-9)
+	assert.InDelta(t, 0.2/math.Sqrt(2.25/5), r.Sortino, 1e-9)
+
+	equity := make([]float64, len(r.Equity))
+	drawdown := make([]float64, len(r.Drawdown))
+
+	for i := range r.Equity {
+		equity[i], drawdown[i] = r.Equity[i].Value, r.Drawdown[i].Value
+	}
+
+	assert.Equal(t, []float64{120, 110, 105, 120, 110}, equity)
+	assert.Equal(t, []float64{0, 10, 15, 0, 10}, drawdown)
+}
+
+func TestNew_Undefined(t *testing.T) {
+	r := New([]Trade{{Stake: 10, Profit: 5}}, 0)
+
+	assert.Nil(t, r.ProfitFactor)
+	assert.Nil(t, r.Calmar)
+	assert.Zero(t, r.Sharpe)
+
+	r = New(nil, 0)
+	assert.Zero(t, r.Trades)
+	assert.Zero(t, r.WinRate)
+}
+
+func TestWrite(t *testing.T) {
+	trades := testTrades()
+	r := New(trades, 100)
+
+	var buf bytes.Buffer
+
+	require.NoError(t, Write(&buf, FormatCSV, r, trades))
+
+	got, err := ReadCSV(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, New(got, 100), r)
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, FormatJSON, r, trades))
+	assert.Contains(t, buf.String(), `"longest_losing_streak": 2`)
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, FormatHTML, r, trades))
+	assert.Contains(t, buf.String(), "<svg")
+	assert.Contains(t, buf.String(), `<path d="M0.0`)
+
+	assert.ErrorContains(t, Write(&buf, "pdf", r, trades), `unknown report format "pdf"`)
+}
+
+func TestReadCSV_MissingColumn(t *testing.T) {
+	_, err := ReadCSV(bytes.NewBufferString("opened_at,closed_at,symbol,stake\n"))
+	assert.ErrorContains(t, err, `missing column "profit"`)
+}
@@ -0,0 +1,168 @@
+// Package rules implements the entry and exit rules strategies use to decide when to open and close positions.
+// Rules keep state between ticks, so a new rule has to be built for every strategy run.
+package rules
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+)
+
+const (
+	// TakeProfit opens a position on the first tick and closes it once the quote moves by take_profit_pct percent
+	// in the direction of the strategy, up for buying and down for selling.
+	TakeProfit = "take_profit"
+	// EMACross opens a position when the fast EMA crosses the slow EMA in the direction of the strategy
+	// and closes it on the opposite cross.
+	EMACross = "ema_cross"
+)
+
+// Names lists the supported rules.
+var Names = []string{TakeProfit, EMACross}
+
+// defaults holds the parameters of every rule with their default values.
+var defaults = map[string]map[string]float64{
+	TakeProfit: {"take_profit_pct": 1},
+	EMACross:   {"fast": 9, "slow": 21},
+}
+
+// Rule decides when a strategy opens and closes its position. WarmUp is the number of ticks the rule needs
+// to see before it can signal.
+type Rule struct {
+	CheckToOpen  func(tick signal.Tick) bool
+	CheckToClose func(tick signal.Tick) bool
+	WarmUp       int
+}
+
+// New builds the rule name with params, missing parameters get default values.
+// short is true for strategies selling the market, which inverts the direction of crosses and of the take profit move.
+// Returns an error if the rule is unknown or a parameter is unknown or out of range.
+func New(name string, params map[string]float64, short bool) (Rule, error) {
+	if name == "" {
+		name = TakeProfit
+	}
+
+	p, ok := defaults[name]
+	if !ok {
+		return Rule{}, fmt.Errorf("unknown rule %q", name)
+	}
+
+	merged := make(map[string]float64, len(p))
+
+	for k, v := range p {
+		merged[k] = v
+	}
+
+	for k, v := range params {
+		if _, ok := p[k]; !ok {
+			return Rule{}, fmt.Errorf("unknown parameter %q of rule %s", k, name)
+		}
+
+		merged[k] = v
+	}
+
+	switch name {
+	case EMACross:
+		return emaCross(merged["fast"], merged["slow"], short)
+	default:
+		return takeProfit(merged["take_profit_pct"], short)
+	}
+}
+
+func takeProfit(pct float64, short bool) (Rule, error) {
+	if pct <= 0 {
+		return Rule{}, fmt.Errorf("take_profit_pct must be positive")
+	}
+
+	var initPrice float64
+
+	return Rule{
+		CheckToOpen: func(tick signal.Tick) bool {
+			initPrice = tick.Quote
+			return true
+		},
+		CheckToClose: func(tick signal.Tick) bool {
+			if short {
+				return tick.Quote < initPrice*(1-pct/100)
+			}
+
+			return tick.Quote > initPrice*(1+pct/100)
+		},
+	}, nil
+}
+
+func emaCross(fast, slow float64, short bool) (Rule, error) {
+	switch {
+	case fast < 1 || fast != math.Trunc(fast):
+		return Rule{}, fmt.Errorf("fast must be a positive integer")
+	case slow <= fast || slow != math.Trunc(slow):
+		return Rule{}, fmt.Errorf("slow must be an integer greater than fast")
+	}
+
+	fastEMA, slowEMA := newEMA(int(fast)), newEMA(int(slow))
+
+	// above reports whether the fast EMA is on the side of the slow one the strategy trades on,
+	// before holds its value at the previous tick. The executor calls exactly one of the checks per tick.
+	var above, before, ready bool
+
+	update := func(tick signal.Tick) {
+		f, s := fastEMA.add(tick.Quote), slowEMA.add(tick.Quote)
+
+		before = above
+		above = f > s
+
+		if short {
+			above = f < s
+		}
+
+		if slowEMA.warm() && !ready {
+			ready = true
+			before = above
+		}
+	}
+
+	return Rule{
+		CheckToOpen: func(tick signal.Tick) bool {
+			update(tick)
+			return ready && above && !before
+		},
+		CheckToClose: func(tick signal.Tick) bool {
+			update(tick)
+			return ready && !above && before
+		},
+		WarmUp: int(slow),
+	}, nil
+}
+
+// ema is an exponential moving average seeded with the simple average of the first period values.
+type ema struct {
+	period int
+	count  int
+	sum    float64
+	value  float64
+}
+
+func newEMA(period int) *ema {
+	return &ema{period: period}
+}
+
+func (e *ema) add(v float64) float64 {
+	e.count++
+
+	if e.count <= e.period {
+		e.sum += v
+		e.value = e.sum / float64(e.count)
+
+		return e.value
+	}
+
+	k := 2 / float64(e.period+1)
+	e.value += k * (v - e.value)
+
+	return e.value
+}
+
+func (e *ema) warm() bool {
+	return e.count >= e.period
+}
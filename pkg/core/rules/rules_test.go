@@ -0,0 +1,105 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_Invalid(t *testing.T) {
+	tests := []struct {
+		params  map[string]float64
+		name    string
+		rule    string
+		wantErr string
+	}{
+		{name: "unknown rule", rule: "rsi", wantErr: `unknown rule "rsi"`},
+		{name: "unknown parameter", rule: EMACross, params: map[string]float64{"period": 3}, wantErr: `unknown parameter "period"`},
+		{name: "fractional period", rule: EMACross, params: map[string]float64{"fast": 2.5}, wantErr: "fast must be a positive integer"},
+		{name: "slow not above fast", rule: EMACross, params: map[string]float64{"fast": 20, "slow": 20}, wantErr: "slow must be an integer greater than fast"},
+		{name: "negative take profit", rule: TakeProfit, params: map[string]float64{"take_profit_pct": -1}, wantErr: "take_profit_pct must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.rule, tt.params, false)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestTakeProfit(t *testing.T) {
+	tests := []struct {
+		name      string
+		quotes    []float64
+		wantClose []bool
+		short     bool
+	}{
+		{name: "buy", quotes: []float64{101.5, 98, 102.5}, wantClose: []bool{false, false, true}},
+		{name: "sell", quotes: []float64{98.5, 102.5, 97.5}, wantClose: []bool{false, false, true}, short: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := New("", map[string]float64{"take_profit_pct": 2}, tt.short)
+			require.NoError(t, err)
+
+			assert.True(t, rule.CheckToOpen(signal.Tick{Quote: 100}))
+
+			for i, quote := range tt.quotes {
+				assert.Equal(t, tt.wantClose[i], rule.CheckToClose(signal.Tick{Quote: quote}), "quote %v", quote)
+			}
+		})
+	}
+}
+
+func TestEMACross(t *testing.T) {
+	tests := []struct {
+		name  string
+		short bool
+	}{
+		{name: "long"},
+		{name: "short", short: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := New(EMACross, map[string]float64{"fast": 2, "slow": 4}, tt.short)
+			require.NoError(t, err)
+
+			// Flat market, then a rally opens long positions and a sell-off opens short ones.
+			first, second := []float64{110, 120, 130, 140}, []float64{90, 80, 70, 60}
+			if tt.short {
+				first, second = second, first
+			}
+
+			for _, q := range []float64{100, 100, 100, 100} {
+				assert.False(t, rule.CheckToOpen(signal.Tick{Quote: q}))
+			}
+
+			opened := false
+
+			for _, q := range first {
+				if rule.CheckToOpen(signal.Tick{Quote: q}) {
+					opened = true
+					break
+				}
+			}
+
+			require.True(t, opened)
+
+			closed := false
+
+			for _, q := range second {
+				if rule.CheckToClose(signal.Tick{Quote: q}) {
+					closed = true
+					break
+				}
+			}
+
+			assert.True(t, closed)
+		})
+	}
+}
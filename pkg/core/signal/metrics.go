@@ -0,0 +1,51 @@
+package signal
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	ticksTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "deriv_bot",
+		Name:      "ticks_total",
+		Help:      "Number of ticks received per symbol.",
+	}, []string{"symbol"})
+
+	tickLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "deriv_bot",
+		Name:      "tick_latency_seconds",
+		Help:      "Delay between the tick time and its delivery to the bot per symbol.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 5},
+	}, []string{"symbol"})
+
+	tickIssues = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "deriv_bot",
+		Name:      "tick_issues_total",
+		Help:      "Number of tick data quality issues per symbol and issue.",
+	}, []string{"symbol", "issue"})
+
+	staleSymbols = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "deriv_bot",
+		Name:      "market_data_stale",
+		Help:      "Whether no tick arrived for the symbol within the stale threshold, 1 when stale.",
+	}, []string{"symbol"})
+
+	ticksDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "deriv_bot",
+		Name:      "ticks_dropped_total",
+		Help:      "Number of ticks dropped from a full provider or subscriber queue per symbol.",
+	}, []string{"symbol"})
+
+	ticksConflated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "deriv_bot",
+		Name:      "ticks_conflated_total",
+		Help:      "Number of ticks replaced by a newer one before delivery in conflation mode per symbol.",
+	}, []string{"symbol"})
+
+	activeSubscriptions = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "deriv_bot",
+		Name:      "market_subscriptions",
+		Help:      "Number of active market data subscriptions.",
+	})
+)
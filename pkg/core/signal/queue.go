@@ -0,0 +1,64 @@
+package signal
+
+// defaultTickBuffer is the number of queued ticks when QueueConfig.Buffer is not set.
+const defaultTickBuffer = 100
+
+// QueueConfig sets how ticks are queued while their consumer is busy. Buffer is the number of queued ticks,
+// the oldest one is dropped when the queue is full. With Conflate only the latest tick is kept.
+type QueueConfig struct {
+	Buffer   int
+	Conflate bool
+}
+
+// TickQueue holds ticks of a symbol not yet taken by their consumer, so the producer is never blocked by a slow
+// consumer. Dropped and conflated ticks are counted per symbol. It is not safe for concurrent use.
+type TickQueue struct {
+	symbol string
+	ticks  []Tick
+	size   int
+	// conflate keeps only the latest tick.
+	conflate bool
+}
+
+// NewTickQueue creates a queue of the ticks of symbol as configured by cfg.
+func NewTickQueue(symbol string, cfg QueueConfig) *TickQueue {
+	size := cfg.Buffer
+	if size <= 0 {
+		size = defaultTickBuffer
+	}
+
+	if cfg.Conflate {
+		size = 1
+	}
+
+	return &TickQueue{symbol: symbol, size: size, conflate: cfg.Conflate, ticks: make([]Tick, 0, size)}
+}
+
+// Push queues tick. A full queue drops its oldest tick, or replaces it with tick in conflation mode.
+func (q *TickQueue) Push(tick Tick) {
+	if len(q.ticks) >= q.size {
+		if q.conflate {
+			ticksConflated.WithLabelValues(q.symbol).Inc()
+		} else {
+			ticksDropped.WithLabelValues(q.symbol).Inc()
+		}
+
+		q.ticks = q.ticks[1:]
+	}
+
+	q.ticks = append(q.ticks, tick)
+}
+
+// Peek returns the oldest queued tick and false if the queue is empty.
+func (q *TickQueue) Peek() (Tick, bool) {
+	if len(q.ticks) == 0 {
+		return Tick{}, false
+	}
+
+	return q.ticks[0], true
+}
+
+// Pop removes the oldest queued tick.
+func (q *TickQueue) Pop() {
+	q.ticks = q.ticks[1:]
+}
@@ -0,0 +1,38 @@
+package signal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTickQueue(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  QueueConfig
+		want []float64
+	}{
+		{name: "queued in order", cfg: QueueConfig{Buffer: 5}, want: []float64{1, 2, 3}},
+		{name: "oldest dropped when full", cfg: QueueConfig{Buffer: 2}, want: []float64{2, 3}},
+		{name: "conflated to the latest", cfg: QueueConfig{Buffer: 5, Conflate: true}, want: []float64{3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewTickQueue("R_100", tt.cfg)
+
+			for _, quote := range []float64{1, 2, 3} {
+				q.Push(Tick{Quote: quote})
+			}
+
+			var got []float64
+
+			for tick, ok := q.Peek(); ok; tick, ok = q.Peek() {
+				got = append(got, tick.Quote)
+				q.Pop()
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package signal
+
+import (
+	"context"
+	"sync"
+)
+
+// Subscription is a provider subscription of a symbol shared by all of its subscribers.
+// Every subscriber receives every tick, subscribers join with SubscribeOnMarket and leave when their context is
+// cancelled.
+type Subscription struct {
+	joins  chan *subscriber
+	leaves chan *subscriber
+	swap   chan source
+	done   chan struct{}
+	symbol string
+	queue  QueueConfig
+}
+
+// subscriber is a consumer of the ticks of a subscription. Ticks are queued while the subscriber is busy, so a
+// slow subscriber never holds back the others. gone is closed once the subscriber left, stop once the subscription
+// stopped feeding it.
+type subscriber struct {
+	ticks  chan Tick
+	gone   <-chan struct{}
+	stop   chan struct{}
+	notify chan struct{}
+	queue  *TickQueue
+	mu     sync.Mutex
+}
+
+func newSubscription(symbol string, queue QueueConfig) *Subscription {
+	return &Subscription{
+		joins:  make(chan *subscriber),
+		leaves: make(chan *subscriber),
+		swap:   make(chan source),
+		done:   make(chan struct{}),
+		symbol: symbol,
+		queue:  queue,
+	}
+}
+
+// join adds a subscriber which stays until ctx is cancelled.
+// Returns the channel of its ticks and false if the subscription has ended.
+func (s *Subscription) join(ctx context.Context) (<-chan Tick, bool) {
+	sb := &subscriber{
+		ticks:  make(chan Tick),
+		gone:   ctx.Done(),
+		stop:   make(chan struct{}),
+		notify: make(chan struct{}, 1),
+		queue:  NewTickQueue(s.symbol, s.queue),
+	}
+
+	select {
+	case s.joins <- sb:
+	case <-s.done:
+		return nil, false
+	}
+
+	go sb.deliver()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			select {
+			case s.leaves <- sb:
+			case <-s.done:
+			}
+		case <-s.done:
+		}
+	}()
+
+	return sb.ticks, true
+}
+
+// ended reports whether the subscription has ended.
+func (s *Subscription) ended() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// push queues tick for the subscriber without blocking.
+func (sb *subscriber) push(tick Tick) {
+	sb.mu.Lock()
+	sb.queue.Push(tick)
+	sb.mu.Unlock()
+
+	select {
+	case sb.notify <- struct{}{}:
+	default:
+	}
+}
+
+// next takes the oldest queued tick, false if the queue is empty.
+func (sb *subscriber) next() (Tick, bool) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	tick, ok := sb.queue.Peek()
+	if ok {
+		sb.queue.Pop()
+	}
+
+	return tick, ok
+}
+
+// deliver sends queued ticks to the subscriber until it leaves, or until the subscription stopped feeding it and
+// the queue is drained. The ticks channel is closed then.
+func (sb *subscriber) deliver() {
+	defer close(sb.ticks)
+
+	for {
+		tick, ok := sb.next()
+		if !ok {
+			select {
+			case <-sb.notify:
+				continue
+			case <-sb.stop:
+				// A tick may be queued right before the subscriber was stopped.
+				if tick, ok = sb.next(); !ok {
+					return
+				}
+			case <-sb.gone:
+				return
+			}
+		}
+
+		select {
+		case sb.ticks <- tick:
+		case <-sb.gone:
+			return
+		}
+	}
+}
@@ -3,7 +3,10 @@ package signal
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"time"
 
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
 	"golang.org/x/sync/singleflight"
 )
 
@@ -12,61 +15,279 @@ type MarketProvider interface {
 }
 
 type SubscribtionManager interface {
-	GetMarketSubscription(symbol string) (<-chan Tick, bool)
-	SetMarketSubscription(symbol string, sub <-chan Tick)
+	GetMarketSubscription(symbol string) (*Subscription, bool)
+	SetMarketSubscription(symbol string, sub *Subscription)
+	RemoveMarketSubscription(symbol string, sub *Subscription)
+}
+
+type Publisher interface {
+	Publish(e event.Event)
+}
+
+// TickRecorder receives every tick delivered to subscribers, it must not block.
+type TickRecorder interface {
+	Record(symbol string, tick Tick)
 }
 
 type Service struct {
 	markerProv MarketProvider
 	subMgr     SubscribtionManager
+	events     Publisher
+	recorder   TickRecorder
+	watchdog   WatchdogConfig
+	queue      QueueConfig
 	fg         singleflight.Group
 }
 
+// source is a provider subscription feeding the ticks of a symbol, cancel ends the subscription.
+type source struct {
+	ticks  <-chan Tick
+	cancel context.CancelFunc
+}
+
 // New creates and initializes a new Service instance with the provided MarketProvider.
 // It requires a valid prov implementing the MarketProvider interface.
+// events receives a tick_received event for every tick delivered by the provider.
 // Returns a pointer to the newly created Service.
-func New(prov MarketProvider, subMgr SubscribtionManager) *Service {
+func New(prov MarketProvider, subMgr SubscribtionManager, events Publisher) *Service {
 	return &Service{
 		markerProv: prov,
 		subMgr:     subMgr,
+		events:     events,
 	}
 }
 
+// SetRecorder taps market subscriptions, so rec receives the ticks of every subscription created afterwards.
+func (s *Service) SetRecorder(rec TickRecorder) {
+	s.recorder = rec
+}
+
+// SetWatchdog sets the thresholds of data quality checks of subscriptions created afterwards.
+func (s *Service) SetWatchdog(cfg WatchdogConfig) {
+	s.watchdog = cfg
+}
+
+// SetQueue sets how ticks are queued for subscribers busy with earlier ticks, for subscriptions created afterwards.
+func (s *Service) SetQueue(cfg QueueConfig) {
+	s.queue = cfg
+}
+
 // SubscribeOnMarket subscribes to real-time market updates for the specified symbol and provides ticks via a channel.
-// It connects to the underlying market provider and streams tick data to subscribers.
-// ctx is the context to control cancellation or timeout, and symbol specifies the market symbol of interest.
-// Returns a read-only channel streaming Tick updates and an error if the subscription fails.
+// Subscribers of a symbol share one provider subscription and every one of them receives every tick. Ticks are queued
+// per subscriber while it's busy, a full queue drops the oldest tick or keeps only the latest one, as set by SetQueue.
+// ctx is the context of the subscriber: it bounds the call and unsubscribes once cancelled, and the provider
+// subscription ends with its last subscriber. symbol specifies the market symbol of interest.
+// Returns a read-only channel streaming Tick updates, closed when the subscriber leaves or the provider subscription
+// ends, and an error if the subscription fails.
 func (s *Service) SubscribeOnMarket(ctx context.Context, symbol string) (<-chan Tick, error) {
-	res := s.fg.DoChan(symbol, func() (interface{}, error) {
-		s.subMgr.GetMarketSubscription(symbol)
-
+	for {
 		if sub, ok := s.subMgr.GetMarketSubscription(symbol); ok {
-			return sub, nil
+			if ticks, ok := sub.join(ctx); ok {
+				return ticks, nil
+			}
 		}
 
-		tickChan, err := s.markerProv.SubscribeToTicks(ctx, symbol)
-		if err != nil {
-			return nil, fmt.Errorf("failed to subscribe to ticks for symbol %s: %w", symbol, err)
+		res := s.fg.DoChan(symbol, func() (interface{}, error) {
+			if sub, ok := s.subMgr.GetMarketSubscription(symbol); ok && !sub.ended() {
+				return sub, nil
+			}
+
+			src, err := s.subscribe(ctx, symbol)
+			if err != nil {
+				return nil, err
+			}
+
+			sub := s.observe(symbol, src)
+			s.subMgr.SetMarketSubscription(symbol, sub)
+
+			return sub, nil
+		})
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case res := <-res:
+			if res.Err != nil {
+				return nil, fmt.Errorf("failed to subscribe to market %s: %w", symbol, res.Err)
+			}
+
+			sub, ok := res.Val.(*Subscription)
+			if !ok {
+				return nil, fmt.Errorf("unexpected type for subscription of symbol %s", symbol)
+			}
+
+			// A subscription ending before it's joined is replaced on the next attempt.
+			if ticks, ok := sub.join(ctx); ok {
+				return ticks, nil
+			}
+
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
 		}
+	}
+}
 
-		s.subMgr.SetMarketSubscription(symbol, tickChan)
+// Resubscribe replaces the provider subscription of symbol with a new one, e.g. when its tick stream stalled.
+// Subscribers keep receiving ticks from the channel returned by SubscribeOnMarket.
+// Returns an error if symbol has no subscription or the new subscription fails.
+func (s *Service) Resubscribe(ctx context.Context, symbol string) error {
+	sub, ok := s.subMgr.GetMarketSubscription(symbol)
+	if !ok {
+		return fmt.Errorf("no market subscription for symbol %s", symbol)
+	}
 
-		return tickChan, nil
-	})
+	src, err := s.subscribe(ctx, symbol)
+	if err != nil {
+		return err
+	}
 
 	select {
+	case sub.swap <- src:
+		return nil
+	case <-sub.done:
+		src.cancel()
+		return fmt.Errorf("no market subscription for symbol %s", symbol)
 	case <-ctx.Done():
-		return nil, ctx.Err()
-	case res := <-res:
-		if res.Err != nil {
-			return nil, fmt.Errorf("failed to subscribe to market %s: %w", symbol, res.Err)
+		src.cancel()
+		return ctx.Err()
+	}
+}
+
+// subscribe opens a provider subscription of symbol. It outlives ctx, which only bounds the request,
+// the subscription lasts until the returned source is cancelled.
+func (s *Service) subscribe(ctx context.Context, symbol string) (source, error) {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	ticks, err := s.markerProv.SubscribeToTicks(ctx, symbol)
+	if err != nil {
+		cancel()
+		return source{}, fmt.Errorf("failed to subscribe to ticks for symbol %s: %w", symbol, err)
+	}
+
+	return source{ticks: ticks, cancel: cancel}, nil
+}
+
+// observe forwards ticks from src to the queues of the subscribers of the returned subscription, so a slow subscriber
+// never holds back the others. It records tick rate, latency and subscription metrics, publishes tick_received
+// events and passes ticks to the recorder, if any.
+// Ticks are checked by the watchdog: out of order ticks and unconfirmed outliers are dropped, and issues,
+// including a stream without ticks for the stale threshold, are published as data_quality events.
+// A source received on the swap channel of the subscription replaces the current one.
+// The subscription ends when the ticks of the current source end or its last subscriber leaves, it's removed
+// from the subscription manager then, so the next subscriber of symbol starts a new one.
+func (s *Service) observe(symbol string, src source) *Subscription {
+	sub := newSubscription(symbol, s.queue)
+	wd := newWatchdog(s.watchdog)
+
+	activeSubscriptions.Inc()
+
+	go func() {
+		subscribers := make(map[*subscriber]struct{})
+
+		defer activeSubscriptions.Dec()
+		defer func() {
+			src.cancel()
+			s.subMgr.RemoveMarketSubscription(symbol, sub)
+			close(sub.done)
+
+			for sb := range subscribers {
+				close(sb.stop)
+			}
+		}()
+
+		stale := time.NewTimer(wd.cfg.StaleAfter)
+		defer stale.Stop()
+
+		replace := func(next source) {
+			src.cancel()
+			src = next
+
+			slog.Info("Market subscription replaced", slog.String("symbol", symbol))
 		}
 
-		tickChan, ok := res.Val.(<-chan Tick)
-		if !ok {
-			return nil, fmt.Errorf("unexpected type for tick channel for symbol %s", symbol)
+		// leave drops sb and reports whether the subscription has subscribers left.
+		leave := func(sb *subscriber) bool {
+			if _, ok := subscribers[sb]; ok {
+				delete(subscribers, sb)
+				close(sb.stop)
+			}
+
+			return len(subscribers) > 0
 		}
 
-		return tickChan, nil
-	}
+		for {
+			select {
+			case sb := <-sub.joins:
+				subscribers[sb] = struct{}{}
+			case sb := <-sub.leaves:
+				if !leave(sb) {
+					return
+				}
+			case next := <-sub.swap:
+				replace(next)
+			case <-stale.C:
+				wd.stale = true
+
+				staleSymbols.WithLabelValues(symbol).Set(1)
+				s.reportIssue(symbol, IssueStale, fmt.Sprintf("no ticks for %s", wd.cfg.StaleAfter))
+			case tick, ok := <-src.ticks:
+				if !ok {
+					return
+				}
+
+				ticksTotal.WithLabelValues(symbol).Inc()
+				tickLatency.WithLabelValues(symbol).Observe(time.Since(tick.Time).Seconds())
+				stale.Reset(wd.cfg.StaleAfter)
+
+				if wd.stale {
+					wd.stale = false
+
+					staleSymbols.WithLabelValues(symbol).Set(0)
+					s.reportIssue(symbol, IssueRecovered, "ticks resumed")
+				}
+
+				accepted, issue, details := wd.check(tick)
+				if issue != "" {
+					s.reportIssue(symbol, issue, details)
+				}
+
+				if !accepted {
+					continue
+				}
+
+				s.events.Publish(event.New(event.TypeTickReceived, event.TickReceived{
+					Time:   tick.Time,
+					Symbol: symbol,
+					Quote:  tick.Quote,
+					Ask:    tick.Ask,
+					Bid:    tick.Bid,
+				}))
+
+				if s.recorder != nil {
+					s.recorder.Record(symbol, tick)
+				}
+
+				for sb := range subscribers {
+					sb.push(tick)
+				}
+			}
+		}
+	}()
+
+	return sub
+}
+
+// reportIssue counts, logs and publishes a data quality issue of symbol.
+func (s *Service) reportIssue(symbol, issue, details string) {
+	tickIssues.WithLabelValues(symbol, issue).Inc()
+
+	slog.Warn("Market data issue", slog.String("symbol", symbol), slog.String("issue", issue), slog.String("details", details))
+
+	s.events.Publish(event.New(event.TypeDataQuality, event.DataQuality{
+		Time:    time.Now(),
+		Symbol:  symbol,
+		Issue:   issue,
+		Details: details,
+	}))
 }
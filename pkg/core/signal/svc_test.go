@@ -0,0 +1,272 @@
+package signal
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider hands out a new tick channel for every subscription.
+type fakeProvider struct {
+	subs []chan Tick
+	ctxs []context.Context
+	mu   sync.Mutex
+}
+
+func (p *fakeProvider) SubscribeToTicks(ctx context.Context, _ string) (<-chan Tick, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ch := make(chan Tick)
+	p.subs = append(p.subs, ch)
+	p.ctxs = append(p.ctxs, ctx)
+
+	return ch, nil
+}
+
+func (p *fakeProvider) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.subs)
+}
+
+func (p *fakeProvider) ctx(i int) context.Context {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.ctxs[i]
+}
+
+func (p *fakeProvider) sub(i int) chan Tick {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.subs[i]
+}
+
+type fakeSubs struct {
+	subs map[string]*Subscription
+	mu   sync.Mutex
+}
+
+func (s *fakeSubs) GetMarketSubscription(symbol string) (*Subscription, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, ok := s.subs[symbol]
+
+	return sub, ok
+}
+
+func (s *fakeSubs) SetMarketSubscription(symbol string, sub *Subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.subs[symbol] = sub
+}
+
+func (s *fakeSubs) RemoveMarketSubscription(symbol string, sub *Subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.subs[symbol] == sub {
+		delete(s.subs, symbol)
+	}
+}
+
+func (s *fakeSubs) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.subs)
+}
+
+// receive reads a tick from every channel in any order, subscribers are fed one after another.
+// Returns the quotes in the order of chans, zero for a channel without a tick within a second.
+func receive(chans ...<-chan Tick) []float64 {
+	quotes := make([]float64, len(chans))
+
+	var wg sync.WaitGroup
+
+	for i, ch := range chans {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			select {
+			case tick := <-ch:
+				quotes[i] = tick.Quote
+			case <-time.After(time.Second):
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return quotes
+}
+
+func TestService_StaleAndResubscribe(t *testing.T) {
+	prov := &fakeProvider{}
+	bus := event.NewBus()
+	svc := New(prov, &fakeSubs{subs: make(map[string]*Subscription)}, bus)
+	svc.SetWatchdog(WatchdogConfig{StaleAfter: 50 * time.Millisecond})
+
+	issues, unsubscribe := bus.Subscribe(event.TypeDataQuality)
+	defer unsubscribe()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ticks, err := svc.SubscribeOnMarket(ctx, "R_100")
+	require.NoError(t, err)
+
+	now := time.Now()
+	prov.sub(0) <- Tick{Time: now, Quote: 100}
+	assert.InDelta(t, 100, (<-ticks).Quote, 0)
+
+	stale := (<-issues).Payload.(event.DataQuality)
+	assert.Equal(t, "R_100", stale.Symbol)
+	assert.Equal(t, IssueStale, stale.Issue)
+
+	require.NoError(t, svc.Resubscribe(ctx, "R_100"))
+
+	// The new subscription feeds the channel subscribers already hold.
+	prov.sub(1) <- Tick{Time: now.Add(time.Second), Quote: 101}
+	assert.InDelta(t, 101, (<-ticks).Quote, 0)
+	assert.Equal(t, IssueRecovered, (<-issues).Payload.(event.DataQuality).Issue)
+
+	assert.Error(t, svc.Resubscribe(ctx, "R_50"))
+}
+
+func TestService_SharedSubscription(t *testing.T) {
+	prov := &fakeProvider{}
+	subs := &fakeSubs{subs: make(map[string]*Subscription)}
+	svc := New(prov, subs, event.NewBus())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	firstCtx, leaveFirst := context.WithCancel(ctx)
+
+	first, err := svc.SubscribeOnMarket(firstCtx, "R_100")
+	require.NoError(t, err)
+
+	second, err := svc.SubscribeOnMarket(ctx, "R_100")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, prov.count())
+
+	// Every subscriber receives every tick.
+	now := time.Now()
+	for i, quote := range []float64{100, 101} {
+		prov.sub(0) <- Tick{Time: now.Add(time.Duration(i) * time.Second), Quote: quote}
+		assert.Equal(t, []float64{quote, quote}, receive(first, second))
+	}
+
+	// The provider subscription outlives the subscriber which created it.
+	leaveFirst()
+
+	_, ok := <-first
+	assert.False(t, ok)
+
+	prov.sub(0) <- Tick{Time: now.Add(2 * time.Second), Quote: 102}
+	assert.InDelta(t, 102, (<-second).Quote, 0)
+	assert.NoError(t, prov.ctx(0).Err())
+
+	// Rejoining after leaving gets ticks of the same provider subscription.
+	first, err = svc.SubscribeOnMarket(ctx, "R_100")
+	require.NoError(t, err)
+
+	prov.sub(0) <- Tick{Time: now.Add(3 * time.Second), Quote: 103}
+	assert.Equal(t, []float64{103, 103}, receive(first, second))
+	assert.Equal(t, 1, prov.count())
+}
+
+func TestService_SlowSubscriber(t *testing.T) {
+	prov := &fakeProvider{}
+	subs := &fakeSubs{subs: make(map[string]*Subscription)}
+	svc := New(prov, subs, event.NewBus())
+	svc.SetQueue(QueueConfig{Conflate: true})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	fast, err := svc.SubscribeOnMarket(ctx, "R_100")
+	require.NoError(t, err)
+
+	slow, err := svc.SubscribeOnMarket(ctx, "R_100")
+	require.NoError(t, err)
+
+	// The slow subscriber doesn't hold back ticks of the fast one.
+	now := time.Now()
+	for i, quote := range []float64{100, 101, 102} {
+		prov.sub(0) <- Tick{Time: now.Add(time.Duration(i) * time.Second), Quote: quote}
+		assert.InDelta(t, quote, (<-fast).Quote, 0)
+	}
+
+	// Ticks queued for the slow subscriber are conflated to the latest one.
+	var got []float64
+
+	for len(got) == 0 || got[len(got)-1] != 102 {
+		select {
+		case tick := <-slow:
+			got = append(got, tick.Quote)
+		case <-ctx.Done():
+			require.Fail(t, "latest tick not delivered", got)
+		}
+	}
+
+	assert.Less(t, len(got), 3)
+}
+
+func TestService_SubscriptionEnds(t *testing.T) {
+	prov := &fakeProvider{}
+	subs := &fakeSubs{subs: make(map[string]*Subscription)}
+	svc := New(prov, subs, event.NewBus())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	subCtx, leave := context.WithCancel(ctx)
+
+	ticks, err := svc.SubscribeOnMarket(subCtx, "R_100")
+	require.NoError(t, err)
+
+	// The last subscriber leaving ends the provider subscription and removes it from the cache.
+	leave()
+
+	_, ok := <-ticks
+	assert.False(t, ok)
+
+	require.Eventually(t, func() bool { return subs.len() == 0 }, time.Second, 5*time.Millisecond)
+	assert.Error(t, prov.ctx(0).Err())
+
+	ticks, err = svc.SubscribeOnMarket(ctx, "R_100")
+	require.NoError(t, err)
+	assert.Equal(t, 2, prov.count())
+
+	prov.sub(1) <- Tick{Time: time.Now(), Quote: 100}
+	assert.InDelta(t, 100, (<-ticks).Quote, 0)
+
+	// The provider stream ending closes the channels of subscribers, the next subscriber starts a new one.
+	close(prov.sub(1))
+
+	_, ok = <-ticks
+	assert.False(t, ok)
+
+	require.Eventually(t, func() bool { return subs.len() == 0 }, time.Second, 5*time.Millisecond)
+
+	ticks, err = svc.SubscribeOnMarket(ctx, "R_100")
+	require.NoError(t, err)
+
+	prov.sub(2) <- Tick{Time: time.Now(), Quote: 101}
+	assert.InDelta(t, 101, (<-ticks).Quote, 0)
+}
@@ -0,0 +1,89 @@
+package signal
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Data quality issues reported by the watchdog.
+const (
+	IssueStale      = "stale"
+	IssueRecovered  = "recovered"
+	IssueOutOfOrder = "out_of_order"
+	IssueOutlier    = "outlier"
+)
+
+// outlierConfirmations is the number of consecutive ticks beyond MaxJump after which the quote is taken
+// as a new price level instead of a bad print.
+const outlierConfirmations = 2
+
+// defaultWatchdog is used for zero fields of the watchdog config.
+var defaultWatchdog = WatchdogConfig{
+	StaleAfter: time.Minute,
+	MaxJump:    0.1,
+}
+
+// WatchdogConfig sets the thresholds of tick data quality checks. A symbol is stale when no tick arrived
+// for StaleAfter, a quote is an outlier when it differs from the previous one by more than MaxJump,
+// a fraction of the previous quote. Zero values are replaced with the defaults.
+type WatchdogConfig struct {
+	StaleAfter time.Duration `mapstructure:"stale_after"`
+	MaxJump    float64       `mapstructure:"max_jump"`
+}
+
+// watchdog checks ticks of a single symbol. It is only used by the goroutine forwarding the ticks of the symbol.
+type watchdog struct {
+	last     Tick
+	cfg      WatchdogConfig
+	outliers int
+	stale    bool
+}
+
+func newWatchdog(cfg WatchdogConfig) *watchdog {
+	if cfg.StaleAfter == 0 {
+		cfg.StaleAfter = defaultWatchdog.StaleAfter
+	}
+
+	if cfg.MaxJump == 0 {
+		cfg.MaxJump = defaultWatchdog.MaxJump
+	}
+
+	return &watchdog{cfg: cfg}
+}
+
+// check validates tick against the previous accepted one. Out of order ticks are rejected, as well as outliers
+// until they are confirmed by consecutive ticks.
+// Returns whether tick is accepted and the issue with a description, if any.
+func (w *watchdog) check(tick Tick) (accepted bool, issue, details string) {
+	if w.last.Time.IsZero() {
+		w.last = tick
+		return true, "", ""
+	}
+
+	if tick.Time.Before(w.last.Time) {
+		return false, IssueOutOfOrder, fmt.Sprintf("tick at %s after tick at %s",
+			tick.Time.Format(time.RFC3339), w.last.Time.Format(time.RFC3339))
+	}
+
+	if w.last.Quote != 0 {
+		jump := math.Abs(tick.Quote-w.last.Quote) / math.Abs(w.last.Quote)
+
+		if jump > w.cfg.MaxJump {
+			w.outliers++
+			details = fmt.Sprintf("quote %g after %g, %.1f%% jump", tick.Quote, w.last.Quote, jump*100)
+
+			if w.outliers < outlierConfirmations {
+				return false, IssueOutlier, details
+			}
+
+			details += ", confirmed as a new level"
+			issue = IssueOutlier
+		}
+	}
+
+	w.outliers = 0
+	w.last = tick
+
+	return true, issue, details
+}
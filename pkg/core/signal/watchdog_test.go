@@ -0,0 +1,66 @@
+package signal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchdog_Check(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type step struct {
+		quote     float64
+		sec       int
+		wantIssue string
+		accepted  bool
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name:  "regular ticks",
+			steps: []step{{quote: 100, sec: 0, accepted: true}, {quote: 101, sec: 1, accepted: true}, {quote: 101, sec: 1, accepted: true}},
+		},
+		{
+			name: "out of order tick is dropped",
+			steps: []step{
+				{quote: 100, sec: 2, accepted: true},
+				{quote: 101, sec: 1, wantIssue: IssueOutOfOrder},
+				{quote: 101, sec: 3, accepted: true},
+			},
+		},
+		{
+			name: "single bad print is dropped",
+			steps: []step{
+				{quote: 100, sec: 0, accepted: true},
+				{quote: 150, sec: 1, wantIssue: IssueOutlier},
+				{quote: 100.5, sec: 2, accepted: true},
+			},
+		},
+		{
+			name: "jump confirmed by the next tick is a new level",
+			steps: []step{
+				{quote: 100, sec: 0, accepted: true},
+				{quote: 150, sec: 1, wantIssue: IssueOutlier},
+				{quote: 151, sec: 2, wantIssue: IssueOutlier, accepted: true},
+				{quote: 152, sec: 3, accepted: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wd := newWatchdog(WatchdogConfig{})
+
+			for i, st := range tt.steps {
+				accepted, issue, _ := wd.check(Tick{Time: start.Add(time.Duration(st.sec) * time.Second), Quote: st.quote})
+				assert.Equal(t, st.accepted, accepted, "step %d", i)
+				assert.Equal(t, st.wantIssue, issue, "step %d", i)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package deriv
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"sync"
 
 	"github.com/ksysoev/deriv-api"
@@ -15,40 +17,147 @@ const (
 )
 
 type Config struct {
-	Endpoint string `mapstructure:"endpoint"`
-	Origin   string `mapstructure:"origin"`
-	AppID    int    `mapstructure:"app_id"`
+	Endpoint   string      `mapstructure:"endpoint"`
+	Origin     string      `mapstructure:"origin"`
+	RateLimits RateLimits  `mapstructure:"rate_limits"`
+	Ticks      TicksConfig `mapstructure:"ticks"`
+	AppID      int         `mapstructure:"app_id"`
 }
 
 type API struct {
-	client *deriv.Client
-	wg     sync.WaitGroup
+	client     *deriv.Client
+	trading    *budget
+	marketData *budget
+	sessions   map[string]*API
+	cfg        Config
+	ticks      TicksConfig
+	wg         sync.WaitGroup
+	mu         sync.Mutex
 }
 
 // New creates a new API instance using the provided configuration.
 // It validates the configuration and initializes a Deriv API client.
+// Requests are throttled on the client side by the trading and market data budgets of cfg.RateLimits.
 // Returns the initialized API instance and an error if client creation fails or the configuration is invalid.
 func New(cfg Config) (*API, error) {
-	client, err := deriv.NewDerivAPI(cfg.Endpoint, cfg.AppID, defaultLanguage, cfg.Origin, deriv.Debug)
+	client, err := newClient(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Deriv API client: %w", err)
+		return nil, err
 	}
 
-	return &API{client: client}, nil
+	return &API{
+		client:     client,
+		cfg:        cfg,
+		trading:    newBudget(budgetTrading, cfg.RateLimits.Trading, defaultRateLimits.Trading),
+		marketData: newBudget(budgetMarketData, cfg.RateLimits.MarketData, defaultRateLimits.MarketData),
+		sessions:   make(map[string]*API),
+		ticks:      cfg.Ticks,
+	}, nil
 }
 
 // Close releases all resources associated with the API instance.
-// It disconnects the underlying client and should be called to clean up properly.
-// Returns an error if disconnection fails.
+// It disconnects the underlying client and the clients of trading sessions, and should be called to clean up properly.
 func (a *API) Close() {
-	a.client.Disconnect()
+	a.mu.Lock()
+	for _, sess := range a.sessions {
+		sess.Close()
+	}
+	a.mu.Unlock()
+
+	a.conn().Disconnect()
 	a.wg.Wait()
 }
 
+// newClient creates a Deriv API client for cfg, it connects on the first request.
+func newClient(cfg Config) (*deriv.Client, error) {
+	client, err := deriv.NewDerivAPI(cfg.Endpoint, cfg.AppID, defaultLanguage, cfg.Origin, deriv.Debug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Deriv API client: %w", err)
+	}
+
+	return client, nil
+}
+
+// conn returns the client of the current connection.
+func (a *API) conn() *deriv.Client {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.client
+}
+
+// redial replaces client once its connection dropped. A Deriv API client can't connect again after its
+// connection was closed, so the next request connects with a new client. Calls with a client that was already
+// replaced are ignored.
+func (a *API) redial(client *deriv.Client) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.client != client {
+		return
+	}
+
+	next, err := newClient(a.cfg)
+	if err != nil {
+		slog.Error("Failed to replace Deriv API client", slog.Any("error", err))
+		return
+	}
+
+	client.Disconnect()
+	a.client = next
+
+	slog.Warn("Deriv API connection dropped, reconnecting on the next request")
+}
+
+// failed classifies err of a request sent with client and replaces client if its connection was closed.
+func (a *API) failed(client *deriv.Client, err error) error {
+	if errors.Is(err, deriv.ErrConnectionClosed) {
+		a.redial(client)
+	}
+
+	return classifyError(err)
+}
+
+// Session returns the trading session of the account with token, opening its own connection on the first call.
+// Deriv authorizes a whole connection, so every account trades over a separate one and strategies with
+// different tokens never act on each other's account. Sessions share the request budgets of a.
+// Returns an error if the client of the session can't be created.
+func (a *API) Session(token string) (executor.TradingProvider, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if sess, ok := a.sessions[token]; ok {
+		return sess, nil
+	}
+
+	client, err := newClient(a.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	sess := &API{
+		client:     client,
+		cfg:        a.cfg,
+		trading:    a.trading,
+		marketData: a.marketData,
+		sessions:   make(map[string]*API),
+		ticks:      a.ticks,
+	}
+
+	a.sessions[token] = sess
+
+	return sess, nil
+}
+
 func (a *API) Authorize(ctx context.Context, token string) (*executor.Account, error) {
-	res, err := a.client.Authorize(ctx, schema.Authorize{Authorize: token})
+	if err := a.trading.wait(ctx, false); err != nil {
+		return nil, err
+	}
+
+	client := a.conn()
+	res, err := client.Authorize(ctx, schema.Authorize{Authorize: token})
 	if err != nil {
-		return nil, fmt.Errorf("failed to authorize with Deriv API: %w", err)
+		return nil, fmt.Errorf("failed to authorize with Deriv API: %w", a.failed(client, err))
 	}
 
 	return &executor.Account{
@@ -56,3 +165,28 @@ func (a *API) Authorize(ctx context.Context, token string) (*executor.Account, e
 		Currency: *res.Authorize.Currency,
 	}, nil
 }
+
+// Balance retrieves the balance of the currently authorized account.
+// Accepts ctx to manage the request lifecycle.
+// Returns the account balance and an error if the API request fails.
+func (a *API) Balance(ctx context.Context) (*executor.Balance, error) {
+	if err := a.trading.wait(ctx, false); err != nil {
+		return nil, err
+	}
+
+	client := a.conn()
+	res, err := client.Balance(ctx, schema.Balance{Balance: 1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", a.failed(client, err))
+	}
+
+	if res.Balance == nil {
+		return nil, fmt.Errorf("empty balance response")
+	}
+
+	return &executor.Balance{
+		LoginID:  res.Balance.Loginid,
+		Currency: res.Balance.Currency,
+		Amount:   res.Balance.Balance,
+	}, nil
+}
@@ -0,0 +1,276 @@
+package deriv
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/executor"
+	"github.com/ksysoev/deriv-bot/pkg/core/market"
+	"github.com/ksysoev/deriv-bot/pkg/prov/deriv/derivtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAPI(t *testing.T, srv *derivtest.Server) *API {
+	t.Helper()
+
+	endpoint, stop := derivtest.Start(srv)
+	t.Cleanup(stop)
+
+	unthrottled := RateLimit{RatePerMinute: 60_000, Burst: 100}
+
+	api, err := New(Config{
+		Endpoint:   endpoint,
+		AppID:      1,
+		Origin:     "http://localhost",
+		RateLimits: RateLimits{Trading: unthrottled, MarketData: unthrottled},
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(api.Close)
+
+	return api
+}
+
+func TestAPI_Trading(t *testing.T) {
+	srv := derivtest.New(
+		derivtest.WithTicks("R_100", 100, 101, 102),
+		derivtest.WithTickInterval(10*time.Millisecond),
+		derivtest.WithAccount("token", derivtest.Account{LoginID: "VRTC1", Currency: "USD", Balance: 1000}),
+	)
+	api := newTestAPI(t, srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := api.Authorize(ctx, "wrong")
+	require.Error(t, err)
+
+	acc, err := api.Authorize(ctx, "token")
+	require.NoError(t, err)
+	assert.Equal(t, &executor.Account{ID: "VRTC1", Currency: "USD"}, acc)
+
+	ticks, err := api.SubscribeToTicks(ctx, "R_100")
+	require.NoError(t, err)
+
+	// The first tick is delivered with the subscription response, the stream starts with the next one.
+	for _, want := range []float64{101, 102, 100, 101} {
+		tick := <-ticks
+		assert.InDelta(t, want, tick.Quote, 0)
+	}
+
+	pos := executor.Position{Symbol: "R_100", Amount: 10, Leverage: 100, Currency: "USD"}
+
+	srv.FailNext("buy", "MarketIsClosed", "This market is presently closed.")
+
+	_, err = api.Buy(ctx, pos)
+	require.ErrorContains(t, err, "This market is presently closed.")
+
+	contractID, err := api.Buy(ctx, pos)
+	require.NoError(t, err)
+
+	contracts, err := api.Portfolio(ctx)
+	require.NoError(t, err)
+	require.Len(t, contracts, 1)
+	assert.Equal(t, contractID, contracts[0].ID)
+	assert.Equal(t, "MULTUP", contracts[0].ContractType)
+	assert.InDelta(t, 100, contracts[0].Multiplier, 0)
+
+	require.NoError(t, api.SetLimits(ctx, contractID, 5, 3))
+	assert.Equal(t, map[string]float64{"take_profit": 5, "stop_loss": 3}, srv.Contracts()[0].Limits)
+
+	soldFor, err := api.ClosePosition(ctx, contractID)
+	require.NoError(t, err)
+	assert.Greater(t, soldFor, 0.0)
+
+	balance, err := api.Balance(ctx)
+	require.NoError(t, err)
+	assert.InDelta(t, 1000-10+soldFor, balance.Amount, 1e-9)
+
+	sold, err := api.SoldContracts(ctx, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	require.Len(t, sold, 1)
+	assert.Equal(t, contractID, sold[0].ID)
+	assert.InDelta(t, soldFor, sold[0].SellPrice, 1e-9)
+
+	sold, err = api.SoldContracts(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, sold)
+
+	_, err = api.ClosePosition(ctx, contractID)
+	require.Error(t, err)
+}
+
+func TestAPI_Sessions(t *testing.T) {
+	srv := derivtest.New(
+		derivtest.WithTicks("R_100", 100, 101, 102),
+		derivtest.WithAccount("first", derivtest.Account{LoginID: "VRTC1", Currency: "USD", Balance: 1000}),
+		derivtest.WithAccount("second", derivtest.Account{LoginID: "VRTC2", Currency: "USD", Balance: 500}),
+	)
+	api := newTestAPI(t, srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	first, err := api.Session("first")
+	require.NoError(t, err)
+
+	second, err := api.Session("second")
+	require.NoError(t, err)
+
+	again, err := api.Session("first")
+	require.NoError(t, err)
+	assert.Same(t, first, again)
+
+	_, err = first.Authorize(ctx, "first")
+	require.NoError(t, err)
+
+	_, err = second.Authorize(ctx, "second")
+	require.NoError(t, err)
+
+	contractID, err := first.Buy(ctx, executor.Position{Symbol: "R_100", Amount: 10, Leverage: 100, Currency: "USD"})
+	require.NoError(t, err)
+
+	// Authorizing the second account doesn't switch the session of the first one.
+	contracts, err := first.Portfolio(ctx)
+	require.NoError(t, err)
+	require.Len(t, contracts, 1)
+	assert.Equal(t, contractID, contracts[0].ID)
+
+	contracts, err = second.Portfolio(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, contracts)
+
+	balance, err := second.Balance(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "VRTC2", balance.LoginID)
+	assert.InDelta(t, 500, balance.Amount, 0)
+}
+
+func TestAPI_Disconnect(t *testing.T) {
+	srv := derivtest.New(derivtest.WithTickInterval(10 * time.Millisecond))
+	api := newTestAPI(t, srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ticks, err := api.SubscribeToTicks(ctx, "R_100")
+	require.NoError(t, err)
+
+	<-ticks
+
+	srv.Disconnect()
+
+	for range ticks { //nolint:revive // drain until the stream is closed
+	}
+
+	// The dropped connection is replaced, so the symbol can be subscribed again.
+	ticks, err = api.SubscribeToTicks(ctx, "R_100")
+	require.NoError(t, err)
+
+	_, ok := <-ticks
+	assert.True(t, ok)
+}
+
+func TestAPI_Catalogue(t *testing.T) {
+	srv := derivtest.New(
+		derivtest.WithSymbol(derivtest.Symbol{Name: "R_100", Multipliers: []float64{40, 100}, MinStake: 1, MaxStake: 2000}),
+		derivtest.WithSymbol(derivtest.Symbol{
+			Name: "frxEURUSD", Market: "forex", Submarket: "major_pairs", Pip: 0.00001, Open: "08:00:00", Close: "16:00:00",
+		}),
+	)
+	api := newTestAPI(t, srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	symbols, err := api.ActiveSymbols(ctx)
+	require.NoError(t, err)
+	require.Len(t, symbols, 2)
+	assert.Equal(t, "R_100", symbols[0].Name)
+	assert.Equal(t, "synthetic_index", symbols[0].Market)
+	assert.True(t, symbols[0].ExchangeOpen)
+	assert.Equal(t, "forex", symbols[1].Market)
+	assert.InDelta(t, 0.00001, symbols[1].Pip, 0)
+
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	sessions, err := api.TradingTimes(ctx, day.Add(13*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []market.Session{{Open: day.Add(8 * time.Hour), Close: day.Add(16*time.Hour + time.Second)}}, sessions["frxEURUSD"])
+	assert.Equal(t, []market.Session{{Open: day, Close: day.Add(24 * time.Hour)}}, sessions["R_100"])
+
+	limits, err := api.ContractLimits(ctx, "R_100")
+	require.NoError(t, err)
+	assert.Equal(t, market.Limits{Multipliers: []float64{40, 100}, MinStake: 1, MaxStake: 2000}, limits)
+
+	_, err = api.ContractLimits(ctx, "R_1000")
+	require.Error(t, err)
+}
+
+func TestAPI_ErrorClasses(t *testing.T) {
+	srv := derivtest.New(derivtest.WithAccount("token", derivtest.Account{LoginID: "VRTC1", Currency: "USD", Balance: 5}))
+	api := newTestAPI(t, srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := api.Authorize(ctx, "wrong")
+	assert.ErrorIs(t, err, executor.ErrAuth)
+
+	_, err = api.Authorize(ctx, "token")
+	require.NoError(t, err)
+
+	pos := executor.Position{Symbol: "R_100", Amount: 10, Leverage: 100, Currency: "USD"}
+
+	tests := []struct {
+		code string
+		want error
+	}{
+		{code: "MarketIsClosed", want: executor.ErrMarketClosed},
+		{code: "RateLimit", want: executor.ErrRateLimited},
+		{code: "ContractBuyValidationError", want: executor.ErrInvalidParams},
+	}
+
+	for _, tt := range tests {
+		srv.FailNext("buy", tt.code, "Failed.")
+
+		_, err = api.Buy(ctx, pos)
+		assert.ErrorIs(t, err, tt.want, tt.code)
+	}
+
+	_, err = api.Buy(ctx, pos)
+	assert.ErrorIs(t, err, executor.ErrInsufficientFunds)
+
+	srv.FailNext("buy", "SomethingNew", "Failed.")
+
+	_, err = api.Buy(ctx, pos)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, executor.ErrTransient)
+
+	srv.Disconnect()
+
+	_, err = api.ClosePosition(ctx, 1)
+	assert.ErrorIs(t, err, executor.ErrTransient)
+}
+
+func TestShortcodeMultiplier(t *testing.T) {
+	tests := []struct {
+		name      string
+		shortcode string
+		want      float64
+	}{
+		{name: "multiplier up", shortcode: "MULTUP_R_100_10.00_150_1700000000_4853980799_0_0.00", want: 150},
+		{name: "fractional multiplier", shortcode: "MULTUP_R_100_10.00_2.5_1700000000", want: 2.5},
+		{name: "other symbol", shortcode: "MULTUP_R_10_10.00_150_1700000000", want: 0},
+		{name: "other type", shortcode: "CALL_R_100_19.54_1700000000_1700000300_S0P_0", want: 0},
+		{name: "truncated", shortcode: "MULTUP_R_100_10.00", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, shortcodeMultiplier(tt.shortcode, "MULTUP", "R_100"), 0)
+		})
+	}
+}
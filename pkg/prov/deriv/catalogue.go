@@ -0,0 +1,175 @@
+package deriv
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ksysoev/deriv-api/schema"
+	"github.com/ksysoev/deriv-bot/pkg/core/market"
+)
+
+// closedAllDay is the trading times value of a market which doesn't open on the day.
+const closedAllDay = "--"
+
+// ActiveSymbols retrieves the symbols currently offered by Deriv with their metadata.
+// Returns the list of symbols and an error if the request fails.
+func (a *API) ActiveSymbols(ctx context.Context) ([]market.Symbol, error) {
+	if err := a.marketData.wait(ctx, false); err != nil {
+		return nil, err
+	}
+
+	client := a.conn()
+	res, err := client.ActiveSymbols(ctx, schema.ActiveSymbols{ActiveSymbols: schema.ActiveSymbolsActiveSymbolsBrief})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active symbols: %w", a.failed(client, err))
+	}
+
+	symbols := make([]market.Symbol, 0, len(res.ActiveSymbols))
+
+	for _, s := range res.ActiveSymbols {
+		symbols = append(symbols, market.Symbol{
+			Name:         s.Symbol,
+			DisplayName:  s.DisplayName,
+			Market:       s.Market,
+			Submarket:    s.Submarket,
+			Pip:          s.Pip,
+			ExchangeOpen: s.ExchangeIsOpen == 1,
+			Suspended:    s.IsTradingSuspended == 1,
+		})
+	}
+
+	return symbols, nil
+}
+
+// TradingTimes retrieves the trading sessions of all symbols for the UTC day of date.
+// Returns sessions keyed by symbol and an error if the request fails or the response is malformed.
+func (a *API) TradingTimes(ctx context.Context, date time.Time) (map[string][]market.Session, error) {
+	day := date.UTC().Truncate(24 * time.Hour)
+
+	if err := a.marketData.wait(ctx, false); err != nil {
+		return nil, err
+	}
+
+	client := a.conn()
+	res, err := client.TradingTimes(ctx, schema.TradingTimes{TradingTimes: day.Format(time.DateOnly)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trading times: %w", a.failed(client, err))
+	}
+
+	if res.TradingTimes == nil {
+		return nil, fmt.Errorf("empty trading times response")
+	}
+
+	sessions := make(map[string][]market.Session)
+
+	for _, m := range res.TradingTimes.Markets {
+		for _, sm := range m.Submarkets {
+			for _, s := range sm.Symbols {
+				ss, err := parseSessions(day, s.Times)
+				if err != nil {
+					return nil, fmt.Errorf("invalid trading times of %s: %w", s.Symbol, err)
+				}
+
+				sessions[s.Symbol] = ss
+			}
+		}
+	}
+
+	return sessions, nil
+}
+
+// ContractLimits retrieves the multipliers and stake bounds of multiplier contracts offered for symbol.
+// Returns zero limits if the symbol offers no multiplier contracts, and an error if the request fails.
+func (a *API) ContractLimits(ctx context.Context, symbol string) (market.Limits, error) {
+	if err := a.marketData.wait(ctx, false); err != nil {
+		return market.Limits{}, err
+	}
+
+	client := a.conn()
+	res, err := client.ContractsFor(ctx, schema.ContractsFor{ContractsFor: symbol})
+	if err != nil {
+		return market.Limits{}, fmt.Errorf("failed to get contracts for %s: %w", symbol, a.failed(client, err))
+	}
+
+	if res.ContractsFor == nil {
+		return market.Limits{}, fmt.Errorf("empty contracts for response")
+	}
+
+	for _, c := range res.ContractsFor.Available {
+		if c.ContractType != string(schema.BuyParametersContractTypeMULTUP) {
+			continue
+		}
+
+		limits := market.Limits{Multipliers: make([]float64, 0, len(c.MultiplierRange))}
+
+		for _, m := range c.MultiplierRange {
+			if v, ok := m.(float64); ok {
+				limits.Multipliers = append(limits.Multipliers, v)
+			}
+		}
+
+		if c.MinStake != nil {
+			limits.MinStake = *c.MinStake
+		}
+
+		if c.MaxStake != nil {
+			limits.MaxStake = *c.MaxStake
+		}
+
+		return limits, nil
+	}
+
+	return market.Limits{}, nil
+}
+
+// parseSessions pairs open and close times of a trading times entry into sessions on day.
+func parseSessions(day time.Time, times map[string]any) ([]market.Session, error) {
+	opens, closes := toStrings(times["open"]), toStrings(times["close"])
+
+	if len(opens) != len(closes) {
+		return nil, fmt.Errorf("%d open times but %d close times", len(opens), len(closes))
+	}
+
+	sessions := make([]market.Session, 0, len(opens))
+
+	for i := range opens {
+		if opens[i] == closedAllDay || closes[i] == closedAllDay {
+			continue
+		}
+
+		open, err := time.Parse(time.TimeOnly, opens[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid open time: %w", err)
+		}
+
+		closeAt, err := time.Parse(time.TimeOnly, closes[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid close time: %w", err)
+		}
+
+		sessions = append(sessions, market.Session{
+			Open:  day.Add(sinceMidnight(open)),
+			Close: day.Add(sinceMidnight(closeAt) + time.Second),
+		})
+	}
+
+	return sessions, nil
+}
+
+func sinceMidnight(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+}
+
+func toStrings(v any) []string {
+	items, _ := v.([]any)
+	strs := make([]string, 0, len(items))
+
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+
+	return strs
+}
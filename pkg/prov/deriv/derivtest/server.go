@@ -0,0 +1,531 @@
+// Package derivtest provides a fake Deriv WebSocket API server for tests and offline demos.
+// It implements the subset of the Deriv v3 protocol used by the bot: authorize, balance, ticks, buy, sell,
+// proposal_open_contract, portfolio, profit_table, contract_update, forget, active_symbols, trading_times and contracts_for.
+package derivtest
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+const (
+	defaultTickInterval = 100 * time.Millisecond
+	defaultQuote        = 1000.0
+	defaultBalance      = 10_000.0
+	defaultCurrency     = "USD"
+	firstVirtualLoginID = 1_000_000
+)
+
+// Account is an account the fake server authorizes with a token.
+type Account struct {
+	LoginID  string
+	Currency string
+	Balance  float64
+}
+
+// Contract is a multiplier contract bought on the fake server.
+type Contract struct {
+	Limits       map[string]float64
+	LoginID      string
+	Symbol       string
+	ContractType string
+	Currency     string
+	Amount       float64
+	Multiplier   float64
+	EntrySpot    float64
+	ID           int
+	PurchaseTime int
+}
+
+// shortcode describes the contract the way Deriv does for multiplier contracts.
+func (c *Contract) shortcode() string {
+	return fmt.Sprintf("%s_%s_%.2f_%g_%d_0_0_0.00", c.ContractType, c.Symbol, c.Amount, c.Multiplier, c.PurchaseTime)
+}
+
+// SoldContract is a contract sold on the fake server, as reported in the profit table.
+type SoldContract struct {
+	Contract
+	SellPrice float64
+	SellTime  int
+}
+
+// Symbol is a symbol offered by the fake server. Open and Close are "HH:MM:SS" trading times in UTC,
+// a symbol without them trades all day.
+type Symbol struct {
+	Name        string
+	DisplayName string
+	Market      string
+	Submarket   string
+	Open        string
+	Close       string
+	Multipliers []float64
+	MinStake    float64
+	MaxStake    float64
+	Pip         float64
+	Suspended   bool
+}
+
+// defaultSymbols are the volatility indices offered when no symbol is configured with WithSymbol.
+var defaultSymbols = []Symbol{
+	{Name: "R_10", DisplayName: "Volatility 10 Index"},
+	{Name: "R_25", DisplayName: "Volatility 25 Index"},
+	{Name: "R_50", DisplayName: "Volatility 50 Index"},
+	{Name: "R_75", DisplayName: "Volatility 75 Index"},
+	{Name: "R_100", DisplayName: "Volatility 100 Index"},
+}
+
+// Option configures the fake server.
+type Option func(s *Server)
+
+// WithTicks scripts the quotes streamed for symbol. The sequence is replayed in a loop.
+// Symbols without a script stream a constant quote.
+func WithTicks(symbol string, quotes ...float64) Option {
+	return func(s *Server) {
+		s.scripts[symbol] = quotes
+	}
+}
+
+// WithTickInterval sets the delay between ticks of a subscription, it defaults to 100ms.
+func WithTickInterval(d time.Duration) Option {
+	return func(s *Server) {
+		s.interval = d
+	}
+}
+
+// WithAccount registers token for acc. Once any account is registered, unknown tokens are rejected,
+// otherwise every distinct non-empty token authorizes a virtual account of its own.
+func WithAccount(token string, acc Account) Option {
+	return func(s *Server) {
+		s.accounts[token] = &acc
+	}
+}
+
+// WithSymbol adds sym to the symbols offered by the server, replacing the default volatility indices.
+// Empty market details default to a synthetic index with multipliers of 10 to 1000 and stakes of 1 to 2000.
+func WithSymbol(sym Symbol) Option {
+	return func(s *Server) {
+		s.symbols[sym.Name] = withSymbolDefaults(sym)
+	}
+}
+
+// Server is a fake Deriv API server, it implements http.Handler and accepts WebSocket connections on any path.
+type Server struct {
+	accounts  map[string]*Account
+	scripts   map[string][]float64
+	quotes    map[string]float64
+	contracts map[int]*Contract
+	sold      []SoldContract
+	symbols   map[string]Symbol
+	failures  map[string][]*apiError
+	conns     map[*websocket.Conn]struct{}
+	interval  time.Duration
+	lastID    int
+	open      bool
+	mu        sync.Mutex
+}
+
+// New creates a fake Deriv API server configured with opts.
+func New(opts ...Option) *Server {
+	s := &Server{
+		accounts:  make(map[string]*Account),
+		scripts:   make(map[string][]float64),
+		quotes:    make(map[string]float64),
+		contracts: make(map[int]*Contract),
+		symbols:   make(map[string]Symbol),
+		failures:  make(map[string][]*apiError),
+		conns:     make(map[*websocket.Conn]struct{}),
+		interval:  defaultTickInterval,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.open = len(s.accounts) == 0
+
+	if len(s.symbols) == 0 {
+		for _, sym := range defaultSymbols {
+			s.symbols[sym.Name] = withSymbolDefaults(sym)
+		}
+	}
+
+	return s
+}
+
+// Start serves s on a local httptest server.
+// Returns the WebSocket endpoint to use as deriv.Config.Endpoint and a function stopping the server.
+func Start(s *Server) (endpoint string, stop func()) {
+	ts := httptest.NewServer(s)
+
+	return "ws" + strings.TrimPrefix(ts.URL, "http"), func() {
+		s.Disconnect()
+		ts.Close()
+	}
+}
+
+// FailNext makes the next request of msgType, e.g. "buy", fail with the API error code and message.
+// Calls are queued, so consecutive requests can be failed by calling it several times.
+func (s *Server) FailNext(msgType, code, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[msgType] = append(s.failures[msgType], &apiError{Code: code, Message: message})
+}
+
+// Disconnect closes all client connections, simulating a network failure or a server restart.
+func (s *Server) Disconnect() {
+	s.mu.Lock()
+	conns := make([]*websocket.Conn, 0, len(s.conns))
+
+	for c := range s.conns {
+		conns = append(conns, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range conns {
+		_ = c.Close(websocket.StatusGoingAway, "server disconnect")
+	}
+}
+
+// Contracts returns copies of the open contracts.
+func (s *Server) Contracts() []Contract {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	contracts := make([]Contract, 0, len(s.contracts))
+
+	for _, c := range s.contracts {
+		contracts = append(contracts, *c)
+	}
+
+	return contracts
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection and serves API requests until the client disconnects.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{InsecureSkipVerify: true})
+	if err != nil {
+		slog.Debug("Failed to accept WebSocket connection", slog.Any("error", err))
+		return
+	}
+
+	s.mu.Lock()
+	s.conns[conn] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+
+		_ = conn.CloseNow()
+	}()
+
+	sess := newSession(s, conn)
+	defer sess.close()
+
+	for {
+		_, data, err := conn.Read(r.Context())
+		if err != nil {
+			return
+		}
+
+		sess.handle(r.Context(), data)
+	}
+}
+
+// nextFailure pops the injected error for msgType, if any.
+func (s *Server) nextFailure(msgType string) *apiError {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	queue := s.failures[msgType]
+	if len(queue) == 0 {
+		return nil
+	}
+
+	s.failures[msgType] = queue[1:]
+
+	return queue[0]
+}
+
+func (s *Server) authorize(token string) (*Account, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if acc, ok := s.accounts[token]; ok {
+		return acc, true
+	}
+
+	if !s.open || token == "" {
+		return nil, false
+	}
+
+	acc := &Account{
+		LoginID:  fmt.Sprintf("VRTC%d", firstVirtualLoginID+len(s.accounts)),
+		Currency: defaultCurrency,
+		Balance:  defaultBalance,
+	}
+	s.accounts[token] = acc
+
+	return acc, true
+}
+
+// quote returns the last streamed quote of symbol.
+func (s *Server) quote(symbol string) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.quoteLocked(symbol)
+}
+
+func (s *Server) quoteLocked(symbol string) float64 {
+	if q, ok := s.quotes[symbol]; ok {
+		return q
+	}
+
+	if script := s.scripts[symbol]; len(script) > 0 {
+		return script[0]
+	}
+
+	return defaultQuote
+}
+
+// nextQuote advances the tick script of symbol, step is the index of the tick in the subscription.
+func (s *Server) nextQuote(symbol string, step int) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := s.quoteLocked(symbol)
+
+	if script := s.scripts[symbol]; len(script) > 0 {
+		q = script[step%len(script)]
+	}
+
+	s.quotes[symbol] = q
+
+	return q
+}
+
+func (s *Server) buy(acc *Account, p buyParameters) (*Contract, *apiError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p.Amount <= 0 {
+		return nil, &apiError{Code: "InvalidAmount", Message: "Stake must be positive."}
+	}
+
+	if p.Amount > acc.Balance {
+		return nil, &apiError{Code: "InsufficientBalance", Message: "Your account balance is insufficient for this trade."}
+	}
+
+	if p.ContractType != "MULTUP" && p.ContractType != "MULTDOWN" {
+		return nil, &apiError{Code: "InvalidContractType", Message: "Only multiplier contracts are supported."}
+	}
+
+	if sym, ok := s.symbols[p.Symbol]; ok {
+		if !slices.Contains(sym.Multipliers, p.Multiplier) {
+			return nil, &apiError{Code: "ContractBuyValidationError", Message: "Multiplier is not in acceptable range."}
+		}
+
+		if p.Amount < sym.MinStake || p.Amount > sym.MaxStake {
+			return nil, &apiError{Code: "ContractBuyValidationError", Message: "Stake is outside of the allowed range."}
+		}
+	}
+
+	s.lastID++
+	acc.Balance -= p.Amount
+
+	c := &Contract{
+		ID:           s.lastID,
+		LoginID:      acc.LoginID,
+		Symbol:       p.Symbol,
+		ContractType: p.ContractType,
+		Currency:     acc.Currency,
+		Amount:       p.Amount,
+		Multiplier:   p.Multiplier,
+		EntrySpot:    s.quoteLocked(p.Symbol),
+		PurchaseTime: int(time.Now().Unix()),
+		Limits:       make(map[string]float64),
+	}
+
+	s.contracts[c.ID] = c
+
+	return c, nil
+}
+
+func (s *Server) sell(acc *Account, contractID int) (soldFor float64, _ *apiError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.contracts[contractID]
+	if !ok || c.LoginID != acc.LoginID {
+		return 0, &apiError{Code: "InvalidSellContractProposal", Message: "This contract was not found among your open positions."}
+	}
+
+	soldFor = c.Amount + profit(c, s.quoteLocked(c.Symbol))
+
+	delete(s.contracts, contractID)
+	acc.Balance += soldFor
+
+	s.sold = append(s.sold, SoldContract{Contract: *c, SellPrice: soldFor, SellTime: int(time.Now().Unix())})
+
+	return soldFor, nil
+}
+
+func (s *Server) contract(acc *Account, contractID int) (Contract, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.contracts[contractID]
+	if !ok || c.LoginID != acc.LoginID {
+		return Contract{}, false
+	}
+
+	return *c, true
+}
+
+func (s *Server) portfolio(acc *Account) []Contract {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var contracts []Contract
+
+	for _, c := range s.contracts {
+		if c.LoginID == acc.LoginID {
+			contracts = append(contracts, *c)
+		}
+	}
+
+	return contracts
+}
+
+// profitTable returns contracts of acc sold at or after from, newest first.
+func (s *Server) profitTable(acc *Account, from int) []SoldContract {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var sold []SoldContract
+
+	for _, c := range slices.Backward(s.sold) {
+		if c.LoginID == acc.LoginID && c.SellTime >= from {
+			sold = append(sold, c)
+		}
+	}
+
+	return sold
+}
+
+func (s *Server) setLimits(acc *Account, contractID int, limits map[string]float64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.contracts[contractID]
+	if !ok || c.LoginID != acc.LoginID {
+		return false
+	}
+
+	for k, v := range limits {
+		c.Limits[k] = v
+	}
+
+	return true
+}
+
+func (s *Server) balance(acc *Account) Account {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return *acc
+}
+
+// profit calculates the profit of a multiplier contract at quote, the loss is capped by the stake.
+// activeSymbols returns the offered symbols ordered by name.
+func (s *Server) activeSymbols() []Symbol {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	symbols := slices.Collect(maps.Values(s.symbols))
+	slices.SortFunc(symbols, func(a, b Symbol) int { return strings.Compare(a.Name, b.Name) })
+
+	return symbols
+}
+
+func (s *Server) symbol(name string) (Symbol, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sym, ok := s.symbols[name]
+
+	return sym, ok
+}
+
+// isOpen reports whether sym trades at t according to its trading times.
+func (sym *Symbol) isOpen(t time.Time) bool {
+	if sym.Open == "" {
+		return true
+	}
+
+	now := t.UTC().Format(time.TimeOnly)
+
+	return now >= sym.Open && now < sym.Close
+}
+
+func withSymbolDefaults(sym Symbol) Symbol {
+	if sym.DisplayName == "" {
+		sym.DisplayName = sym.Name
+	}
+
+	if sym.Market == "" {
+		sym.Market, sym.Submarket = "synthetic_index", "random_index"
+	}
+
+	if sym.Multipliers == nil {
+		sym.Multipliers = []float64{10, 20, 50, 100, 200, 500, 1000}
+	}
+
+	if sym.MinStake == 0 && sym.MaxStake == 0 {
+		sym.MinStake, sym.MaxStake = 1, 2000
+	}
+
+	if sym.Pip == 0 {
+		sym.Pip = 0.01
+	}
+
+	return sym
+}
+
+func profit(c *Contract, quote float64) float64 {
+	if c.EntrySpot == 0 {
+		return 0
+	}
+
+	change := (quote - c.EntrySpot) / c.EntrySpot
+	if c.ContractType == "MULTDOWN" {
+		change = -change
+	}
+
+	return math.Max(c.Amount*c.Multiplier*change, -c.Amount)
+}
+
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type buyParameters struct {
+	ContractType string  `json:"contract_type"`
+	Symbol       string  `json:"symbol"`
+	Amount       float64 `json:"amount"`
+	Multiplier   float64 `json:"multiplier"`
+}
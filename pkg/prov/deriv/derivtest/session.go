@@ -0,0 +1,605 @@
+package derivtest
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+const writeTimeout = 5 * time.Second
+
+// requestTypes lists supported request types, a request type is the key of the request naming the call.
+var requestTypes = []string{
+	"authorize", "balance", "ticks", "buy", "sell", "proposal_open_contract", "portfolio", "profit_table", "contract_update",
+	"forget", "ping",
+	"active_symbols", "trading_times", "contracts_for",
+}
+
+// authorizedTypes lists request types which require an authorized connection.
+var authorizedTypes = map[string]struct{}{
+	"balance": {}, "buy": {}, "sell": {}, "proposal_open_contract": {}, "portfolio": {}, "profit_table": {},
+	"contract_update": {},
+}
+
+// session holds the state of a single client connection.
+type session struct {
+	srv     *Server
+	conn    *websocket.Conn
+	acc     *Account
+	subs    map[string]context.CancelFunc
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	lastSub int
+	mu      sync.Mutex
+}
+
+type request struct {
+	echo    map[string]any
+	raw     map[string]json.RawMessage
+	reqID   *int
+	msgType string
+}
+
+func newSession(srv *Server, conn *websocket.Conn) *session {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &session{
+		srv:    srv,
+		conn:   conn,
+		subs:   make(map[string]context.CancelFunc),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// close stops all subscriptions of the session and waits for their streams to finish.
+func (s *session) close() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *session) handle(ctx context.Context, data []byte) {
+	req := request{}
+
+	if err := json.Unmarshal(data, &req.raw); err != nil {
+		s.send(req, nil, &apiError{Code: "InputValidationFailed", Message: "Invalid JSON."})
+		return
+	}
+
+	_ = json.Unmarshal(data, &req.echo)
+
+	if raw, ok := req.raw["req_id"]; ok {
+		var id int
+		if json.Unmarshal(raw, &id) == nil {
+			req.reqID = &id
+		}
+	}
+
+	for _, t := range requestTypes {
+		if _, ok := req.raw[t]; ok {
+			req.msgType = t
+			break
+		}
+	}
+
+	if req.msgType == "" {
+		s.send(req, nil, &apiError{Code: "UnrecognisedRequest", Message: "Unrecognised request."})
+		return
+	}
+
+	if apiErr := s.srv.nextFailure(req.msgType); apiErr != nil {
+		s.send(req, nil, apiErr)
+		return
+	}
+
+	s.mu.Lock()
+	acc := s.acc
+	s.mu.Unlock()
+
+	if _, ok := authorizedTypes[req.msgType]; ok && acc == nil {
+		s.send(req, nil, &apiError{Code: "AuthorizationRequired", Message: "Please log in."})
+		return
+	}
+
+	switch req.msgType {
+	case "authorize":
+		s.handleAuthorize(req)
+	case "balance":
+		b := s.srv.balance(acc)
+		s.send(req, map[string]any{"balance": b.Balance, "currency": b.Currency, "loginid": b.LoginID}, nil)
+	case "ticks":
+		s.handleTicks(ctx, req)
+	case "buy":
+		s.handleBuy(req, acc)
+	case "sell":
+		s.handleSell(req, acc)
+	case "proposal_open_contract":
+		s.handleOpenContract(req, acc)
+	case "portfolio":
+		s.handlePortfolio(req, acc)
+	case "profit_table":
+		s.handleProfitTable(req, acc)
+	case "contract_update":
+		s.handleContractUpdate(req, acc)
+	case "forget":
+		s.handleForget(req)
+	case "ping":
+		s.send(req, "pong", nil)
+	case "active_symbols":
+		s.handleActiveSymbols(req)
+	case "trading_times":
+		s.handleTradingTimes(req)
+	case "contracts_for":
+		s.handleContractsFor(req)
+	}
+}
+
+func (s *session) handleAuthorize(req request) {
+	var token string
+
+	_ = json.Unmarshal(req.raw["authorize"], &token)
+
+	acc, ok := s.srv.authorize(token)
+	if !ok {
+		s.send(req, nil, &apiError{Code: "InvalidToken", Message: "The token is invalid."})
+		return
+	}
+
+	s.mu.Lock()
+	s.acc = acc
+	s.mu.Unlock()
+
+	b := s.srv.balance(acc)
+
+	s.send(req, map[string]any{
+		"loginid":    b.LoginID,
+		"currency":   b.Currency,
+		"balance":    b.Balance,
+		"is_virtual": 1,
+		"scopes":     []string{"read", "trade"},
+	}, nil)
+}
+
+func (s *session) handleTicks(ctx context.Context, req request) {
+	var symbol string
+
+	_ = json.Unmarshal(req.raw["ticks"], &symbol)
+
+	if symbol == "" {
+		s.send(req, nil, &apiError{Code: "InputValidationFailed", Message: "Symbol is required."})
+		return
+	}
+
+	if !s.subscribes(req) {
+		s.send(req, s.tick(symbol, 0, ""), nil)
+		return
+	}
+
+	s.stream(ctx, req, func(subID string, step int) (any, bool) {
+		return s.tick(symbol, step, subID), true
+	})
+}
+
+func (s *session) tick(symbol string, step int, subID string) map[string]any {
+	q := s.srv.nextQuote(symbol, step)
+
+	return map[string]any{
+		"ask":      q,
+		"bid":      q,
+		"quote":    q,
+		"epoch":    time.Now().Unix(),
+		"id":       subID,
+		"pip_size": 2,
+		"symbol":   symbol,
+	}
+}
+
+func (s *session) handleBuy(req request, acc *Account) {
+	var params buyParameters
+
+	_ = json.Unmarshal(req.raw["parameters"], &params)
+
+	c, apiErr := s.srv.buy(acc, params)
+	if apiErr != nil {
+		s.send(req, nil, apiErr)
+		return
+	}
+
+	s.send(req, map[string]any{
+		"balance_after":  s.srv.balance(acc).Balance,
+		"buy_price":      c.Amount,
+		"contract_id":    c.ID,
+		"longcode":       "Multiplier contract on " + c.Symbol,
+		"payout":         0,
+		"purchase_time":  c.PurchaseTime,
+		"shortcode":      c.shortcode(),
+		"start_time":     c.PurchaseTime,
+		"transaction_id": c.ID,
+	}, nil)
+}
+
+func (s *session) handleSell(req request, acc *Account) {
+	var contractID int
+
+	_ = json.Unmarshal(req.raw["sell"], &contractID)
+
+	soldFor, apiErr := s.srv.sell(acc, contractID)
+	if apiErr != nil {
+		s.send(req, nil, apiErr)
+		return
+	}
+
+	s.send(req, map[string]any{
+		"balance_after":  s.srv.balance(acc).Balance,
+		"contract_id":    contractID,
+		"reference_id":   contractID,
+		"sold_for":       soldFor,
+		"transaction_id": contractID,
+	}, nil)
+}
+
+func (s *session) handleOpenContract(req request, acc *Account) {
+	var contractID int
+
+	_ = json.Unmarshal(req.raw["contract_id"], &contractID)
+
+	if _, ok := s.srv.contract(acc, contractID); !ok {
+		s.send(req, nil, &apiError{Code: "InvalidContractId", Message: "Contract not found."})
+		return
+	}
+
+	if !s.subscribes(req) {
+		s.send(req, s.openContract(acc, contractID), nil)
+		return
+	}
+
+	s.stream(s.ctx, req, func(_ string, _ int) (any, bool) {
+		poc := s.openContract(acc, contractID)
+		return poc, poc["is_sold"] == 0
+	})
+}
+
+// openContract describes the contract, a sold contract is reported with is_sold set.
+func (s *session) openContract(acc *Account, contractID int) map[string]any {
+	c, ok := s.srv.contract(acc, contractID)
+	if !ok {
+		return map[string]any{"contract_id": contractID, "is_sold": 1, "status": "sold"}
+	}
+
+	spot := s.srv.quote(c.Symbol)
+	p := profit(&c, spot)
+
+	return map[string]any{
+		"contract_id":   c.ID,
+		"contract_type": c.ContractType,
+		"currency":      c.Currency,
+		"underlying":    c.Symbol,
+		"buy_price":     c.Amount,
+		"bid_price":     c.Amount + p,
+		"multiplier":    c.Multiplier,
+		"entry_spot":    c.EntrySpot,
+		"current_spot":  spot,
+		"profit":        p,
+		"date_start":    c.PurchaseTime,
+		"is_sold":       0,
+		"status":        "open",
+	}
+}
+
+func (s *session) handlePortfolio(req request, acc *Account) {
+	contracts := make([]map[string]any, 0)
+
+	for _, c := range s.srv.portfolio(acc) {
+		contracts = append(contracts, map[string]any{
+			"contract_id":   c.ID,
+			"contract_type": c.ContractType,
+			"currency":      c.Currency,
+			"symbol":        c.Symbol,
+			"buy_price":     c.Amount,
+			"purchase_time": c.PurchaseTime,
+			"date_start":    c.PurchaseTime,
+			"shortcode":     c.shortcode(),
+		})
+	}
+
+	s.send(req, map[string]any{"contracts": contracts}, nil)
+}
+
+// handleProfitTable reports sold contracts, date_from is supported as an epoch only.
+func (s *session) handleProfitTable(req request, acc *Account) {
+	var dateFrom string
+
+	_ = json.Unmarshal(req.raw["date_from"], &dateFrom)
+	from, _ := strconv.Atoi(dateFrom)
+
+	transactions := make([]map[string]any, 0)
+
+	for _, c := range s.srv.profitTable(acc, from) {
+		transactions = append(transactions, map[string]any{
+			"contract_id":       c.ID,
+			"transaction_id":    c.ID,
+			"buy_price":         c.Amount,
+			"sell_price":        c.SellPrice,
+			"purchase_time":     c.PurchaseTime,
+			"sell_time":         c.SellTime,
+			"underlying_symbol": c.Symbol,
+		})
+	}
+
+	s.send(req, map[string]any{"count": len(transactions), "transactions": transactions}, nil)
+}
+
+func (s *session) handleContractUpdate(req request, acc *Account) {
+	var (
+		contractID int
+		limits     map[string]float64
+	)
+
+	_ = json.Unmarshal(req.raw["contract_id"], &contractID)
+	_ = json.Unmarshal(req.raw["limit_order"], &limits)
+
+	if !s.srv.setLimits(acc, contractID, limits) {
+		s.send(req, nil, &apiError{Code: "ContractUpdateFailure", Message: "Contract not found."})
+		return
+	}
+
+	resp := make(map[string]any, len(limits))
+	for k, v := range limits {
+		resp[k] = map[string]any{"order_amount": v}
+	}
+
+	s.send(req, resp, nil)
+}
+
+func (s *session) handleForget(req request) {
+	var subID string
+
+	_ = json.Unmarshal(req.raw["forget"], &subID)
+
+	s.mu.Lock()
+	cancel, ok := s.subs[subID]
+	delete(s.subs, subID)
+	s.mu.Unlock()
+
+	if ok {
+		cancel()
+		s.send(req, 1, nil)
+
+		return
+	}
+
+	s.send(req, 0, nil)
+}
+
+func (s *session) handleActiveSymbols(req request) {
+	symbols := s.srv.activeSymbols()
+	now := time.Now()
+	resp := make([]map[string]any, 0, len(symbols))
+
+	for i, sym := range symbols {
+		resp = append(resp, map[string]any{
+			"display_name":           sym.DisplayName,
+			"display_order":          i,
+			"exchange_is_open":       boolToInt(sym.isOpen(now)),
+			"is_trading_suspended":   boolToInt(sym.Suspended),
+			"market":                 sym.Market,
+			"market_display_name":    sym.Market,
+			"pip":                    sym.Pip,
+			"subgroup":               "none",
+			"subgroup_display_name":  "None",
+			"submarket":              sym.Submarket,
+			"submarket_display_name": sym.Submarket,
+			"symbol":                 sym.Name,
+			"symbol_type":            "",
+		})
+	}
+
+	s.send(req, resp, nil)
+}
+
+// handleTradingTimes reports the trading times of every symbol, grouped by market and submarket.
+// The requested date is ignored, all days share the same times.
+func (s *session) handleTradingTimes(req request) {
+	markets := make(map[string]map[string][]map[string]any)
+
+	for _, sym := range s.srv.activeSymbols() {
+		open, closeAt := sym.Open, sym.Close
+		if open == "" {
+			open, closeAt = "00:00:00", "23:59:59"
+		}
+
+		if markets[sym.Market] == nil {
+			markets[sym.Market] = make(map[string][]map[string]any)
+		}
+
+		markets[sym.Market][sym.Submarket] = append(markets[sym.Market][sym.Submarket], map[string]any{
+			"name":   sym.DisplayName,
+			"symbol": sym.Name,
+			"times":  map[string]any{"open": []string{open}, "close": []string{closeAt}, "settlement": closeAt},
+		})
+	}
+
+	resp := make([]map[string]any, 0, len(markets))
+
+	for _, market := range slices.Sorted(maps.Keys(markets)) {
+		submarkets := make([]map[string]any, 0, len(markets[market]))
+
+		for _, submarket := range slices.Sorted(maps.Keys(markets[market])) {
+			submarkets = append(submarkets, map[string]any{"name": submarket, "symbols": markets[market][submarket]})
+		}
+
+		resp = append(resp, map[string]any{"name": market, "submarkets": submarkets})
+	}
+
+	s.send(req, map[string]any{"markets": resp}, nil)
+}
+
+// handleContractsFor reports the multiplier contracts available for the symbol.
+func (s *session) handleContractsFor(req request) {
+	var symbol string
+
+	_ = json.Unmarshal(req.raw["contracts_for"], &symbol)
+
+	sym, ok := s.srv.symbol(symbol)
+	if !ok {
+		s.send(req, nil, &apiError{Code: "InvalidSymbol", Message: "Symbol " + symbol + " is invalid."})
+		return
+	}
+
+	available := make([]map[string]any, 0, 2)
+
+	for _, contractType := range []string{"MULTUP", "MULTDOWN"} {
+		sentiment := "up"
+		if contractType == "MULTDOWN" {
+			sentiment = "down"
+		}
+
+		available = append(available, map[string]any{
+			"barrier_category":          "american",
+			"barriers":                  0,
+			"contract_category":         "multiplier",
+			"contract_category_display": "Multiply Up/Multiply Down",
+			"contract_type":             contractType,
+			"exchange_name":             "RANDOM",
+			"expiry_type":               "no_expiry",
+			"market":                    sym.Market,
+			"max_contract_duration":     "",
+			"min_contract_duration":     "",
+			"multiplier_range":          sym.Multipliers,
+			"min_stake":                 sym.MinStake,
+			"max_stake":                 sym.MaxStake,
+			"sentiment":                 sentiment,
+			"start_type":                "spot",
+			"submarket":                 sym.Submarket,
+			"underlying_symbol":         sym.Name,
+		})
+	}
+
+	s.send(req, map[string]any{"available": available, "hit_count": len(available)}, nil)
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+func (s *session) subscribes(req request) bool {
+	var subscribe int
+
+	_ = json.Unmarshal(req.raw["subscribe"], &subscribe)
+
+	return subscribe == 1
+}
+
+// stream sends the first message produced by next as the subscription response and keeps sending
+// messages at the tick interval until the subscription is forgotten, next reports the stream is over
+// or the connection is closed.
+func (s *session) stream(ctx context.Context, req request, next func(subID string, step int) (any, bool)) {
+	s.mu.Lock()
+	s.lastSub++
+	subID := strconv.Itoa(s.lastSub)
+
+	subCtx, cancel := context.WithCancel(s.ctx)
+	s.subs[subID] = cancel
+	s.mu.Unlock()
+
+	payload, more := next(subID, 0)
+	s.sendSubscription(req, subID, payload)
+
+	if !more {
+		cancel()
+		return
+	}
+
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+
+		ticker := time.NewTicker(s.srv.interval)
+		defer ticker.Stop()
+
+		for step := 1; ; step++ {
+			select {
+			case <-ctx.Done():
+				return
+			case <-subCtx.Done():
+				return
+			case <-ticker.C:
+				payload, more := next(subID, step)
+				s.sendSubscription(req, subID, payload)
+
+				if !more {
+					return
+				}
+			}
+		}
+	}()
+}
+
+func (s *session) sendSubscription(req request, subID string, payload any) {
+	s.write(s.envelope(req, map[string]any{
+		req.msgType:    payload,
+		"subscription": map[string]any{"id": subID},
+	}))
+}
+
+func (s *session) send(req request, payload any, apiErr *apiError) {
+	body := map[string]any{}
+
+	if apiErr != nil {
+		body["error"] = apiErr
+	} else {
+		body[req.msgType] = payload
+	}
+
+	s.write(s.envelope(req, body))
+}
+
+// envelope adds the common response fields to body. Tick responses use the tick message type.
+func (s *session) envelope(req request, body map[string]any) map[string]any {
+	msgType := req.msgType
+	if msgType == "ticks" {
+		msgType = "tick"
+
+		if p, ok := body["ticks"]; ok {
+			delete(body, "ticks")
+			body["tick"] = p
+		}
+	}
+
+	body["echo_req"] = req.echo
+	body["msg_type"] = msgType
+
+	if req.reqID != nil {
+		body["req_id"] = *req.reqID
+	}
+
+	return body
+}
+
+func (s *session) write(msg map[string]any) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		slog.Error("Failed to encode fake Deriv API response", slog.Any("error", err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, writeTimeout)
+	defer cancel()
+
+	// Write errors mean the connection is gone, the read loop of the server cleans it up.
+	_ = s.conn.Write(ctx, websocket.MessageText, data)
+}
@@ -0,0 +1,52 @@
+package deriv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ksysoev/deriv-api"
+	"github.com/ksysoev/deriv-bot/pkg/core/executor"
+)
+
+// errorClasses maps Deriv API error codes to the executor errors they are reported with.
+var errorClasses = map[string]error{
+	"InvalidToken":                executor.ErrAuth,
+	"AuthorizationRequired":       executor.ErrAuth,
+	"PermissionDenied":            executor.ErrAuth,
+	"DisabledClient":              executor.ErrAuth,
+	"InsufficientBalance":         executor.ErrInsufficientFunds,
+	"MarketIsClosed":              executor.ErrMarketClosed,
+	"MarketIsClosedTryVolatility": executor.ErrMarketClosed,
+	"RateLimit":                   executor.ErrRateLimited,
+	"InputValidationFailed":       executor.ErrInvalidParams,
+	"ContractBuyValidationError":  executor.ErrInvalidParams,
+	"ContractCreationFailure":     executor.ErrInvalidParams,
+	"InvalidContractProposal":     executor.ErrInvalidParams,
+	"InvalidSellContractProposal": executor.ErrInvalidParams,
+	"InvalidContractId":           executor.ErrInvalidParams,
+	"ContractUpdateFailure":       executor.ErrInvalidParams,
+	"OfferingsValidationError":    executor.ErrInvalidParams,
+	"InvalidSymbol":               executor.ErrInvalidParams,
+	"InternalServerError":         executor.ErrTransient,
+}
+
+// classifyError wraps err with the executor error matching its Deriv error code.
+// Failures without an API error, like a dropped connection or a timeout, are transient.
+// Errors with unknown codes and cancellations are returned as they are.
+func classifyError(err error) error {
+	var apiErr *deriv.APIError
+	if errors.As(err, &apiErr) {
+		if class, ok := errorClasses[apiErr.Code]; ok {
+			return fmt.Errorf("%w: %w", class, err)
+		}
+
+		return err
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %w", executor.ErrTransient, err)
+}
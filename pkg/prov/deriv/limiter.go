@@ -0,0 +1,124 @@
+package deriv
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	budgetTrading    = "trading"
+	budgetMarketData = "market_data"
+)
+
+// defaultRateLimits keep requests below the per connection limits enforced by Deriv,
+// which reports its limits in requests per minute.
+var defaultRateLimits = RateLimits{
+	Trading:    RateLimit{RatePerMinute: 90, Burst: 5},
+	MarketData: RateLimit{RatePerMinute: 150, Burst: 10},
+}
+
+// RateLimits sets the request budgets of the client, trading requests and market data requests are limited separately.
+type RateLimits struct {
+	Trading    RateLimit `mapstructure:"trading"`
+	MarketData RateLimit `mapstructure:"market_data"`
+}
+
+// RateLimit is a token bucket refilled with RatePerMinute requests per minute and holding up to Burst requests.
+// Zero values are replaced with the defaults of the budget.
+type RateLimit struct {
+	RatePerMinute int `mapstructure:"rate_per_minute"`
+	Burst         int `mapstructure:"burst"`
+}
+
+// budget is a token bucket shared by a group of requests. Urgent requests, such as closing a position,
+// are served before queued requests of normal priority.
+type budget struct {
+	limiter *rate.Limiter
+	name    string
+	urgent  atomic.Int32
+}
+
+func newBudget(name string, cfg, defaults RateLimit) *budget {
+	if cfg.RatePerMinute == 0 {
+		cfg.RatePerMinute = defaults.RatePerMinute
+	}
+
+	if cfg.Burst == 0 {
+		cfg.Burst = defaults.Burst
+	}
+
+	return &budget{
+		limiter: rate.NewLimiter(rate.Every(time.Minute/time.Duration(cfg.RatePerMinute)), cfg.Burst),
+		name:    name,
+	}
+}
+
+// wait blocks until the budget allows a request, urgent requests skip the queue of normal ones.
+// Returns an error if ctx is cancelled before the request is allowed.
+func (b *budget) wait(ctx context.Context, urgent bool) error {
+	if b.urgent.Load() == 0 && b.limiter.Allow() {
+		return nil
+	}
+
+	priority := "normal"
+	if urgent {
+		priority = "urgent"
+	}
+
+	apiThrottled.WithLabelValues(b.name, priority).Inc()
+	apiQueued.WithLabelValues(b.name).Inc()
+
+	defer apiQueued.WithLabelValues(b.name).Dec()
+	defer observeThrottle(b.name, time.Now())
+
+	var err error
+
+	if urgent {
+		err = b.waitUrgent(ctx)
+	} else {
+		err = b.waitNormal(ctx)
+	}
+
+	if err != nil {
+		return fmt.Errorf("rate limit of %s requests: %w", b.name, err)
+	}
+
+	return nil
+}
+
+func (b *budget) waitUrgent(ctx context.Context) error {
+	b.urgent.Add(1)
+	defer b.urgent.Add(-1)
+
+	return b.limiter.Wait(ctx)
+}
+
+// waitNormal polls the bucket, leaving tokens to urgent requests as long as any of them is queued.
+func (b *budget) waitNormal(ctx context.Context) error {
+	interval := time.Duration(float64(time.Second) / float64(b.limiter.Limit()))
+
+	for {
+		if b.urgent.Load() == 0 && b.limiter.Allow() {
+			return nil
+		}
+
+		// Urgent requests reserve tokens ahead, so the bucket may be in debt of more than one token.
+		delay := interval
+		if missing := 1 - b.limiter.Tokens(); missing > 0 {
+			delay = time.Duration(missing * float64(interval))
+		}
+
+		t := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package deriv
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudget_Wait(t *testing.T) {
+	b := newBudget(budgetTrading, RateLimit{RatePerMinute: 1200, Burst: 2}, defaultRateLimits.Trading)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+
+	for range 3 {
+		require.NoError(t, b.wait(ctx, false))
+	}
+
+	// The burst is served at once, the third request waits for a token refilled every 50ms.
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+
+	cancelled, cancelWait := context.WithCancel(ctx)
+	cancelWait()
+
+	assert.ErrorIs(t, b.wait(cancelled, false), context.Canceled)
+}
+
+func TestBudget_UrgentFirst(t *testing.T) {
+	b := newBudget(budgetTrading, RateLimit{RatePerMinute: 600, Burst: 1}, defaultRateLimits.Trading)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, b.wait(ctx, false))
+
+	served := make(chan string, 2)
+
+	go func() {
+		assert.NoError(t, b.wait(ctx, false))
+		served <- "open"
+	}()
+
+	// Let the normal request queue before the urgent one arrives.
+	time.Sleep(20 * time.Millisecond)
+
+	go func() {
+		assert.NoError(t, b.wait(ctx, true))
+		served <- "close"
+	}()
+
+	assert.Equal(t, "close", <-served)
+	assert.Equal(t, "open", <-served)
+}
+
+func TestNewBudget_Defaults(t *testing.T) {
+	b := newBudget(budgetMarketData, RateLimit{}, defaultRateLimits.MarketData)
+
+	assert.Equal(t, defaultRateLimits.MarketData.Burst, b.limiter.Burst())
+	assert.InDelta(t, float64(defaultRateLimits.MarketData.RatePerMinute)/60, float64(b.limiter.Limit()), 1e-9)
+}
@@ -9,18 +9,33 @@ import (
 	"github.com/ksysoev/deriv-bot/pkg/core/signal"
 )
 
+// TicksConfig sets how ticks are queued while the consumer of a subscription is busy. Buffer is the number
+// of queued ticks, the oldest one is dropped when the queue is full. With Conflate only the latest tick is kept.
+type TicksConfig struct {
+	Buffer   int  `mapstructure:"buffer"`
+	Conflate bool `mapstructure:"conflate"`
+}
+
 // SubscribeToTicks subscribes to real-time tick data for the specified symbol using the provided context.
 // It listens for tick data updates and streams them through a channel of signal.Tick.
+// Ticks are queued while the consumer is busy, so the websocket reader is never blocked by a slow consumer.
 // Accepts ctx for managing subscription lifecycle and symbol, the market symbol to subscribe to.
-// Returns a read-only channel of signal.Tick containing streaming tick updates and an error if the subscription fails.
+// Returns a read-only channel of signal.Tick containing streaming tick updates, closed when the connection drops,
+// and an error if the subscription fails.
 func (a *API) SubscribeToTicks(ctx context.Context, symbol string) (<-chan signal.Tick, error) {
-	_, sub, err := a.client.SubscribeTicks(ctx, schema.Ticks{Ticks: symbol})
+	if err := a.marketData.wait(ctx, false); err != nil {
+		return nil, err
+	}
+
+	client := a.conn()
+	_, sub, err := client.SubscribeTicks(ctx, schema.Ticks{Ticks: symbol})
 	if err != nil {
-		return nil, fmt.Errorf("failed to subscribe to ticks for symbol %s: %w", symbol, err)
+		return nil, fmt.Errorf("failed to subscribe to ticks for symbol %s: %w", symbol, a.failed(client, err))
 	}
 
 	subChan := sub.GetStream()
 	resChan := make(chan signal.Tick)
+	queue := signal.NewTickQueue(symbol, signal.QueueConfig(a.ticks))
 
 	a.wg.Add(1)
 
@@ -30,22 +45,32 @@ func (a *API) SubscribeToTicks(ctx context.Context, symbol string) (<-chan signa
 		defer close(resChan)
 
 		for {
+			// Sending is enabled only when a tick is queued.
+			var out chan<- signal.Tick
+
+			next, ok := queue.Peek()
+			if ok {
+				out = resChan
+			}
+
 			select {
 			case <-ctx.Done():
 				return
 			case tick, ok := <-subChan:
 				if !ok {
+					// The stream ends when the connection drops, the subscriber subscribes again over a new one.
+					a.redial(client)
 					return
 				}
 
-				epoch := time.Unix(int64(*tick.Tick.Epoch), 0)
-
-				resChan <- signal.Tick{
-					Time:  epoch,
+				queue.Push(signal.Tick{
+					Time:  time.Unix(int64(*tick.Tick.Epoch), 0),
 					Quote: *tick.Tick.Quote,
 					Ask:   *tick.Tick.Ask,
 					Bid:   *tick.Tick.Bid,
-				}
+				})
+			case out <- next:
+				queue.Pop()
 			}
 		}
 	}()
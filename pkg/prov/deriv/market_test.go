@@ -0,0 +1,51 @@
+package deriv
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/prov/deriv/derivtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_SlowTickConsumer(t *testing.T) {
+	srv := derivtest.New(
+		derivtest.WithTicks("R_100", 100, 101, 102, 103, 104),
+		derivtest.WithTickInterval(5*time.Millisecond),
+		derivtest.WithAccount("token", derivtest.Account{LoginID: "VRTC1", Currency: "USD", Balance: 1000}),
+	)
+
+	endpoint, stop := derivtest.Start(srv)
+	t.Cleanup(stop)
+
+	api, err := New(Config{Endpoint: endpoint, AppID: 1, Origin: "http://localhost", Ticks: TicksConfig{Conflate: true}})
+	require.NoError(t, err)
+
+	t.Cleanup(api.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = api.Authorize(ctx, "token")
+	require.NoError(t, err)
+
+	ticks, err := api.SubscribeToTicks(ctx, "R_100")
+	require.NoError(t, err)
+
+	// Ticks keep arriving while nobody reads them, requests on the same connection are still answered.
+	time.Sleep(100 * time.Millisecond)
+
+	for range 3 {
+		_, err := api.Balance(ctx)
+		require.NoError(t, err)
+	}
+
+	select {
+	case tick := <-ticks:
+		assert.Contains(t, []float64{100, 101, 102, 103, 104}, tick.Quote)
+	case <-ctx.Done():
+		t.Fatal("no tick delivered")
+	}
+}
@@ -0,0 +1,70 @@
+package deriv
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	opBuy   = "buy"
+	opSell  = "sell"
+	opClose = "close"
+)
+
+var (
+	orderAttempts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "deriv_bot",
+		Name:      "order_attempts_total",
+		Help:      "Number of order requests sent to Deriv API per operation.",
+	}, []string{"operation"})
+
+	orderFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "deriv_bot",
+		Name:      "order_failures_total",
+		Help:      "Number of failed order requests per operation.",
+	}, []string{"operation"})
+
+	orderLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "deriv_bot",
+		Name:      "order_duration_seconds",
+		Help:      "Round-trip time of order requests per operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	apiThrottled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "deriv_bot",
+		Name:      "api_throttled_requests_total",
+		Help:      "Number of Deriv API requests delayed by the client side rate limit per budget and priority.",
+	}, []string{"budget", "priority"})
+
+	apiQueued = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "deriv_bot",
+		Name:      "api_queued_requests",
+		Help:      "Number of Deriv API requests waiting for the client side rate limit per budget.",
+	}, []string{"budget"})
+
+	apiThrottleWait = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "deriv_bot",
+		Name:      "api_throttle_wait_seconds",
+		Help:      "Time Deriv API requests waited for the client side rate limit per budget.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"budget"})
+)
+
+// observeOrder records an order attempt for the operation, its latency since start and whether it failed.
+// It is meant to be deferred with a pointer to the named error result of the order call.
+func observeOrder(operation string, start time.Time, err *error) {
+	orderAttempts.WithLabelValues(operation).Inc()
+	orderLatency.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+
+	if *err != nil {
+		orderFailures.WithLabelValues(operation).Inc()
+	}
+}
+
+// observeThrottle records the time a request of the budget waited since start.
+func observeThrottle(budget string, start time.Time) {
+	apiThrottleWait.WithLabelValues(budget).Observe(time.Since(start).Seconds())
+}
@@ -3,6 +3,9 @@ package deriv
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ksysoev/deriv-api/schema"
 	"github.com/ksysoev/deriv-bot/pkg/core/executor"
@@ -12,10 +15,17 @@ import (
 // It uses the provided price, amount, and leverage to configure the order.
 // Accepts ctx for request lifecycle management, symbol, the asset's market symbol, amount as the quantity to buy, price for the transaction, and leverage specifying the multiplier.
 // Returns the contract ID of the placed buy order and an error if the order fails due to API issues or invalid parameters.
-func (a *API) Buy(ctx context.Context, pos executor.Position) (int, error) {
+func (a *API) Buy(ctx context.Context, pos executor.Position) (_ int, err error) {
+	if err := a.trading.wait(ctx, false); err != nil {
+		return 0, err
+	}
+
+	defer observeOrder(opBuy, time.Now(), &err)
+
 	basis := schema.BuyParametersBasisStake
 
-	res, err := a.client.Buy(ctx, schema.Buy{
+	client := a.conn()
+	res, err := client.Buy(ctx, schema.Buy{
 		Buy:   "1",
 		Price: pos.Price,
 		Parameters: &schema.BuyParameters{
@@ -30,7 +40,7 @@ func (a *API) Buy(ctx context.Context, pos executor.Position) (int, error) {
 	})
 
 	if err != nil {
-		return 0, fmt.Errorf("failed to place buy order for symbol %s: %w", pos.Symbol, err)
+		return 0, fmt.Errorf("failed to place buy order for symbol %s: %w", pos.Symbol, a.failed(client, err))
 	}
 
 	return res.Buy.ContractId, nil
@@ -40,10 +50,17 @@ func (a *API) Buy(ctx context.Context, pos executor.Position) (int, error) {
 // It uses the given price, amount, and leverage to configure the order.
 // Accepts ctx for request lifecycle management, symbol for the market asset, amount as the quantity to sell, price per unit, and leverage for multiplier configuration.
 // Returns the contract ID of the placed sell order and an error if the order fails due to API issues or invalid parameters.
-func (a *API) Sell(ctx context.Context, pos executor.Position) (int, error) {
+func (a *API) Sell(ctx context.Context, pos executor.Position) (_ int, err error) {
+	if err := a.trading.wait(ctx, false); err != nil {
+		return 0, err
+	}
+
+	defer observeOrder(opSell, time.Now(), &err)
+
 	basis := schema.BuyParametersBasisStake
 
-	res, err := a.client.Buy(ctx, schema.Buy{
+	client := a.conn()
+	res, err := client.Buy(ctx, schema.Buy{
 		Price: pos.Price,
 		Parameters: &schema.BuyParameters{
 			ContractType: schema.BuyParametersContractTypeMULTDOWN,
@@ -57,7 +74,7 @@ func (a *API) Sell(ctx context.Context, pos executor.Position) (int, error) {
 	})
 
 	if err != nil {
-		return 0, fmt.Errorf("failed to place sell order for symbol %s: %w", pos.Symbol, err)
+		return 0, fmt.Errorf("failed to place sell order for symbol %s: %w", pos.Symbol, a.failed(client, err))
 	}
 
 	return res.Buy.ContractId, nil
@@ -66,15 +83,189 @@ func (a *API) Sell(ctx context.Context, pos executor.Position) (int, error) {
 // ClosePosition closes an open trading position for a given contract ID.
 // It performs a sell operation at the market price to close the position.
 // Accepts ctx to manage request lifecycle and contractID identifying the position to close.
-// Returns an error if the API request to close the position fails.
-func (a *API) ClosePosition(ctx context.Context, contractID int) error {
-	_, err := a.client.Sell(ctx, schema.Sell{
+// Returns the amount the contract was sold for and an error if the API request to close the position fails.
+func (a *API) ClosePosition(ctx context.Context, contractID int) (_ float64, err error) {
+	// Closing limits the risk of an open position, so it is served before queued orders.
+	if err := a.trading.wait(ctx, true); err != nil {
+		return 0, err
+	}
+
+	defer observeOrder(opClose, time.Now(), &err)
+
+	client := a.conn()
+	res, err := client.Sell(ctx, schema.Sell{
 		Sell:  contractID,
 		Price: 0, // Sell at market price, we may want to allow specifying a price in the future
 	})
 
 	if err != nil {
-		return fmt.Errorf("failed to close position %d: %w", contractID, err)
+		return 0, fmt.Errorf("failed to close position %d: %w", contractID, a.failed(client, err))
+	}
+
+	if res.Sell == nil || res.Sell.SoldFor == nil {
+		return 0, nil
+	}
+
+	return *res.Sell.SoldFor, nil
+}
+
+// Portfolio retrieves all open contracts of the currently authorized account.
+// Accepts ctx to manage the request lifecycle.
+// Returns the list of open contracts and an error if the API request fails.
+func (a *API) Portfolio(ctx context.Context) ([]executor.Contract, error) {
+	if err := a.trading.wait(ctx, false); err != nil {
+		return nil, err
+	}
+
+	client := a.conn()
+	res, err := client.Portfolio(ctx, schema.Portfolio{Portfolio: 1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portfolio: %w", a.failed(client, err))
+	}
+
+	if res.Portfolio == nil {
+		return nil, fmt.Errorf("empty portfolio response")
+	}
+
+	contracts := make([]executor.Contract, 0, len(res.Portfolio.Contracts))
+
+	for _, c := range res.Portfolio.Contracts {
+		contract := executor.Contract{}
+
+		if c.ContractId != nil {
+			contract.ID = *c.ContractId
+		}
+
+		if c.Symbol != nil {
+			contract.Symbol = *c.Symbol
+		}
+
+		if c.ContractType != nil {
+			contract.ContractType = *c.ContractType
+		}
+
+		if c.Currency != nil {
+			contract.Currency = *c.Currency
+		}
+
+		if c.BuyPrice != nil {
+			contract.BuyPrice = *c.BuyPrice
+		}
+
+		if c.PurchaseTime != nil {
+			contract.PurchasedAt = time.Unix(int64(*c.PurchaseTime), 0)
+		}
+
+		if c.Shortcode != nil {
+			contract.Multiplier = shortcodeMultiplier(*c.Shortcode, contract.ContractType, contract.Symbol)
+		}
+
+		contracts = append(contracts, contract)
+	}
+
+	return contracts, nil
+}
+
+// shortcodeMultiplier extracts the multiplier of a multiplier contract from its shortcode, the portfolio doesn't
+// report it otherwise. Shortcodes of multiplier contracts start with TYPE_SYMBOL_STAKE_MULTIPLIER,
+// e.g. MULTUP_R_100_10.00_150_1700000000_4853980799_0_0.00.
+// Returns zero if the shortcode doesn't describe a multiplier contract of the given type and symbol.
+func shortcodeMultiplier(shortcode, contractType, symbol string) float64 {
+	rest, ok := strings.CutPrefix(shortcode, contractType+"_"+symbol+"_")
+	if !ok {
+		return 0
+	}
+
+	fields := strings.SplitN(rest, "_", 3)
+	if len(fields) < 2 {
+		return 0
+	}
+
+	multiplier, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return 0
+	}
+
+	return multiplier
+}
+
+// soldContractsLimit bounds the number of profit table entries requested for reconciliation.
+const soldContractsLimit = 50
+
+// SoldContracts retrieves contracts of the currently authorized account sold since the given time,
+// newest first, as recorded in the profit table.
+// Returns the list of sold contracts and an error if the API request fails.
+func (a *API) SoldContracts(ctx context.Context, since time.Time) ([]executor.SoldContract, error) {
+	from := strconv.FormatInt(since.Unix(), 10)
+
+	if err := a.trading.wait(ctx, false); err != nil {
+		return nil, err
+	}
+
+	client := a.conn()
+	res, err := client.ProfitTable(ctx, schema.ProfitTable{
+		ProfitTable: 1,
+		DateFrom:    &from,
+		Limit:       soldContractsLimit,
+		Sort:        schema.ProfitTableSortDESC,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profit table: %w", a.failed(client, err))
+	}
+
+	if res.ProfitTable == nil {
+		return nil, fmt.Errorf("empty profit table response")
+	}
+
+	contracts := make([]executor.SoldContract, 0, len(res.ProfitTable.Transactions))
+
+	for _, t := range res.ProfitTable.Transactions {
+		if t.ContractId == nil || t.SellPrice == nil {
+			continue
+		}
+
+		contract := executor.SoldContract{
+			ID:        *t.ContractId,
+			SellPrice: *t.SellPrice,
+		}
+
+		if t.SellTime != nil {
+			contract.SoldAt = time.Unix(int64(*t.SellTime), 0)
+		}
+
+		contracts = append(contracts, contract)
+	}
+
+	return contracts, nil
+}
+
+// SetLimits attaches native take profit and stop loss orders to an open multiplier contract.
+// takeProfit and stopLoss are amounts in account currency, zero leaves the corresponding order unset.
+// Like closing, it protects an open position, so it is served before queued orders.
+// Returns an error if the API request fails.
+func (a *API) SetLimits(ctx context.Context, contractID int, takeProfit, stopLoss float64) error {
+	limits := schema.ContractUpdateLimitOrder{}
+
+	if takeProfit > 0 {
+		limits.TakeProfit = &takeProfit
+	}
+
+	if stopLoss > 0 {
+		limits.StopLoss = &stopLoss
+	}
+
+	if err := a.trading.wait(ctx, true); err != nil {
+		return err
+	}
+
+	client := a.conn()
+	_, err := client.ContractUpdate(ctx, schema.ContractUpdate{
+		ContractUpdate: 1,
+		ContractId:     contractID,
+		LimitOrder:     limits,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set limits for contract %d: %w", contractID, a.failed(client, err))
 	}
 
 	return nil
@@ -0,0 +1,144 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
+	"github.com/ksysoev/deriv-bot/pkg/core/notifier"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type capturedRequest struct {
+	header http.Header
+	path   string
+	body   []byte
+}
+
+func newStandIn(t *testing.T, status int) (*httptest.Server, <-chan capturedRequest) {
+	t.Helper()
+
+	requests := make(chan capturedRequest, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		requests <- capturedRequest{path: r.URL.Path, header: r.Header, body: body}
+
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+
+	t.Cleanup(srv.Close)
+
+	return srv, requests
+}
+
+func testNotification() notifier.Notification {
+	return notifier.Format(event.New(event.TypePositionClosed, event.PositionClosed{
+		Strategy:   "r100-long",
+		Symbol:     "R_100",
+		Profit:     1.5,
+		ContractID: 42,
+	}))
+}
+
+func TestTelegram_Send(t *testing.T) {
+	srv, requests := newStandIn(t, http.StatusOK)
+
+	sink := NewTelegram(srv.URL, "bot-token", "-100")
+	require.NoError(t, sink.Send(context.Background(), testNotification()))
+
+	req := <-requests
+	assert.Equal(t, "/botbot-token/sendMessage", req.path)
+
+	var msg map[string]string
+
+	require.NoError(t, json.Unmarshal(req.body, &msg))
+	assert.Equal(t, "-100", msg["chat_id"])
+	assert.Equal(t, "MarkdownV2", msg["parse_mode"])
+	assert.Equal(t, "*Position closed*\nr100\\-long: R\\_100 contract 42 closed with profit 1\\.50", msg["text"])
+}
+
+func TestEscapeMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain text", in: "profit 15", want: "profit 15"},
+		{name: "reserved characters", in: "r100-long: 1.50 (R_100)!", want: `r100\-long: 1\.50 \(R\_100\)\!`},
+		{name: "backslash", in: `C:\bot\log_1`, want: `C:\\bot\\log\_1`},
+		{name: "escaped character", in: `\_`, want: `\\\_`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, escapeMarkdown(tt.in))
+		})
+	}
+}
+
+func TestSlack_Send(t *testing.T) {
+	srv, requests := newStandIn(t, http.StatusOK)
+
+	require.NoError(t, NewSlack(srv.URL+"/services/T/B/X").Send(context.Background(), testNotification()))
+
+	req := <-requests
+	assert.Equal(t, "/services/T/B/X", req.path)
+	assert.JSONEq(t, `{"text":"*Position closed*\nr100-long: R_100 contract 42 closed with profit 1.50"}`, string(req.body))
+}
+
+func TestWebhook_Send(t *testing.T) {
+	srv, requests := newStandIn(t, http.StatusNoContent)
+
+	sink := NewWebhook(srv.URL, "hmac-secret")
+	sink.now = func() time.Time { return time.Unix(1792000000, 0) }
+
+	require.NoError(t, sink.Send(context.Background(), testNotification()))
+
+	req := <-requests
+	assert.Equal(t, "position_closed", req.header.Get(EventHeader))
+	assert.Equal(t, "1792000000", req.header.Get(TimestampHeader))
+
+	mac := hmac.New(sha256.New, []byte("hmac-secret"))
+	mac.Write([]byte("1792000000." + string(req.body)))
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), req.header.Get(SignatureHeader))
+
+	var payload struct {
+		Event struct {
+			Payload event.PositionClosed `json:"payload"`
+			Type    event.Type           `json:"type"`
+		} `json:"event"`
+		Title string `json:"title"`
+	}
+
+	require.NoError(t, json.Unmarshal(req.body, &payload))
+	assert.Equal(t, event.TypePositionClosed, payload.Event.Type)
+	assert.Equal(t, 42, payload.Event.Payload.ContractID)
+	assert.Equal(t, "Position closed", payload.Title)
+}
+
+func TestWebhook_SendWithoutSecret(t *testing.T) {
+	srv, requests := newStandIn(t, http.StatusOK)
+
+	require.NoError(t, NewWebhook(srv.URL, "").Send(context.Background(), testNotification()))
+	assert.Empty(t, (<-requests).header.Get(SignatureHeader))
+}
+
+func TestSink_ErrorStatus(t *testing.T) {
+	srv, _ := newStandIn(t, http.StatusTooManyRequests)
+
+	err := NewSlack(srv.URL).Send(context.Background(), testNotification())
+	assert.ErrorContains(t, err, "unexpected response status 429")
+}
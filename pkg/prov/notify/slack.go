@@ -0,0 +1,36 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/notifier"
+)
+
+type Slack struct {
+	client     *http.Client
+	webhookURL string
+}
+
+// NewSlack creates a sink posting notifications to a Slack incoming webhook.
+// webhookURL is the secret URL of the incoming webhook.
+func NewSlack(webhookURL string) *Slack {
+	return &Slack{
+		client:     &http.Client{Timeout: defaultTimeout},
+		webhookURL: webhookURL,
+	}
+}
+
+// Send posts the notification to the Slack channel bound to the webhook.
+// Returns an error if the webhook request fails.
+func (s *Slack) Send(ctx context.Context, n notifier.Notification) error {
+	err := postJSON(ctx, s.client, s.webhookURL, map[string]string{
+		"text": fmt.Sprintf("*%s*\n%s", n.Title, n.Text),
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to send slack message: %w", err)
+	}
+
+	return nil
+}
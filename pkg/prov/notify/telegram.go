@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/notifier"
+	"github.com/ksysoev/deriv-bot/pkg/prov/telegram"
+)
+
+type Telegram struct {
+	client *telegram.Client
+	chatID string
+}
+
+// NewTelegram creates a sink sending notifications to a Telegram chat through the Bot API.
+// baseURL overrides the Bot API address, it defaults to the public Telegram API when empty.
+// token is the bot token and chatID identifies the chat to post to.
+func NewTelegram(baseURL, token, chatID string) *Telegram {
+	return &Telegram{
+		client: telegram.New(baseURL, token),
+		chatID: chatID,
+	}
+}
+
+// Send posts the notification as a message to the configured chat.
+// Returns an error if the Bot API request fails.
+func (t *Telegram) Send(ctx context.Context, n notifier.Notification) error {
+	err := t.client.SendMessage(ctx, telegram.Message{
+		ChatID:    t.chatID,
+		Text:      fmt.Sprintf("*%s*\n%s", escapeMarkdown(n.Title), escapeMarkdown(n.Text)),
+		ParseMode: "MarkdownV2",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send telegram message: %w", err)
+	}
+
+	return nil
+}
+
+var markdownEscaper = strings.NewReplacer(
+	"\\", "\\\\", "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`, "~", `\~`, "`", "\\`", ">", `\>`,
+	"#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`, "|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+)
+
+// escapeMarkdown escapes characters reserved by the Telegram MarkdownV2 format.
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
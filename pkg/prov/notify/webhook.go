@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/event"
+	"github.com/ksysoev/deriv-bot/pkg/core/notifier"
+)
+
+const (
+	SignatureHeader = "X-Bot-Signature-256"
+	TimestampHeader = "X-Bot-Timestamp"
+	EventHeader     = "X-Bot-Event"
+)
+
+type Webhook struct {
+	client *http.Client
+	now    func() time.Time
+	url    string
+	secret []byte
+}
+
+// NewWebhook creates a sink posting events as JSON to a generic HTTP endpoint.
+// Every request carries its Unix time in seconds in the X-Bot-Timestamp header. When secret is not empty,
+// the request is signed with HMAC-SHA256 of the timestamp and the body, sent in the X-Bot-Signature-256 header
+// as "sha256=<hex>", so receivers can reject replayed requests with an old timestamp.
+func NewWebhook(url, secret string) *Webhook {
+	return &Webhook{
+		client: &http.Client{Timeout: defaultTimeout},
+		now:    time.Now,
+		url:    url,
+		secret: []byte(secret),
+	}
+}
+
+// Send posts the event with its formatted text to the webhook endpoint.
+// Returns an error if the request fails or the endpoint responds with a non 2xx status.
+func (w *Webhook) Send(ctx context.Context, n notifier.Notification) error {
+	data, err := json.Marshal(struct {
+		Event event.Event `json:"event"`
+		Title string      `json:"title"`
+		Text  string      `json:"text"`
+	}{
+		Event: n.Event,
+		Title: n.Title,
+		Text:  n.Text,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(w.now().Unix(), 10)
+	headers := map[string]string{EventHeader: string(n.Event.Type), TimestampHeader: timestamp}
+
+	if len(w.secret) > 0 {
+		headers[SignatureHeader] = "sha256=" + Sign(w.secret, timestamp, data)
+	}
+
+	if err := post(ctx, w.client, w.url, data, headers); err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of timestamp, a dot and body using secret, as sent in the signature
+// header. timestamp is the value of the timestamp header.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,276 @@
+// Package paper simulates trading of multiplier contracts against locally known quotes, without placing real orders.
+package paper
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/executor"
+)
+
+const (
+	defaultBalance  = 10_000.0
+	defaultCurrency = "USD"
+	accountID       = "PAPER"
+
+	contractUp   = executor.ContractTypeUp
+	contractDown = executor.ContractTypeDown
+)
+
+// Errors wrap the executor error classes, so strategies react to them as to failures of a real account.
+var (
+	ErrNoQuote             = fmt.Errorf("%w: no quote for symbol", executor.ErrTransient)
+	ErrInsufficientBalance = fmt.Errorf("%w on paper account", executor.ErrInsufficientFunds)
+	ErrContractNotFound    = fmt.Errorf("%w: contract not found", executor.ErrInvalidParams)
+)
+
+// Config defines the simulated account.
+type Config struct {
+	Currency string  `mapstructure:"currency"`
+	Balance  float64 `mapstructure:"balance"`
+}
+
+type QuoteSource interface {
+	Quote(symbol string) (float64, bool)
+}
+
+type contract struct {
+	executor.Contract
+	entry      float64
+	takeProfit float64
+	stopLoss   float64
+}
+
+type Trader struct {
+	quotes    QuoteSource
+	contracts map[int]*contract
+	sold      []executor.SoldContract
+	currency  string
+	balance   float64
+	lastID    int
+	mu        sync.Mutex
+}
+
+// New creates a paper trading provider filling orders at the quotes of quotes.
+// Returns a pointer to the initialized Trader.
+func New(cfg Config, quotes QuoteSource) *Trader {
+	if cfg.Currency == "" {
+		cfg.Currency = defaultCurrency
+	}
+
+	if cfg.Balance == 0 {
+		cfg.Balance = defaultBalance
+	}
+
+	return &Trader{
+		quotes:    quotes,
+		contracts: make(map[int]*contract),
+		currency:  cfg.Currency,
+		balance:   cfg.Balance,
+	}
+}
+
+// Authorize accepts any token and returns the simulated account.
+func (t *Trader) Authorize(_ context.Context, _ string) (*executor.Account, error) {
+	return &executor.Account{ID: accountID, Currency: t.currency}, nil
+}
+
+// Buy opens a simulated long multiplier contract at the last quote of the symbol.
+// Returns the contract ID and an error if there is no quote yet or the balance is insufficient.
+func (t *Trader) Buy(_ context.Context, pos executor.Position) (int, error) {
+	return t.open(pos, contractUp)
+}
+
+// Sell opens a simulated short multiplier contract at the last quote of the symbol.
+// Returns the contract ID and an error if there is no quote yet or the balance is insufficient.
+func (t *Trader) Sell(_ context.Context, pos executor.Position) (int, error) {
+	return t.open(pos, contractDown)
+}
+
+func (t *Trader) open(pos executor.Position, contractType string) (int, error) {
+	quote, ok := t.quotes.Quote(pos.Symbol)
+	if !ok {
+		return 0, fmt.Errorf("%w %s", ErrNoQuote, pos.Symbol)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if pos.Amount > t.balance {
+		return 0, fmt.Errorf("%w: stake %.2f, balance %.2f", ErrInsufficientBalance, pos.Amount, t.balance)
+	}
+
+	t.lastID++
+	t.balance -= pos.Amount
+
+	t.contracts[t.lastID] = &contract{
+		Contract: executor.Contract{
+			PurchasedAt:  time.Now(),
+			Symbol:       pos.Symbol,
+			ContractType: contractType,
+			Currency:     t.currency,
+			BuyPrice:     pos.Amount,
+			Multiplier:   pos.Leverage,
+			ID:           t.lastID,
+		},
+		entry: quote,
+	}
+
+	return t.lastID, nil
+}
+
+// ClosePosition closes a simulated contract at the last quote of its symbol.
+// Returns the amount the contract was sold for and an error if the contract is not open,
+// e.g. because it was stopped out or reached its take profit or stop loss.
+func (t *Trader) ClosePosition(_ context.Context, contractID int) (float64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.settleAll()
+
+	c, ok := t.contracts[contractID]
+	if !ok {
+		return 0, fmt.Errorf("%w: %d", ErrContractNotFound, contractID)
+	}
+
+	quote, ok := t.quotes.Quote(c.Symbol)
+	if !ok {
+		quote = c.entry
+	}
+
+	return t.sell(c, quote), nil
+}
+
+// SetLimits sets take profit and stop loss amounts of a contract, zero amounts disable the limit.
+// The contract is sold once its profit reaches take profit or its loss reaches stop loss.
+// Returns an error if the contract is not open.
+func (t *Trader) SetLimits(_ context.Context, contractID int, takeProfit, stopLoss float64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.settleAll()
+
+	c, ok := t.contracts[contractID]
+	if !ok {
+		return fmt.Errorf("%w: %d", ErrContractNotFound, contractID)
+	}
+
+	c.takeProfit, c.stopLoss = takeProfit, stopLoss
+
+	if quote, ok := t.quotes.Quote(c.Symbol); ok {
+		t.check(c, quote)
+	}
+
+	return nil
+}
+
+// Update evaluates the open contracts of symbol at quote, like the platform does on every tick:
+// contracts which lost their stake are stopped out, and ones reaching take profit or stop loss are sold.
+func (t *Trader) Update(symbol string, quote float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.settle(symbol, quote)
+}
+
+// settleAll evaluates all open contracts at the last quotes of their symbols.
+// It is called with t.mu held, so contracts are settled even if quote updates are not delivered to Update.
+func (t *Trader) settleAll() {
+	for _, c := range t.contracts {
+		if quote, ok := t.quotes.Quote(c.Symbol); ok {
+			t.check(c, quote)
+		}
+	}
+}
+
+// settle evaluates the open contracts of symbol at quote. It is called with t.mu held.
+func (t *Trader) settle(symbol string, quote float64) {
+	for _, c := range t.contracts {
+		if c.Symbol == symbol {
+			t.check(c, quote)
+		}
+	}
+}
+
+// check sells contract c if it is stopped out or reaches its take profit or stop loss at quote.
+// It is called with t.mu held.
+func (t *Trader) check(c *contract, quote float64) {
+	p := profit(c, quote)
+
+	if p <= -c.BuyPrice || (c.takeProfit > 0 && p >= c.takeProfit) || (c.stopLoss > 0 && p <= -c.stopLoss) {
+		t.sell(c, quote)
+	}
+}
+
+// sell closes contract c at quote and credits the amount it was sold for to the balance.
+// It is called with t.mu held. Returns the amount the contract was sold for.
+func (t *Trader) sell(c *contract, quote float64) float64 {
+	soldFor := c.BuyPrice + profit(c, quote)
+
+	delete(t.contracts, c.ID)
+	t.balance += soldFor
+	t.sold = append(t.sold, executor.SoldContract{ID: c.ID, SellPrice: soldFor, SoldAt: time.Now()})
+
+	return soldFor
+}
+
+// Balance returns the simulated account balance.
+func (t *Trader) Balance(_ context.Context) (*executor.Balance, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.settleAll()
+
+	return &executor.Balance{LoginID: accountID, Currency: t.currency, Amount: t.balance}, nil
+}
+
+// Portfolio returns the open simulated contracts ordered by ID.
+func (t *Trader) Portfolio(_ context.Context) ([]executor.Contract, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.settleAll()
+
+	contracts := make([]executor.Contract, 0, len(t.contracts))
+	for _, c := range t.contracts {
+		contracts = append(contracts, c.Contract)
+	}
+
+	sort.Slice(contracts, func(i, j int) bool { return contracts[i].ID < contracts[j].ID })
+
+	return contracts, nil
+}
+
+// SoldContracts returns the simulated contracts sold at or after since, newest first.
+func (t *Trader) SoldContracts(_ context.Context, since time.Time) ([]executor.SoldContract, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.settleAll()
+
+	var sold []executor.SoldContract
+
+	for _, c := range slices.Backward(t.sold) {
+		if !c.SoldAt.Before(since) {
+			sold = append(sold, c)
+		}
+	}
+
+	return sold, nil
+}
+
+// profit calculates the profit of a multiplier contract at quote, the loss is capped by the stake
+// at which the contract is stopped out.
+func profit(c *contract, quote float64) float64 {
+	change := (quote - c.entry) / c.entry
+	if c.ContractType == contractDown {
+		change = -change
+	}
+
+	return math.Max(c.BuyPrice*c.Multiplier*change, -c.BuyPrice)
+}
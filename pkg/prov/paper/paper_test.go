@@ -0,0 +1,222 @@
+package paper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/executor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQuotes map[string]float64
+
+func (q fakeQuotes) Quote(symbol string) (float64, bool) {
+	v, ok := q[symbol]
+	return v, ok
+}
+
+func TestTrader_OpenAndClose(t *testing.T) {
+	tests := []struct {
+		name        string
+		exit        float64
+		wantSoldFor float64
+		sell        bool
+	}{
+		{name: "buy profit", exit: 101, wantSoldFor: 20},
+		{name: "sell profit", exit: 99, wantSoldFor: 20, sell: true},
+		{name: "loss", exit: 99.5, wantSoldFor: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quotes := fakeQuotes{"R_100": 100}
+			trader := New(Config{Balance: 1000}, quotes)
+
+			open := trader.Buy
+			if tt.sell {
+				open = trader.Sell
+			}
+
+			id, err := open(context.Background(), executor.Position{Symbol: "R_100", Amount: 10, Leverage: 100})
+			require.NoError(t, err)
+
+			portfolio, err := trader.Portfolio(context.Background())
+			require.NoError(t, err)
+			require.Len(t, portfolio, 1)
+			assert.Equal(t, id, portfolio[0].ID)
+
+			quotes["R_100"] = tt.exit
+
+			soldFor, err := trader.ClosePosition(context.Background(), id)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.wantSoldFor, soldFor, 1e-9)
+
+			balance, err := trader.Balance(context.Background())
+			require.NoError(t, err)
+			assert.InDelta(t, 990+tt.wantSoldFor, balance.Amount, 1e-9)
+			assert.Equal(t, "USD", balance.Currency)
+
+			sold, err := trader.SoldContracts(context.Background(), time.Now().Add(-time.Minute))
+			require.NoError(t, err)
+			require.Len(t, sold, 1)
+			assert.Equal(t, id, sold[0].ID)
+			assert.InDelta(t, tt.wantSoldFor, sold[0].SellPrice, 1e-9)
+
+			_, err = trader.ClosePosition(context.Background(), id)
+			assert.ErrorIs(t, err, ErrContractNotFound)
+		})
+	}
+}
+
+func TestTrader_StopOut(t *testing.T) {
+	tests := []struct {
+		name     string
+		settle   func(t *testing.T, trader *Trader, id int)
+		quote    float64
+		wantOpen bool
+	}{
+		{
+			name:   "on quote update",
+			quote:  100,
+			settle: func(_ *testing.T, trader *Trader, _ int) { trader.Update("R_100", 98) },
+		},
+		{
+			name:  "on portfolio",
+			quote: 99,
+			settle: func(t *testing.T, trader *Trader, _ int) {
+				t.Helper()
+
+				portfolio, err := trader.Portfolio(context.Background())
+				require.NoError(t, err)
+				assert.Empty(t, portfolio)
+			},
+		},
+		{
+			name:  "on close",
+			quote: 99,
+			settle: func(t *testing.T, trader *Trader, id int) {
+				t.Helper()
+
+				_, err := trader.ClosePosition(context.Background(), id)
+				assert.ErrorIs(t, err, ErrContractNotFound)
+			},
+		},
+		{
+			name:     "other symbol",
+			quote:    100,
+			settle:   func(_ *testing.T, trader *Trader, _ int) { trader.Update("R_50", 98) },
+			wantOpen: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quotes := fakeQuotes{"R_100": 100}
+			trader := New(Config{Balance: 1000}, quotes)
+
+			id, err := trader.Buy(context.Background(), executor.Position{Symbol: "R_100", Amount: 10, Leverage: 100})
+			require.NoError(t, err)
+
+			// Once the quote dropped by 1% the contract lost its stake.
+			quotes["R_100"] = tt.quote
+			tt.settle(t, trader, id)
+
+			sold, err := trader.SoldContracts(context.Background(), time.Now().Add(-time.Minute))
+			require.NoError(t, err)
+
+			if tt.wantOpen {
+				assert.Empty(t, sold)
+				return
+			}
+
+			require.Len(t, sold, 1)
+			assert.Equal(t, id, sold[0].ID)
+			assert.InDelta(t, 0, sold[0].SellPrice, 1e-9)
+
+			balance, err := trader.Balance(context.Background())
+			require.NoError(t, err)
+			assert.InDelta(t, 990, balance.Amount, 1e-9)
+		})
+	}
+}
+
+func TestTrader_SetLimits(t *testing.T) {
+	tests := []struct {
+		name        string
+		quote       float64
+		takeProfit  float64
+		stopLoss    float64
+		wantSoldFor float64
+		wantOpen    bool
+	}{
+		{name: "take profit", quote: 100.5, takeProfit: 5, wantSoldFor: 15},
+		{name: "stop loss", quote: 99.6, stopLoss: 3, wantSoldFor: 6},
+		{name: "within limits", quote: 100.2, takeProfit: 5, stopLoss: 3, wantOpen: true},
+		{name: "no limits", quote: 100.5, wantOpen: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trader := New(Config{Balance: 1000}, fakeQuotes{"R_100": 100})
+
+			id, err := trader.Buy(context.Background(), executor.Position{Symbol: "R_100", Amount: 10, Leverage: 100})
+			require.NoError(t, err)
+
+			require.NoError(t, trader.SetLimits(context.Background(), id, tt.takeProfit, tt.stopLoss))
+
+			trader.Update("R_100", tt.quote)
+
+			portfolio, err := trader.Portfolio(context.Background())
+			require.NoError(t, err)
+
+			if tt.wantOpen {
+				assert.Len(t, portfolio, 1)
+				return
+			}
+
+			assert.Empty(t, portfolio)
+
+			sold, err := trader.SoldContracts(context.Background(), time.Now().Add(-time.Minute))
+			require.NoError(t, err)
+			require.Len(t, sold, 1)
+			assert.InDelta(t, tt.wantSoldFor, sold[0].SellPrice, 1e-9)
+		})
+	}
+}
+
+func TestTrader_SetLimitsReached(t *testing.T) {
+	quotes := fakeQuotes{"R_100": 100}
+	trader := New(Config{Balance: 1000}, quotes)
+
+	id, err := trader.Buy(context.Background(), executor.Position{Symbol: "R_100", Amount: 10, Leverage: 100})
+	require.NoError(t, err)
+
+	// Limits already reached when they are set sell the contract right away.
+	quotes["R_100"] = 100.5
+	require.NoError(t, trader.SetLimits(context.Background(), id, 5, 3))
+
+	_, err = trader.ClosePosition(context.Background(), id)
+	assert.ErrorIs(t, err, ErrContractNotFound)
+
+	sold, err := trader.SoldContracts(context.Background(), time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	require.Len(t, sold, 1)
+	assert.InDelta(t, 15, sold[0].SellPrice, 1e-9)
+}
+
+func TestTrader_Errors(t *testing.T) {
+	trader := New(Config{Balance: 5}, fakeQuotes{"R_100": 100})
+
+	_, err := trader.Buy(context.Background(), executor.Position{Symbol: "R_50", Amount: 1, Leverage: 10})
+	assert.ErrorIs(t, err, ErrNoQuote)
+
+	_, err = trader.Buy(context.Background(), executor.Position{Symbol: "R_100", Amount: 10, Leverage: 10})
+	assert.ErrorIs(t, err, ErrInsufficientBalance)
+	assert.ErrorIs(t, err, executor.ErrInsufficientFunds)
+
+	assert.ErrorIs(t, trader.SetLimits(context.Background(), 1, 5, 3), ErrContractNotFound)
+}
+
+var _ executor.TradingProvider = (*Trader)(nil)
@@ -0,0 +1,172 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+)
+
+const (
+	keystoreVersion = 1
+	kdfIterations   = 600_000
+	keyLength       = 32
+	saltLength      = 16
+)
+
+type keystoreFile struct {
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+	Version    int    `json:"version"`
+	Iterations int    `json:"iterations"`
+}
+
+// Keystore is a local file with named secrets encrypted with AES-256-GCM.
+// The encryption key is derived from a passphrase with PBKDF2-SHA256.
+type Keystore struct {
+	entries    map[string]string
+	path       string
+	passphrase string
+}
+
+// OpenKeystore opens and decrypts the keystore at path using passphrase.
+// If the file does not exist, an empty keystore is returned and the file is created on the first Save.
+// Returns an error if the file cannot be read, has an unsupported format or the passphrase is wrong.
+func OpenKeystore(path, passphrase string) (*Keystore, error) {
+	ks := &Keystore{
+		path:       path,
+		passphrase: passphrase,
+		entries:    make(map[string]string),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ks, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+
+	var file keystoreFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse keystore: %w", err)
+	}
+
+	if file.Version != keystoreVersion {
+		return nil, fmt.Errorf("unsupported keystore version %d", file.Version)
+	}
+
+	gcm, err := newCipher(passphrase, file.Salt, file.Iterations)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore, wrong passphrase?")
+	}
+
+	if err := json.Unmarshal(plain, &ks.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse keystore entries: %w", err)
+	}
+
+	return ks, nil
+}
+
+// Get returns the secret stored under name and whether it exists.
+func (k *Keystore) Get(name string) (string, bool) {
+	val, ok := k.entries[name]
+
+	return val, ok
+}
+
+// Set stores val under name, replacing any existing entry. Changes are persisted only by Save.
+func (k *Keystore) Set(name, val string) {
+	k.entries[name] = val
+}
+
+// Delete removes the entry stored under name and reports whether it existed. Changes are persisted only by Save.
+func (k *Keystore) Delete(name string) bool {
+	_, ok := k.entries[name]
+	delete(k.entries, name)
+
+	return ok
+}
+
+// Names returns the sorted names of all stored secrets.
+func (k *Keystore) Names() []string {
+	names := make([]string, 0, len(k.entries))
+	for name := range k.entries {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Save encrypts the keystore with a fresh salt and nonce and writes it to disk with owner-only permissions.
+// Returns an error if encryption or writing the file fails.
+func (k *Keystore) Save() error {
+	plain, err := json.Marshal(k.entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode keystore entries: %w", err)
+	}
+
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	gcm, err := newCipher(k.passphrase, salt, kdfIterations)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	data, err := json.Marshal(keystoreFile{
+		Version:    keystoreVersion,
+		Iterations: kdfIterations,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plain, nil),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode keystore: %w", err)
+	}
+
+	if err := os.WriteFile(k.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write keystore: %w", err)
+	}
+
+	return nil
+}
+
+func newCipher(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, keyLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive keystore key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create keystore cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create keystore cipher: %w", err)
+	}
+
+	return gcm, nil
+}
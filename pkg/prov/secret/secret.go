@@ -0,0 +1,204 @@
+package secret
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	redacted = "[REDACTED]"
+
+	schemeEnv      = "env"
+	schemeFile     = "file"
+	schemeKeystore = "keystore"
+
+	defaultPassphraseEnv = "BOT_KEYSTORE_PASSPHRASE"
+)
+
+type Config struct {
+	KeystorePath  string `mapstructure:"keystore_path"`
+	PassphraseEnv string `mapstructure:"passphrase_env"`
+}
+
+// Value holds a resolved secret. It never prints its content through fmt, slog or encoders,
+// the raw secret is only available through Reveal.
+type Value string
+
+// Reveal returns the raw secret value.
+func (v Value) Reveal() string {
+	return string(v)
+}
+
+// String returns a redacted representation of the secret.
+func (v Value) String() string {
+	return redacted
+}
+
+// GoString returns a redacted representation of the secret for the %#v verb.
+func (v Value) GoString() string {
+	return redacted
+}
+
+// LogValue returns a redacted representation of the secret for structured logging.
+func (v Value) LogValue() slog.Value {
+	return slog.StringValue(redacted)
+}
+
+// MarshalText returns a redacted representation of the secret for text and JSON encoders.
+func (v Value) MarshalText() ([]byte, error) {
+	return []byte(redacted), nil
+}
+
+type Resolver struct {
+	keystore *Keystore
+	known    map[string]struct{}
+	cfg      Config
+	mu       sync.RWMutex
+	ksMu     sync.Mutex
+}
+
+// New creates a Resolver for secret references using the provided configuration.
+// The keystore is opened lazily on the first keystore reference.
+func New(cfg Config) *Resolver {
+	if cfg.PassphraseEnv == "" {
+		cfg.PassphraseEnv = defaultPassphraseEnv
+	}
+
+	return &Resolver{cfg: cfg, known: make(map[string]struct{})}
+}
+
+// Resolve returns the secret pointed to by ref.
+// Supported references are "env:NAME" for environment variables, "file:/path" for files such as Docker or Kubernetes
+// secret mounts, and "keystore:name" for entries of the encrypted local keystore.
+// Every resolved value is remembered, so Redact can scrub it from arbitrary strings afterwards.
+// Returns an error if the reference is malformed or the secret cannot be found or is empty.
+func (r *Resolver) Resolve(ref string) (Value, error) {
+	if err := ValidateRef(ref); err != nil {
+		return "", err
+	}
+
+	scheme, name, _ := strings.Cut(ref, ":")
+
+	var (
+		val string
+		err error
+	)
+
+	switch scheme {
+	case schemeEnv:
+		val, err = fromEnv(name)
+	case schemeFile:
+		val, err = fromFile(name)
+	default:
+		val, err = r.fromKeystore(name)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if val == "" {
+		return "", fmt.Errorf("secret %q is empty", ref)
+	}
+
+	r.mu.Lock()
+	r.known[val] = struct{}{}
+	r.mu.Unlock()
+
+	return Value(val), nil
+}
+
+// ValidateRef checks that ref is a well-formed secret reference with a supported scheme, without resolving it.
+// Returns an error describing the problem, the reference itself is never included.
+func ValidateRef(ref string) error {
+	scheme, name, ok := strings.Cut(ref, ":")
+	if !ok || name == "" {
+		// The reference is not echoed back, it may be a raw secret pasted into the config by mistake.
+		return fmt.Errorf("invalid secret reference, expected <env|file|keystore>:<name>")
+	}
+
+	switch scheme {
+	case schemeEnv, schemeFile, schemeKeystore:
+		return nil
+	default:
+		return fmt.Errorf("unsupported secret scheme %q", scheme)
+	}
+}
+
+// Redact replaces every occurrence of previously resolved secrets in s with a redaction marker.
+func (r *Resolver) Redact(s string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for val := range r.known {
+		s = strings.ReplaceAll(s, val, redacted)
+	}
+
+	return s
+}
+
+// Keystore opens the encrypted local keystore configured for the resolver.
+// The passphrase is read from the environment variable named in the configuration.
+// The keystore is opened once and shared, a failed attempt is retried on the next call.
+// Returns an error if the keystore path or passphrase is not configured, or the keystore cannot be decrypted.
+func (r *Resolver) Keystore() (*Keystore, error) {
+	r.ksMu.Lock()
+	defer r.ksMu.Unlock()
+
+	if r.keystore != nil {
+		return r.keystore, nil
+	}
+
+	if r.cfg.KeystorePath == "" {
+		return nil, fmt.Errorf("keystore path is not configured")
+	}
+
+	passphrase := os.Getenv(r.cfg.PassphraseEnv)
+	if passphrase == "" {
+		return nil, fmt.Errorf("keystore passphrase is not set in %s", r.cfg.PassphraseEnv)
+	}
+
+	ks, err := OpenKeystore(r.cfg.KeystorePath, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	r.keystore = ks
+
+	return ks, nil
+}
+
+func (r *Resolver) fromKeystore(name string) (string, error) {
+	ks, err := r.Keystore()
+	if err != nil {
+		return "", fmt.Errorf("failed to open keystore: %w", err)
+	}
+
+	val, ok := ks.Get(name)
+	if !ok {
+		return "", fmt.Errorf("secret %q not found in keystore", name)
+	}
+
+	return val, nil
+}
+
+func fromEnv(name string) (string, error) {
+	val, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+
+	return strings.TrimSpace(val), nil
+}
+
+func fromFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
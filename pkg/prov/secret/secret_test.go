@@ -0,0 +1,124 @@
+package secret
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_Resolve(t *testing.T) {
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "token")
+
+	require.NoError(t, os.WriteFile(secretFile, []byte("file-token\n"), 0o600))
+	t.Setenv("TEST_DERIV_TOKEN", "env-token")
+	t.Setenv("TEST_EMPTY_TOKEN", "")
+
+	tests := []struct {
+		name    string
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{name: "Environment variable", ref: "env:TEST_DERIV_TOKEN", want: "env-token"},
+		{name: "File", ref: "file:" + secretFile, want: "file-token"},
+		{name: "Missing environment variable", ref: "env:TEST_MISSING_TOKEN", wantErr: true},
+		{name: "Empty environment variable", ref: "env:TEST_EMPTY_TOKEN", wantErr: true},
+		{name: "Missing file", ref: "file:" + filepath.Join(dir, "missing"), wantErr: true},
+		{name: "Raw value", ref: "raw-token", wantErr: true},
+		{name: "Unknown scheme", ref: "vault:token", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(Config{})
+
+			got, err := r.Resolve(tt.ref)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Reveal())
+			assert.Equal(t, "auth "+redacted, r.Redact("auth "+tt.want))
+		})
+	}
+}
+
+func TestResolver_Keystore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keystore.json")
+
+	ks, err := OpenKeystore(path, "passphrase")
+	require.NoError(t, err)
+
+	ks.Set("demo", "keystore-token")
+	require.NoError(t, ks.Save())
+
+	t.Setenv("TEST_KEYSTORE_PASSPHRASE", "passphrase")
+
+	r := New(Config{KeystorePath: path, PassphraseEnv: "TEST_KEYSTORE_PASSPHRASE"})
+
+	got, err := r.Resolve("keystore:demo")
+	require.NoError(t, err)
+	assert.Equal(t, "keystore-token", got.Reveal())
+
+	_, err = r.Resolve("keystore:missing")
+	assert.Error(t, err)
+
+	_, err = OpenKeystore(path, "wrong")
+	assert.Error(t, err)
+}
+
+func TestResolver_Concurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keystore.json")
+
+	ks, err := OpenKeystore(path, "passphrase")
+	require.NoError(t, err)
+
+	ks.Set("demo", "keystore-token")
+	require.NoError(t, ks.Save())
+
+	t.Setenv("TEST_KEYSTORE_PASSPHRASE", "passphrase")
+
+	r := New(Config{KeystorePath: path, PassphraseEnv: "TEST_KEYSTORE_PASSPHRASE"})
+
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := r.Resolve("keystore:demo")
+			assert.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+
+	first, err := r.Keystore()
+	require.NoError(t, err)
+
+	second, err := r.Keystore()
+	require.NoError(t, err)
+
+	assert.Same(t, first, second)
+	assert.Len(t, r.known, 1)
+}
+
+func TestValue_Redacted(t *testing.T) {
+	v := Value("token")
+
+	cfg := struct{ Token Value }{Token: v}
+
+	assert.NotContains(t, fmt.Sprintf("%v %+v %#v %s", v, cfg, cfg, v), "token")
+	assert.Equal(t, slog.StringValue(redacted), v.LogValue())
+}
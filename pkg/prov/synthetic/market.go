@@ -0,0 +1,150 @@
+// Package synthetic generates market data locally, so the bot can be developed and simulated without network access.
+package synthetic
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+)
+
+const defaultInterval = time.Second
+
+// defaultModel resembles a volatility index with 100% annual volatility.
+var defaultModel = ModelConfig{Model: ModelGBM, Start: 1000, Volatility: 1}
+
+// Config defines synthetic markets. Symbols without an entry in Symbols use the Default model,
+// which is a geometric Brownian motion with 100% annual volatility when not configured.
+// Seed makes generated prices reproducible, zero seeds the generators randomly.
+type Config struct {
+	Symbols  map[string]ModelConfig `mapstructure:"symbols"`
+	Default  ModelConfig            `mapstructure:"default"`
+	Interval time.Duration          `mapstructure:"interval"`
+	Seed     uint64                 `mapstructure:"seed"`
+}
+
+type Market struct {
+	quotes  map[string]float64
+	onQuote []func(symbol string, quote float64)
+	cfg     Config
+	mu      sync.RWMutex
+}
+
+// New creates a synthetic market provider using the provided configuration.
+// The models of all configured symbols are validated upfront.
+// Returns the initialized Market and an error if a model is invalid.
+func New(cfg Config) (*Market, error) {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultInterval
+	}
+
+	if cfg.Default == (ModelConfig{}) {
+		cfg.Default = defaultModel
+	}
+
+	if _, err := newModel("", cfg.Default, cfg.Interval); err != nil {
+		return nil, fmt.Errorf("invalid default synthetic model: %w", err)
+	}
+
+	for symbol, mc := range cfg.Symbols {
+		if _, err := newModel(symbol, mc, cfg.Interval); err != nil {
+			return nil, fmt.Errorf("invalid synthetic model for %s: %w", symbol, err)
+		}
+	}
+
+	return &Market{
+		cfg:    cfg,
+		quotes: make(map[string]float64),
+	}, nil
+}
+
+// SubscribeToTicks starts generating ticks for symbol at the configured interval.
+// Ticks are streamed until ctx is cancelled, then the channel is closed.
+// Returns a channel of generated ticks and an error if the model of the symbol is invalid.
+func (m *Market) SubscribeToTicks(ctx context.Context, symbol string) (<-chan signal.Tick, error) {
+	mc, ok := m.cfg.Symbols[symbol]
+	if !ok {
+		mc = m.cfg.Default
+	}
+
+	gen, err := newModel(symbol, mc, m.cfg.Interval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid synthetic model for %s: %w", symbol, err)
+	}
+
+	rng := m.rng(symbol)
+	ticks := make(chan signal.Tick)
+
+	go func() {
+		defer close(ticks)
+
+		ticker := time.NewTicker(m.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				q := gen.next(rng)
+				tick := signal.Tick{
+					Time:  now,
+					Quote: q,
+					Ask:   q * (1 + mc.Spread),
+					Bid:   q * (1 - mc.Spread),
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case ticks <- tick:
+				}
+
+				m.mu.Lock()
+				m.quotes[symbol] = q
+				onQuote := m.onQuote
+				m.mu.Unlock()
+
+				for _, fn := range onQuote {
+					fn(symbol, q)
+				}
+			}
+		}
+	}()
+
+	return ticks, nil
+}
+
+// OnQuote registers fn to be called with the quote of every streamed tick, e.g. to settle paper contracts.
+func (m *Market) OnQuote(fn func(symbol string, quote float64)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onQuote = append(m.onQuote, fn)
+}
+
+// Quote returns the quote of the last streamed tick of symbol, and false if no tick was streamed yet.
+func (m *Market) Quote(symbol string) (float64, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	q, ok := m.quotes[symbol]
+
+	return q, ok
+}
+
+// rng creates the random source of a symbol. With a configured seed every symbol gets its own reproducible sequence.
+func (m *Market) rng(symbol string) *rand.Rand {
+	if m.cfg.Seed == 0 {
+		return rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())) //nolint:gosec // synthetic prices, not security sensitive
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(symbol))
+
+	return rand.New(rand.NewPCG(m.cfg.Seed, h.Sum64())) //nolint:gosec // synthetic prices, not security sensitive
+}
@@ -0,0 +1,128 @@
+package synthetic
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func collect(t *testing.T, m *Market, symbol string, n int) []float64 {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ticks, err := m.SubscribeToTicks(ctx, symbol)
+	require.NoError(t, err)
+
+	quotes := make([]float64, 0, n)
+
+	for range n {
+		select {
+		case tick := <-ticks:
+			assert.GreaterOrEqual(t, tick.Ask, tick.Quote)
+			assert.LessOrEqual(t, tick.Bid, tick.Quote)
+
+			quotes = append(quotes, tick.Quote)
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for tick")
+		}
+	}
+
+	// The stream is drained until the generator stops, ticks it streamed meanwhile are not collected.
+	cancel()
+
+	for range ticks { //nolint:revive // drain until the stream is closed
+	}
+
+	return quotes
+}
+
+func TestMarket_SeedIsReproducible(t *testing.T) {
+	models := []ModelConfig{
+		{Model: ModelRandomWalk, Start: 100, Step: 0.5},
+		{Model: ModelGBM, Start: 100, Drift: 0.1, Volatility: 2},
+		{Model: ModelJump, Start: 100, Volatility: 1, JumpIntensity: 1e6, JumpStdDev: 0.01, Spread: 0.001},
+	}
+
+	for _, mc := range models {
+		t.Run(mc.Model, func(t *testing.T) {
+			cfg := Config{Default: mc, Interval: time.Millisecond, Seed: 42}
+
+			m1, err := New(cfg)
+			require.NoError(t, err)
+
+			m2, err := New(cfg)
+			require.NoError(t, err)
+
+			var streamed []float64
+
+			m1.OnQuote(func(symbol string, quote float64) {
+				if symbol == "R_100" {
+					streamed = append(streamed, quote)
+				}
+			})
+
+			quotes := collect(t, m1, "R_100", 20)
+
+			assert.Equal(t, quotes, collect(t, m2, "R_100", 20))
+			assert.NotEqual(t, quotes, collect(t, m1, "R_50", 20))
+
+			for _, q := range quotes {
+				assert.Positive(t, q)
+			}
+
+			last, ok := m1.Quote("R_100")
+			assert.True(t, ok)
+			require.GreaterOrEqual(t, len(streamed), len(quotes))
+			assert.Equal(t, quotes, streamed[:len(quotes)])
+			assert.Equal(t, streamed[len(streamed)-1], last)
+		})
+	}
+}
+
+func TestMarket_Replay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ticks.csv")
+	data := "time,quote,ask,bid,symbol\n1000,1.5,1.6,1.4,frxEURUSD\n1500,90,91,89,R_100\n2000,2.5,2.6,2.4,frxEURUSD\n"
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+	m, err := New(Config{
+		Symbols:  map[string]ModelConfig{"frxEURUSD": {Model: ModelReplay, File: path}},
+		Interval: time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	// Ticks recorded for other symbols aren't replayed.
+	assert.Equal(t, []float64{1.5, 2.5, 1.5}, collect(t, m, "frxEURUSD", 3))
+
+	_, err = New(Config{Symbols: map[string]ModelConfig{"R_50": {Model: ModelReplay, File: path}}})
+	assert.ErrorContains(t, err, "has no ticks of R_50")
+}
+
+func TestNew_InvalidModel(t *testing.T) {
+	tests := []struct {
+		model   ModelConfig
+		name    string
+		wantErr string
+	}{
+		{name: "unknown model", model: ModelConfig{Model: "sine", Start: 1}, wantErr: `unknown model "sine"`},
+		{name: "no start price", model: ModelConfig{Model: ModelGBM, Volatility: 1}, wantErr: "start price must be positive"},
+		{name: "negative volatility", model: ModelConfig{Model: ModelJump, Start: 1, Volatility: -1}, wantErr: "must not be negative"},
+		{name: "missing replay file", model: ModelConfig{Model: ModelReplay, File: "missing.csv"}, wantErr: "failed to open tick file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(Config{Symbols: map[string]ModelConfig{"R_100": tt.model}})
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+var _ signal.MarketProvider = (*Market)(nil)
@@ -0,0 +1,181 @@
+package synthetic
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/repo/tickfile"
+)
+
+const (
+	ModelRandomWalk = "random_walk"
+	ModelGBM        = "gbm"
+	ModelJump       = "jump"
+	ModelReplay     = "replay"
+)
+
+// year is the time unit of drift, volatility and jump intensity.
+const year = 365 * 24 * time.Hour
+
+// ModelConfig describes how prices of a symbol are generated.
+// Drift and Volatility are annualized, JumpIntensity is the expected number of jumps per year,
+// and JumpMean and JumpStdDev describe the distribution of log jump sizes.
+// Noise is the standard deviation of the relative noise added to replayed quotes,
+// and Spread is the relative distance of ask and bid from the quote.
+type ModelConfig struct {
+	Model         string  `mapstructure:"model"`
+	File          string  `mapstructure:"file"`
+	Start         float64 `mapstructure:"start"`
+	Step          float64 `mapstructure:"step"`
+	Drift         float64 `mapstructure:"drift"`
+	Volatility    float64 `mapstructure:"volatility"`
+	JumpIntensity float64 `mapstructure:"jump_intensity"`
+	JumpMean      float64 `mapstructure:"jump_mean"`
+	JumpStdDev    float64 `mapstructure:"jump_stddev"`
+	Noise         float64 `mapstructure:"noise"`
+	Spread        float64 `mapstructure:"spread"`
+}
+
+// model produces the next price of a synthetic market on every call.
+type model interface {
+	next(rng *rand.Rand) float64
+}
+
+// newModel creates the price model described by cfg for ticks of symbol generated every interval. A replay model
+// replays the ticks of the file recorded for symbol, or all of them when symbol is empty.
+// Returns an error if the model is unknown, its parameters are invalid or the replay file can't be loaded or has no
+// ticks of symbol.
+func newModel(symbol string, cfg ModelConfig, interval time.Duration) (model, error) {
+	dt := float64(interval) / float64(year)
+
+	if cfg.Model != ModelReplay && cfg.Start <= 0 {
+		return nil, fmt.Errorf("start price must be positive")
+	}
+
+	switch cfg.Model {
+	case ModelRandomWalk:
+		if cfg.Step <= 0 {
+			return nil, fmt.Errorf("step must be positive for %s model", cfg.Model)
+		}
+
+		return &randomWalk{price: cfg.Start, step: cfg.Step}, nil
+	case ModelGBM, "":
+		if cfg.Volatility < 0 {
+			return nil, fmt.Errorf("volatility must not be negative")
+		}
+
+		return &gbm{price: cfg.Start, drift: cfg.Drift, volatility: cfg.Volatility, dt: dt}, nil
+	case ModelJump:
+		if cfg.Volatility < 0 || cfg.JumpIntensity < 0 || cfg.JumpStdDev < 0 {
+			return nil, fmt.Errorf("volatility and jump parameters must not be negative")
+		}
+
+		return &jumpDiffusion{
+			gbm:       gbm{price: cfg.Start, drift: cfg.Drift, volatility: cfg.Volatility, dt: dt},
+			intensity: cfg.JumpIntensity,
+			mean:      cfg.JumpMean,
+			stdDev:    cfg.JumpStdDev,
+		}, nil
+	case ModelReplay:
+		records, err := tickfile.ReadFile(cfg.File)
+		if err != nil {
+			return nil, err
+		}
+
+		ticks := tickfile.Ticks(records, symbol)
+		if len(ticks) == 0 {
+			return nil, fmt.Errorf("replay file %s has no ticks of %s", cfg.File, cmp.Or(symbol, "any symbol"))
+		}
+
+		quotes := make([]float64, len(ticks))
+		for i, t := range ticks {
+			quotes[i] = t.Quote
+		}
+
+		return &replay{quotes: quotes, noise: cfg.Noise}, nil
+	default:
+		return nil, fmt.Errorf("unknown model %q", cfg.Model)
+	}
+}
+
+// randomWalk moves the price by normally distributed steps.
+type randomWalk struct {
+	price float64
+	step  float64
+}
+
+func (m *randomWalk) next(rng *rand.Rand) float64 {
+	m.price = math.Max(m.price+rng.NormFloat64()*m.step, m.step)
+
+	return m.price
+}
+
+// gbm is a geometric Brownian motion.
+type gbm struct {
+	price      float64
+	drift      float64
+	volatility float64
+	dt         float64
+}
+
+func (m *gbm) next(rng *rand.Rand) float64 {
+	m.price *= math.Exp(m.logReturn(rng))
+
+	return m.price
+}
+
+func (m *gbm) logReturn(rng *rand.Rand) float64 {
+	return (m.drift-m.volatility*m.volatility/2)*m.dt + m.volatility*math.Sqrt(m.dt)*rng.NormFloat64()
+}
+
+// jumpDiffusion is a geometric Brownian motion with log-normal jumps arriving as a Poisson process.
+type jumpDiffusion struct {
+	gbm
+	intensity float64
+	mean      float64
+	stdDev    float64
+}
+
+func (m *jumpDiffusion) next(rng *rand.Rand) float64 {
+	r := m.logReturn(rng)
+
+	for range poisson(rng, m.intensity*m.dt) {
+		r += m.mean + m.stdDev*rng.NormFloat64()
+	}
+
+	m.price *= math.Exp(r)
+
+	return m.price
+}
+
+// poisson draws a Poisson distributed number with the mean lambda using Knuth's algorithm,
+// which is efficient for the small means of per tick jump counts.
+func poisson(rng *rand.Rand, lambda float64) int {
+	limit := math.Exp(-lambda)
+	p := rng.Float64()
+	n := 0
+
+	for p > limit {
+		p *= rng.Float64()
+		n++
+	}
+
+	return n
+}
+
+// replay loops over recorded quotes adding relative noise to them.
+type replay struct {
+	quotes []float64
+	idx    int
+	noise  float64
+}
+
+func (m *replay) next(rng *rand.Rand) float64 {
+	q := m.quotes[m.idx%len(m.quotes)]
+	m.idx++
+
+	return q * (1 + m.noise*rng.NormFloat64())
+}
@@ -0,0 +1,51 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/chatops"
+)
+
+type Bot struct {
+	client *Client
+	offset int
+}
+
+// NewBot creates a chat command bot receiving commands through long polling of the Bot API.
+func NewBot(client *Client) *Bot {
+	return &Bot{client: client}
+}
+
+// Commands long-polls for new messages and returns those which look like bot commands.
+// Received updates are acknowledged on the next call, so each command is delivered once.
+// Returns an error if polling fails.
+func (b *Bot) Commands(ctx context.Context) ([]chatops.Command, error) {
+	updates, err := b.client.GetUpdates(ctx, b.offset)
+	if err != nil {
+		return nil, err
+	}
+
+	cmds := make([]chatops.Command, 0, len(updates))
+
+	for _, u := range updates {
+		b.offset = max(b.offset, u.UpdateID+1)
+
+		if u.Message == nil || !strings.HasPrefix(u.Message.Text, "/") {
+			continue
+		}
+
+		cmds = append(cmds, chatops.Command{
+			ChatID: u.Message.Chat.ID,
+			Text:   u.Message.Text,
+		})
+	}
+
+	return cmds, nil
+}
+
+// Reply sends a plain text reply to the chat.
+// Returns an error if the Bot API request fails.
+func (b *Bot) Reply(ctx context.Context, chatID int64, text string) error {
+	return b.client.SendMessage(ctx, Message{ChatID: chatID, Text: text})
+}
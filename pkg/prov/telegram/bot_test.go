@@ -0,0 +1,130 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/chatops"
+	"github.com/ksysoev/deriv-bot/pkg/core/executor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTelegram is a stand-in for the Bot API serving queued updates and recording sent messages.
+type fakeTelegram struct {
+	updates []Update
+	sent    []Message
+	offsets []int
+	mu      sync.Mutex
+}
+
+func (f *fakeTelegram) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var result any
+
+	switch r.URL.Path {
+	case "/bottest-token/getUpdates":
+		var req struct {
+			Offset int `json:"offset"`
+		}
+
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		f.offsets = append(f.offsets, req.Offset)
+
+		pending := make([]Update, 0, len(f.updates))
+
+		for _, u := range f.updates {
+			if u.UpdateID >= req.Offset {
+				pending = append(pending, u)
+			}
+		}
+
+		result = pending
+	case "/bottest-token/sendMessage":
+		var msg struct {
+			Text   string `json:"text"`
+			ChatID int64  `json:"chat_id"`
+		}
+
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		f.sent = append(f.sent, Message{ChatID: msg.ChatID, Text: msg.Text})
+		result = true
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"ok":false,"description":"Not Found"}`))
+
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": result})
+}
+
+func (f *fakeTelegram) sentMessages() []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Message(nil), f.sent...)
+}
+
+type stubExecutor struct {
+	paused []string
+}
+
+func (e *stubExecutor) Strategies() []executor.StrategyStatus {
+	return []executor.StrategyStatus{{Name: "r100-long", Symbol: "R_100", Type: executor.StrategyTypeBuy, State: executor.StrategyStateWaitingToOpen}}
+}
+
+func (e *stubExecutor) PauseStrategy(name string) error {
+	e.paused = append(e.paused, name)
+	return nil
+}
+
+func (e *stubExecutor) ResumeStrategy(string) error              { return nil }
+func (e *stubExecutor) Positions() []executor.OpenPosition       { return nil }
+func (e *stubExecutor) ClosePosition(context.Context, int) error { return nil }
+func (e *stubExecutor) Trades(time.Time) []executor.ClosedTrade  { return nil }
+
+func TestBot_ChatCommands(t *testing.T) {
+	fake := &fakeTelegram{
+		updates: []Update{
+			{UpdateID: 10, Message: &IncomingMessage{Chat: Chat{ID: 1}, Text: "/status"}},
+			{UpdateID: 11, Message: &IncomingMessage{Chat: Chat{ID: 2}, Text: "/pause r100-long"}},
+			{UpdateID: 12, Message: &IncomingMessage{Chat: Chat{ID: 1}, Text: "hello"}},
+			{UpdateID: 13, Message: &IncomingMessage{Chat: Chat{ID: 1}, Text: "/pause@deriv_bot r100-long"}},
+		},
+	}
+
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	exec := &stubExecutor{}
+	svc := chatops.New(exec, NewBot(New(srv.URL, "test-token")), []int64{1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- svc.Run(ctx) }()
+
+	require.Eventually(t, func() bool { return len(fake.sentMessages()) == 2 }, time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+
+	sent := fake.sentMessages()
+	assert.Equal(t, Message{ChatID: int64(1), Text: "r100-long (buy R_100): waiting_to_open"}, sent[0])
+	assert.Equal(t, Message{ChatID: int64(1), Text: "Strategy r100-long paused"}, sent[1])
+	assert.Equal(t, []string{"r100-long"}, exec.paused, "commands from chats outside the allowlist are ignored")
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	assert.Equal(t, 0, fake.offsets[0])
+	assert.Equal(t, 14, fake.offsets[1], "received updates are acknowledged")
+}
@@ -0,0 +1,133 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultURL      = "https://api.telegram.org"
+	requestTimeout  = 10 * time.Second
+	pollTimeout     = 30 * time.Second
+	maxResponseSize = 1 << 20
+)
+
+type Client struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+type Message struct {
+	ChatID    any    `json:"chat_id"`
+	Text      string `json:"text"`
+	ParseMode string `json:"parse_mode,omitempty"`
+}
+
+type Update struct {
+	Message  *IncomingMessage `json:"message,omitempty"`
+	UpdateID int              `json:"update_id"`
+}
+
+type IncomingMessage struct {
+	Text string `json:"text"`
+	Chat Chat   `json:"chat"`
+}
+
+type Chat struct {
+	ID int64 `json:"id"`
+}
+
+type response struct {
+	Result      json.RawMessage `json:"result"`
+	Description string          `json:"description"`
+	OK          bool            `json:"ok"`
+}
+
+// New creates a Telegram Bot API client.
+// baseURL overrides the Bot API address, it defaults to the public Telegram API when empty.
+// token is the bot token issued by BotFather.
+func New(baseURL, token string) *Client {
+	if baseURL == "" {
+		baseURL = defaultURL
+	}
+
+	return &Client{
+		client: &http.Client{Timeout: pollTimeout + requestTimeout},
+		url:    strings.TrimRight(baseURL, "/"),
+		token:  token,
+	}
+}
+
+// SendMessage posts a message to a chat.
+// Returns an error if the Bot API request fails or is rejected.
+func (c *Client) SendMessage(ctx context.Context, msg Message) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	return c.call(ctx, "sendMessage", msg, nil)
+}
+
+// GetUpdates long-polls the Bot API for updates with ID greater or equal to offset.
+// Returns the received updates, which may be empty if nothing arrived during the poll timeout,
+// and an error if the request fails.
+func (c *Client) GetUpdates(ctx context.Context, offset int) ([]Update, error) {
+	var updates []Update
+
+	err := c.call(ctx, "getUpdates", map[string]any{
+		"offset":          offset,
+		"timeout":         int(pollTimeout.Seconds()),
+		"allowed_updates": []string{"message"},
+	}, &updates)
+	if err != nil {
+		return nil, err
+	}
+
+	return updates, nil
+}
+
+func (c *Client) call(ctx context.Context, method string, params, result any) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s request: %w", method, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+"/bot"+c.token+"/"+method, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", method, err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send %s request: %w", method, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	var res response
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&res); err != nil {
+		return fmt.Errorf("failed to decode %s response with status %d: %w", method, resp.StatusCode, err)
+	}
+
+	if !res.OK {
+		return fmt.Errorf("telegram %s failed with status %d: %s", method, resp.StatusCode, res.Description)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(res.Result, result); err != nil {
+		return fmt.Errorf("failed to decode %s result: %w", method, err)
+	}
+
+	return nil
+}
@@ -7,7 +7,7 @@ import (
 )
 
 type SubscriptionManager struct {
-	subs map[string]<-chan signal.Tick
+	subs map[string]*signal.Subscription
 	mu   sync.Mutex
 }
 
@@ -16,15 +16,15 @@ type SubscriptionManager struct {
 // Returns a pointer to a SubscriptionManager configured with an empty subscription map.
 func New() *SubscriptionManager {
 	return &SubscriptionManager{
-		subs: make(map[string]<-chan signal.Tick),
+		subs: make(map[string]*signal.Subscription),
 	}
 }
 
-// GetMarketSubscription retrieves the subscription channel for a specific market symbol if it exists.
+// GetMarketSubscription retrieves the subscription for a specific market symbol if it exists.
 // It locks the subscription manager during execution to ensure thread safety.
 // Takes symbol, the market symbol to search for in the subscription map.
-// Returns a channel of signal.Tick for the specified market symbol if a subscription exists, otherwise returns false.
-func (s *SubscriptionManager) GetMarketSubscription(symbol string) (<-chan signal.Tick, bool) {
+// Returns the subscription of the specified market symbol if it exists, otherwise returns false.
+func (s *SubscriptionManager) GetMarketSubscription(symbol string) (*signal.Subscription, bool) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -33,12 +33,24 @@ func (s *SubscriptionManager) GetMarketSubscription(symbol string) (<-chan signa
 	return sub, ok
 }
 
-// SetMarketSubscription registers a subscription channel for a given market symbol, overriding any existing subscription.
+// SetMarketSubscription registers a subscription for a given market symbol, overriding any existing subscription.
 // It safely updates the internal subscription map while ensuring thread safety using a mutex.
-// Takes symbol, the market symbol used as a key, and sub, the channel of signal.Tick used for subscription updates.
-func (s *SubscriptionManager) SetMarketSubscription(symbol string, sub <-chan signal.Tick) {
+// Takes symbol, the market symbol used as a key, and sub, the subscription delivering ticks of the symbol.
+func (s *SubscriptionManager) SetMarketSubscription(symbol string, sub *signal.Subscription) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	s.subs[symbol] = sub
 }
+
+// RemoveMarketSubscription removes the subscription of a given market symbol once it has ended.
+// A newer subscription registered for the symbol in the meantime is kept.
+// Takes symbol, the market symbol used as a key, and sub, the subscription which ended.
+func (s *SubscriptionManager) RemoveMarketSubscription(symbol string, sub *signal.Subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.subs[symbol] == sub {
+		delete(s.subs, symbol)
+	}
+}
@@ -0,0 +1,12 @@
+package tickfile
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var droppedTicks = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "deriv_bot",
+	Name:      "recorder_dropped_ticks_total",
+	Help:      "Number of ticks not recorded because the recorder queue was full per symbol.",
+}, []string{"symbol"})
@@ -0,0 +1,199 @@
+package tickfile
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+)
+
+const (
+	defaultFlushInterval = 5 * time.Second
+	recordBufferSize     = 4096
+	dayLayout            = "2006-01-02"
+)
+
+// RecorderConfig enables tick recording when Dir is set.
+// Ticks are written to Dir/<symbol>/<YYYY-MM-DD>.csv.gz by the UTC day of the tick,
+// and buffered data is flushed to disk every FlushInterval.
+type RecorderConfig struct {
+	Dir           string        `mapstructure:"dir"`
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+}
+
+type record struct {
+	tick   signal.Tick
+	symbol string
+}
+
+// dayFile is the open file of a symbol for a single day.
+type dayFile struct {
+	f   *os.File
+	gz  *gzip.Writer
+	w   *Writer
+	day string
+}
+
+// Recorder appends ticks to rotating gzip compressed files per symbol per day.
+type Recorder struct {
+	records chan record
+	files   map[string]*dayFile
+	cfg     RecorderConfig
+}
+
+// NewRecorder creates a Recorder writing to the directory configured in cfg.
+// Returns the initialized Recorder.
+func NewRecorder(cfg RecorderConfig) *Recorder {
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultFlushInterval
+	}
+
+	return &Recorder{
+		cfg:     cfg,
+		records: make(chan record, recordBufferSize),
+		files:   make(map[string]*dayFile),
+	}
+}
+
+// Record queues tick of symbol for writing. It never blocks the tick stream:
+// when the disk can't keep up and the queue is full, the tick is dropped and counted in the dropped ticks metric.
+func (r *Recorder) Record(symbol string, tick signal.Tick) {
+	select {
+	case r.records <- record{symbol: symbol, tick: tick}:
+	default:
+		droppedTicks.WithLabelValues(symbol).Inc()
+	}
+}
+
+// Run writes queued ticks to disk until ctx is cancelled, then writes the remaining queued ticks and closes all files.
+// Write failures are logged and don't stop recording.
+// Returns an error if files can't be closed cleanly.
+func (r *Recorder) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			for {
+				select {
+				case rec := <-r.records:
+					r.write(rec)
+				default:
+					return r.closeAll()
+				}
+			}
+		case rec := <-r.records:
+			r.write(rec)
+		case <-ticker.C:
+			r.flushAll()
+		}
+	}
+}
+
+func (r *Recorder) write(rec record) {
+	df, err := r.file(rec.symbol, rec.tick.Time.UTC().Format(dayLayout))
+	if err != nil {
+		slog.Error("Failed to open tick file", slog.String("symbol", rec.symbol), slog.Any("error", err))
+		return
+	}
+
+	if err := df.w.Write(rec.tick); err != nil {
+		slog.Error("Failed to record tick", slog.String("symbol", rec.symbol), slog.Any("error", err))
+	}
+}
+
+// file returns the open file of symbol for day, rotating the file of the previous day.
+func (r *Recorder) file(symbol, day string) (*dayFile, error) {
+	if df, ok := r.files[symbol]; ok {
+		if df.day == day {
+			return df, nil
+		}
+
+		delete(r.files, symbol)
+
+		if err := df.close(); err != nil {
+			slog.Error("Failed to close tick file", slog.String("symbol", symbol), slog.Any("error", err))
+		}
+	}
+
+	df, err := openDayFile(filepath.Join(r.cfg.Dir, symbol), symbol, day)
+	if err != nil {
+		return nil, err
+	}
+
+	r.files[symbol] = df
+
+	return df, nil
+}
+
+func (r *Recorder) flushAll() {
+	for symbol, df := range r.files {
+		if err := df.flush(); err != nil {
+			slog.Error("Failed to flush tick file", slog.String("symbol", symbol), slog.Any("error", err))
+		}
+	}
+}
+
+func (r *Recorder) closeAll() error {
+	var errs []error
+
+	for symbol, df := range r.files {
+		if err := df.close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close tick file of %s: %w", symbol, err))
+		}
+
+		delete(r.files, symbol)
+	}
+
+	return errors.Join(errs...)
+}
+
+// openDayFile opens the file of day in dir for appending. A new gzip member is started,
+// so files written before a restart stay readable, and the header is written only to new files.
+func openDayFile(dir, symbol, day string) (*dayFile, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	f, err := os.OpenFile(filepath.Join(dir, day+".csv.gz"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	gz := gzip.NewWriter(f)
+	df := &dayFile{f: f, gz: gz, w: NewWriter(gz, symbol), day: day}
+
+	if info.Size() == 0 {
+		if err := df.w.WriteHeader(); err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("failed to write header: %w", err)
+		}
+	}
+
+	return df, nil
+}
+
+func (df *dayFile) flush() error {
+	if err := df.w.Flush(); err != nil {
+		return err
+	}
+
+	return df.gz.Flush()
+}
+
+func (df *dayFile) close() error {
+	return errors.Join(df.w.Flush(), df.gz.Close(), df.f.Close())
+}
@@ -0,0 +1,63 @@
+package tickfile
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runRecorder(t *testing.T, dir string, ticks map[string][]signal.Tick) {
+	t.Helper()
+
+	rec := NewRecorder(RecorderConfig{Dir: dir})
+
+	for symbol, tt := range ticks {
+		for _, tick := range tt {
+			rec.Record(symbol, tick)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.NoError(t, rec.Run(ctx))
+}
+
+func TestRecorder(t *testing.T) {
+	dir := t.TempDir()
+	day1 := time.Date(2026, 10, 19, 23, 59, 59, 0, time.UTC)
+	day2 := day1.Add(2 * time.Second)
+
+	ticks := []signal.Tick{
+		{Time: day1, Quote: 1234.567, Ask: 1234.6, Bid: 1234.5},
+		{Time: day2, Quote: 0.1 + 0.2, Ask: 0.31, Bid: 0.29},
+	}
+
+	runRecorder(t, dir, map[string][]signal.Tick{"R_100": ticks, "R_50": ticks[:1]})
+
+	// A restart appends to the file of the day.
+	runRecorder(t, dir, map[string][]signal.Tick{"R_100": {{Time: day2.Add(time.Second), Quote: 2, Ask: 2, Bid: 2}}})
+
+	got, err := ReadFile(filepath.Join(dir, "R_100", "2026-10-19.csv.gz"))
+	require.NoError(t, err)
+	assert.Equal(t, []Record{{
+		Symbol: "R_100",
+		Tick:   signal.Tick{Time: time.UnixMilli(day1.UnixMilli()), Quote: 1234.567, Ask: 1234.6, Bid: 1234.5},
+	}}, got)
+
+	got, err = ReadFile(filepath.Join(dir, "R_100", "2026-10-20.csv.gz"))
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, 0.1+0.2, got[0].Tick.Quote)
+	assert.Equal(t, 2.0, got[1].Tick.Quote)
+
+	got, err = ReadFile(filepath.Join(dir, "R_50", "2026-10-19.csv.gz"))
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "R_50", got[0].Symbol)
+}
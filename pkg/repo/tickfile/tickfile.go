@@ -0,0 +1,179 @@
+// Package tickfile reads and writes market ticks stored as CSV files.
+// Each row holds the tick time as Unix milliseconds, the quote and optionally the ask and bid prices and the symbol.
+// A header row is optional, files with the .gz extension are gzip compressed and may consist of several gzip members,
+// so they can be appended to.
+package tickfile
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+)
+
+// Header is the column names row of tick files.
+var Header = []string{"time", "quote", "ask", "bid", "symbol"}
+
+// Record is a tick read from a tick file with the symbol of its row, empty for rows without one.
+type Record struct {
+	Symbol string
+	Tick   signal.Tick
+}
+
+// ReadFile reads all records from the file at path.
+// Returns an error if the file can't be opened or has malformed rows.
+func ReadFile(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tick file: %w", err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	var r io.Reader = f
+
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tick file %s: %w", path, err)
+		}
+
+		defer func() { _ = gz.Close() }()
+
+		r = gz
+	}
+
+	records, err := Read(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tick file %s: %w", path, err)
+	}
+
+	return records, nil
+}
+
+// Read reads all records from CSV data in r. Data cut short, e.g. a compressed file not closed after a crash,
+// is read up to the last complete flush.
+// Returns an error if a row is malformed.
+func Read(r io.Reader) ([]Record, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	var records []Record
+
+	for line := 1; ; line++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return records, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if line == 1 && rec[0] == Header[0] {
+			continue
+		}
+
+		record, err := parseRecord(rec)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		records = append(records, record)
+	}
+}
+
+func parseRecord(rec []string) (Record, error) {
+	if len(rec) < 2 || len(rec) > len(Header) {
+		return Record{}, fmt.Errorf("expected 2 to %d columns, got %d", len(Header), len(rec))
+	}
+
+	ms, err := strconv.ParseInt(rec[0], 10, 64)
+	if err != nil {
+		return Record{}, fmt.Errorf("invalid time: %w", err)
+	}
+
+	prices := make([]float64, 3)
+
+	for i, v := range rec[1:min(len(rec), 4)] {
+		if prices[i], err = strconv.ParseFloat(v, 64); err != nil {
+			return Record{}, fmt.Errorf("invalid %s: %w", Header[i+1], err)
+		}
+	}
+
+	tick := signal.Tick{Time: time.UnixMilli(ms), Quote: prices[0], Ask: prices[1], Bid: prices[2]}
+
+	if len(rec) == 2 {
+		tick.Ask, tick.Bid = tick.Quote, tick.Quote
+	}
+
+	record := Record{Tick: tick}
+
+	if len(rec) == len(Header) {
+		record.Symbol = rec[4]
+	}
+
+	return record, nil
+}
+
+// Ticks returns the ticks of records recorded for symbol, or of all records when symbol is empty.
+// Records without a symbol are taken as ticks of any symbol.
+func Ticks(records []Record, symbol string) []signal.Tick {
+	ticks := make([]signal.Tick, 0, len(records))
+
+	for _, rec := range records {
+		if symbol == "" || rec.Symbol == "" || rec.Symbol == symbol {
+			ticks = append(ticks, rec.Tick)
+		}
+	}
+
+	return ticks
+}
+
+// Writer writes ticks of a symbol as CSV rows.
+type Writer struct {
+	cw     *csv.Writer
+	symbol string
+	rec    []string
+}
+
+// NewWriter creates a Writer appending rows for symbol to w.
+func NewWriter(w io.Writer, symbol string) *Writer {
+	return &Writer{
+		cw:     csv.NewWriter(w),
+		symbol: symbol,
+		rec:    make([]string, len(Header)),
+	}
+}
+
+// WriteHeader writes the column names row.
+func (w *Writer) WriteHeader() error {
+	return w.cw.Write(Header)
+}
+
+// Write writes tick as a row. Prices are formatted with the shortest representation that reads back exactly.
+func (w *Writer) Write(tick signal.Tick) error {
+	w.rec[0] = strconv.FormatInt(tick.Time.UnixMilli(), 10)
+	w.rec[1] = strconv.FormatFloat(tick.Quote, 'f', -1, 64)
+	w.rec[2] = strconv.FormatFloat(tick.Ask, 'f', -1, 64)
+	w.rec[3] = strconv.FormatFloat(tick.Bid, 'f', -1, 64)
+	w.rec[4] = w.symbol
+
+	return w.cw.Write(w.rec)
+}
+
+// Flush writes buffered rows to the underlying writer.
+// Returns an error if a previous write or the flush failed.
+func (w *Writer) Flush() error {
+	w.cw.Flush()
+
+	return w.cw.Error()
+}
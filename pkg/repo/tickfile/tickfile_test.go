@@ -0,0 +1,125 @@
+package tickfile
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/deriv-bot/pkg/core/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+		want    []Record
+	}{
+		{
+			name: "with header",
+			data: "time,quote,ask,bid\n1000,1.5,1.6,1.4\n",
+			want: []Record{{Tick: signal.Tick{Time: time.UnixMilli(1000), Quote: 1.5, Ask: 1.6, Bid: 1.4}}},
+		},
+		{
+			name: "with symbol",
+			data: "time,quote,ask,bid,symbol\n1000,1.5,1.6,1.4,R_100\n",
+			want: []Record{{Symbol: "R_100", Tick: signal.Tick{Time: time.UnixMilli(1000), Quote: 1.5, Ask: 1.6, Bid: 1.4}}},
+		},
+		{
+			name: "quotes only",
+			data: "1000,1.5\n2000,2.5\n",
+			want: []Record{
+				{Tick: signal.Tick{Time: time.UnixMilli(1000), Quote: 1.5, Ask: 1.5, Bid: 1.5}},
+				{Tick: signal.Tick{Time: time.UnixMilli(2000), Quote: 2.5, Ask: 2.5, Bid: 2.5}},
+			},
+		},
+		{
+			name:    "invalid quote",
+			data:    "1000,abc\n",
+			wantErr: "line 1: invalid quote",
+		},
+		{
+			name:    "too many columns",
+			data:    "1000,1,2,3,R_100,5\n",
+			wantErr: "expected 2 to 5 columns, got 6",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := Read(strings.NewReader(tt.data))
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, records)
+		})
+	}
+}
+
+func TestReadFile_Gzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ticks.csv.gz")
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+
+	gz := gzip.NewWriter(f)
+	_, err = gz.Write([]byte("1000,1.5,1.6,1.4\n"))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	require.NoError(t, f.Close())
+
+	records, err := ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []Record{{Tick: signal.Tick{Time: time.UnixMilli(1000), Quote: 1.5, Ask: 1.6, Bid: 1.4}}}, records)
+}
+
+func TestWriter_RoundTrip(t *testing.T) {
+	ticks := []signal.Tick{
+		{Time: time.UnixMilli(1000), Quote: 1234.56, Ask: 1234.57, Bid: 1234.55},
+		{Time: time.UnixMilli(2000), Quote: 0.1, Ask: 0.2, Bid: 0.3},
+	}
+
+	var buf strings.Builder
+
+	w := NewWriter(&buf, "R_100")
+	require.NoError(t, w.WriteHeader())
+
+	for _, tick := range ticks {
+		require.NoError(t, w.Write(tick))
+	}
+
+	require.NoError(t, w.Flush())
+
+	records, err := Read(strings.NewReader(buf.String()))
+	require.NoError(t, err)
+	require.Len(t, records, len(ticks))
+
+	for i, rec := range records {
+		assert.Equal(t, "R_100", rec.Symbol)
+		assert.True(t, ticks[i].Time.Equal(rec.Tick.Time))
+		assert.InDelta(t, ticks[i].Quote, rec.Tick.Quote, 0)
+		assert.InDelta(t, ticks[i].Ask, rec.Tick.Ask, 0)
+		assert.InDelta(t, ticks[i].Bid, rec.Tick.Bid, 0)
+	}
+}
+
+func TestTicks(t *testing.T) {
+	records := []Record{
+		{Symbol: "R_100", Tick: signal.Tick{Quote: 1}},
+		{Symbol: "R_50", Tick: signal.Tick{Quote: 2}},
+		{Tick: signal.Tick{Quote: 3}},
+	}
+
+	assert.Equal(t, []signal.Tick{{Quote: 1}, {Quote: 3}}, Ticks(records, "R_100"))
+	assert.Equal(t, []signal.Tick{{Quote: 3}}, Ticks(records, "R_10"))
+	assert.Len(t, Ticks(records, ""), 3)
+}